@@ -4,13 +4,44 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
+	"time"
 
+	"github.com/dxtym/bankrupt/authz"
 	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pagination"
 	"github.com/dxtym/bankrupt/token"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/valid"
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
 )
 
+type accountResponse struct {
+	ID        int64       `json:"id"`
+	Owner     string      `json:"owner"`
+	Balance   utils.Money `json:"balance"`
+	Currency  string      `json:"currency"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+func newAccountResponse(account db.Account) accountResponse {
+	return accountResponse{
+		ID:        account.ID,
+		Owner:     account.Owner,
+		Balance:   account.BalanceMoney(),
+		Currency:  account.Currency,
+		CreatedAt: account.CreatedAt,
+	}
+}
+
+func newAccountResponses(accounts []db.Account) []accountResponse {
+	res := make([]accountResponse, len(accounts))
+	for i, account := range accounts {
+		res[i] = newAccountResponse(account)
+	}
+	return res
+}
+
 type createAccountRequest struct {
 	Currency string `json:"currency" binding:"required,currency"`
 }
@@ -24,14 +55,28 @@ func (s *Server) createAccount(ctx *gin.Context) {
 
 	// add auth payload (not from request)
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	arg := db.CreateAccountParams{
-		Owner:    authPayload.Username,
-		Balance:  0,
-		Currency: req.Currency,
+
+	idempotency, err := getIdempotency(ctx, authPayload.Username, req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	arg := db.CreateAccountTxParams{
+		CreateAccountParams: db.CreateAccountParams{
+			Owner:    authPayload.Username,
+			Balance:  0,
+			Currency: req.Currency,
+		},
+		Idempotency: idempotency,
 	}
 
-	account, err := s.store.CreateAccount(ctx, arg)
+	txResult, err := s.store.CreateAccountTx(ctx, arg)
 	if err != nil {
+		if errors.Is(err, db.ErrIdempotencyKeyReused) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
 			case "foreign_key_violation", "invalid_foreign_key":
@@ -43,7 +88,7 @@ func (s *Server) createAccount(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(txResult.Account))
 }
 
 type getAccountRequest struct {
@@ -69,18 +114,23 @@ func (s *Server) getAccount(ctx *gin.Context) {
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if authPayload.Username != account.Owner {
-		err := errors.New("account has limited priveliges")
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, err)
+	if err := s.policy.AuthorizeOwner(authPayload, authz.ReadAccount, account.Owner); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, newAccountResponse(account))
 }
 
 type listAccountRequest struct {
-	PageId   int32 `form:"page_id" binding:"required,min=1"`
-	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+	PageId    int32  `form:"page_id" binding:"omitempty,min=1"` // deprecated, use page_token
+	PageSize  int32  `form:"page_size" binding:"min=0"`
+	PageToken string `form:"page_token" binding:"excluded_with=PageId"`
+}
+
+type listAccountResponse struct {
+	Accounts      []accountResponse `json:"accounts"`
+	NextPageToken string            `json:"next_page_token"`
 }
 
 func (s *Server) listAccount(ctx *gin.Context) {
@@ -91,6 +141,17 @@ func (s *Server) listAccount(ctx *gin.Context) {
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if req.PageId == 0 {
+		s.listAccountPage(ctx, authPayload, req)
+		return
+	}
+
+	// offset paging is kept for old clients, they always send page_id
+	if err := valid.ValidatePageSize(req.PageSize); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	arg := db.ListAccountsParams{
 		Owner:  authPayload.Username,
 		Limit:  req.PageSize,
@@ -108,5 +169,41 @@ func (s *Server) listAccount(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, accounts)
+	ctx.JSON(http.StatusOK, newAccountResponses(accounts))
+}
+
+func (s *Server) listAccountPage(ctx *gin.Context, authPayload *token.Payload, req listAccountRequest) {
+	pageSize, err := pagination.PageSize(req.PageSize, pagination.DefaultPageSize, pagination.MaxPageSize)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	scope := "accounts:" + authPayload.Username
+	cursor, err := s.pageTokens.Decode(req.PageToken, scope)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	accounts, err := s.store.ListAccountsPage(ctx, db.ListAccountsPageParams{
+		Owner:          authPayload.Username,
+		AfterCreatedAt: cursor.CreatedAt,
+		AfterID:        cursor.ID,
+		LimitCount:     pageSize + 1,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	accounts, next := pagination.Trim(accounts, pageSize, accountCursor)
+	ctx.JSON(http.StatusOK, listAccountResponse{
+		Accounts:      newAccountResponses(accounts),
+		NextPageToken: s.pageTokens.Encode(next, scope),
+	})
+}
+
+func accountCursor(account db.Account) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: account.CreatedAt, ID: account.ID}
 }
@@ -35,7 +35,7 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "OK",
 			accountId: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -52,7 +52,7 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "UnAuthorized",
 			accountId: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, "unauthorized_user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, "unauthorized_user", utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -64,6 +64,38 @@ func TestGetAccountAPI(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name:      "BankerReadsOtherAccount",
+			accountId: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, "banker", utils.BankerRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatch(t, recorder.Body, account)
+			},
+		},
+		{
+			name:      "SystemRoleForbidden",
+			accountId: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.SystemRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
 		{
 			name:      "NoAuthorization",
 			accountId: account.ID,
@@ -82,7 +114,7 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "NotFound",
 			accountId: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -98,7 +130,7 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "InternalError",
 			accountId: account.ID,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -114,7 +146,7 @@ func TestGetAccountAPI(t *testing.T) {
 			name:      "InvalidId",
 			accountId: 0,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -170,17 +202,19 @@ func TestCreateAccountAPI(t *testing.T) {
 				"currency": account.Currency,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
-				arg := db.CreateAccountParams{
-					Owner:    account.Owner,
-					Currency: account.Currency,
-					Balance:  0,
+				arg := db.CreateAccountTxParams{
+					CreateAccountParams: db.CreateAccountParams{
+						Owner:    account.Owner,
+						Currency: account.Currency,
+						Balance:  0,
+					},
 				}
 				s.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Eq(arg)).
-					Return(account, nil).AnyTimes()
+					CreateAccountTx(gomock.Any(), gomock.Eq(arg)).
+					Return(db.CreateAccountTxResult{Account: account}, nil).AnyTimes()
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -197,7 +231,7 @@ func TestCreateAccountAPI(t *testing.T) {
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+					CreateAccountTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -210,12 +244,12 @@ func TestCreateAccountAPI(t *testing.T) {
 				"currency": account.Currency,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Any()).
-					Return(db.Account{}, sql.ErrConnDone).
+					CreateAccountTx(gomock.Any(), gomock.Any()).
+					Return(db.CreateAccountTxResult{}, sql.ErrConnDone).
 					AnyTimes()
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -229,15 +263,39 @@ func TestCreateAccountAPI(t *testing.T) {
 				"currency": "XYZ",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
-				s.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+				s.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name: "IdempotencyKeyReused",
+			body: gin.H{
+				"owner":    account.Owner,
+				"currency": account.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
+				request.Header.Set(idempotencyKeyHeader, utils.RandomString(16))
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateAccountTxParams) (db.CreateAccountTxResult, error) {
+						require.NotNil(t, arg.Idempotency)
+						require.Equal(t, user.Username, arg.Idempotency.Username)
+						return db.CreateAccountTxResult{}, db.ErrIdempotencyKeyReused
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
 	}
 
 	for i := range testCases {
@@ -280,6 +338,7 @@ func TestListAccountsAPI(t *testing.T) {
 	type Query struct {
 		pageId     int
 		pageOffset int
+		pageToken  string
 	}
 
 	testCases := []struct {
@@ -296,7 +355,7 @@ func TestListAccountsAPI(t *testing.T) {
 				pageOffset: n,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				arg := db.ListAccountsParams{
@@ -338,7 +397,7 @@ func TestListAccountsAPI(t *testing.T) {
 				pageOffset: n,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				arg := db.ListAccountsParams{
@@ -362,7 +421,7 @@ func TestListAccountsAPI(t *testing.T) {
 				pageOffset: n,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -381,7 +440,7 @@ func TestListAccountsAPI(t *testing.T) {
 				pageOffset: n,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -399,7 +458,7 @@ func TestListAccountsAPI(t *testing.T) {
 				pageOffset: 1 << 18,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -410,6 +469,98 @@ func TestListAccountsAPI(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name: "PageToken",
+			query: Query{
+				pageOffset: n - 1,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				arg := db.ListAccountsPageParams{
+					Owner:      user.Username,
+					LimitCount: int32(n),
+				}
+				s.EXPECT().
+					ListAccountsPage(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(accounts, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listAccountResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, newAccountResponses(accounts[:n-1]), rsp.Accounts)
+				require.NotEmpty(t, rsp.NextPageToken)
+			},
+		},
+		{
+			name: "LastPage",
+			query: Query{
+				pageOffset: n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					ListAccountsPage(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(accounts, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listAccountResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, newAccountResponses(accounts), rsp.Accounts)
+				require.Empty(t, rsp.NextPageToken)
+			},
+		},
+		{
+			name: "InvalidPageToken",
+			query: Query{
+				pageOffset: n,
+				pageToken:  "invalid",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					ListAccountsPage(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PageTokenWithPageId",
+			query: Query{
+				pageId:     1,
+				pageOffset: n,
+				pageToken:  "invalid",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, user.Username, utils.DepositorRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					ListAccounts(gomock.Any(), gomock.Any()).
+					Times(0)
+				s.EXPECT().
+					ListAccountsPage(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 	}
 
 	for i := range testCases {
@@ -430,8 +581,13 @@ func TestListAccountsAPI(t *testing.T) {
 
 			// for query params
 			q := request.URL.Query()
-			q.Add("page_id", fmt.Sprintf("%d", tc.query.pageId))
+			if tc.query.pageId != 0 {
+				q.Add("page_id", fmt.Sprintf("%d", tc.query.pageId))
+			}
 			q.Add("page_size", fmt.Sprintf("%d", tc.query.pageOffset))
+			if tc.query.pageToken != "" {
+				q.Add("page_token", tc.query.pageToken)
+			}
 			request.URL.RawQuery = q.Encode()
 
 			tc.setupAuth(t, request, server.token)
@@ -442,11 +598,12 @@ func TestListAccountsAPI(t *testing.T) {
 }
 
 func randomAccount(owner string) db.Account {
+	balance := utils.RandomMoney()
 	return db.Account{
 		ID:       utils.RandomInt(1, 1000),
 		Owner:    owner,
-		Balance:  utils.RandomMoney(),
-		Currency: utils.RandomCurrency(),
+		Balance:  balance.Amount,
+		Currency: balance.Currency,
 	}
 }
 
@@ -454,18 +611,18 @@ func requireBodyMatch(t *testing.T, body *bytes.Buffer, account db.Account) {
 	data, err := io.ReadAll(body)
 	require.NoError(t, err)
 
-	var gotAccount db.Account
+	var gotAccount accountResponse
 	err = json.Unmarshal(data, &gotAccount)
 	require.NoError(t, err)
-	require.Equal(t, account, gotAccount)
+	require.Equal(t, newAccountResponse(account), gotAccount)
 }
 
 func requireBodyMatches(t *testing.T, body *bytes.Buffer, accounts []db.Account) {
 	data, err := io.ReadAll(body)
 	require.NoError(t, err)
 
-	var gotAccounts []db.Account
+	var gotAccounts []accountResponse
 	err = json.Unmarshal(data, &gotAccounts)
 	require.NoError(t, err)
-	require.Equal(t, accounts, gotAccounts)
+	require.Equal(t, newAccountResponses(accounts), gotAccounts)
 }
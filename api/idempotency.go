@@ -0,0 +1,40 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/valid"
+	"github.com/gin-gonic/gin"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// read optional idempotency key and fingerprint the request it guards
+func getIdempotency(ctx *gin.Context, username string, req any) (*db.IdempotencyParams, error) {
+	key := ctx.GetHeader(idempotencyKeyHeader)
+	if len(key) == 0 {
+		return nil, nil
+	}
+
+	if err := valid.ValidateIdempotencyKey(key); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(ctx.FullPath()))
+	hash.Write(data)
+
+	return &db.IdempotencyParams{
+		Username:    username,
+		Key:         key,
+		RequestHash: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
@@ -1,9 +1,15 @@
 package api
 
 import (
+	"context"
+	"database/sql"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/dxtym/bankrupt/currency"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -11,3 +17,30 @@ func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
 }
+
+func newTestServer(t *testing.T, s db.Store) *Server {
+	config := utils.Config{
+		TokenSymmetricKey: utils.RandomString(32),
+		TokenDuration:     time.Minute,
+	}
+
+	currencies := currency.NewRegistry(testCurrencies{}, time.Minute)
+
+	server, err := NewServer(config, s, currencies)
+	if err != nil {
+		t.Fatal("cannot create server:", err)
+	}
+
+	return server
+}
+
+// currencies seeded by migration, so tests don't hit the store
+type testCurrencies struct{}
+
+func (testCurrencies) ListCurrencies(ctx context.Context) ([]db.Currency, error) {
+	return []db.Currency{
+		{Code: utils.CAD, Exponent: 2, Enabled: true, MinTransferAmount: 1},
+		{Code: utils.EUR, Exponent: 2, Enabled: true, MinTransferAmount: 1},
+		{Code: utils.USD, Exponent: 2, Enabled: true, MinTransferAmount: 1, MaxTransferAmount: sql.NullInt64{Int64: 1000000, Valid: true}},
+	}, nil
+}
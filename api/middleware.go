@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/dxtym/bankrupt/authz"
 	"github.com/dxtym/bankrupt/token"
 	"github.com/gin-gonic/gin"
 )
@@ -40,7 +41,7 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		}
 
 		authToken := fields[1]
-		payload, err := tokenMaker.VerifyToken(authToken)
+		payload, err := tokenMaker.VerifyToken(authToken, token.AccessToken)
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
 			return
@@ -50,3 +51,15 @@ func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// check the caller's role may call the operation, runs after authMiddleware
+func authorizeMiddleware(policy authz.Policy, op authz.Operation) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		payload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if err := policy.Authorize(payload, op); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		ctx.Next()
+	}
+}
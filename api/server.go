@@ -2,10 +2,11 @@ package api
 
 import (
 	"fmt"
-	"testing"
-	"time"
 
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/currency"
 	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pagination"
 	"github.com/dxtym/bankrupt/token"
 	"github.com/dxtym/bankrupt/utils"
 	"github.com/gin-gonic/gin"
@@ -14,40 +15,32 @@ import (
 )
 
 type Server struct {
-	config utils.Config
-	store  db.Store
-	token  token.Maker
-	router *gin.Engine
+	config     utils.Config
+	store      db.Store
+	token      token.Maker
+	currencies *currency.Registry
+	pageTokens *pagination.Signer
+	policy     authz.Policy
+	router     *gin.Engine
 }
 
-func newTestServer(t *testing.T, s db.Store) *Server {
-	config := utils.Config{
-		TokenSymmetricKey: utils.RandomString(32),
-		TokenDuration:     time.Minute,
-	}
-
-	server, err := NewServer(config, s)
-	if err != nil {
-		t.Fatal("cannot create server:", err)
-	}
-
-	return server
-}
-
-func NewServer(config utils.Config, s db.Store) (*Server, error) {
+func NewServer(config utils.Config, s db.Store, currencies *currency.Registry) (*Server, error) {
 	// chose paseto maker (can choose jwt too)
 	token, err := token.NewPasetoMaker([]byte(config.TokenSymmetricKey))
 	if err != nil {
 		return nil, fmt.Errorf("cannot load token maker: %w", err)
 	}
 	server := &Server{
-		config: config,
-		store:  s,
-		token:  token,
+		config:     config,
+		store:      s,
+		token:      token,
+		currencies: currencies,
+		pageTokens: pagination.NewSigner(config.TokenSymmetricKey),
+		policy:     authz.DefaultPolicy,
 	}
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		v.RegisterValidation("currency", validCurrency)
+		v.RegisterValidation("currency", validCurrency(currencies))
 	}
 
 	server.setUpRouting()
@@ -63,11 +56,12 @@ func (s *Server) setUpRouting() {
 
 	authRoute := router.Group("/").Use(authMiddleware(s.token))
 
-	authRoute.POST("/accounts", s.createAccount)
-	authRoute.GET("/accounts/:id", s.getAccount)
-	authRoute.GET("/accounts", s.listAccount)
+	authRoute.POST("/accounts", authorizeMiddleware(s.policy, authz.CreateAccount), s.createAccount)
+	authRoute.GET("/accounts/:id", authorizeMiddleware(s.policy, authz.ReadAccount), s.getAccount)
+	authRoute.GET("/accounts", authorizeMiddleware(s.policy, authz.ListAccounts), s.listAccount)
 
-	authRoute.POST("/transfers", s.createTransfer)
+	authRoute.POST("/transfers", authorizeMiddleware(s.policy, authz.MoveMoney), s.createTransfer)
+	authRoute.POST("/transfer_batches", authorizeMiddleware(s.policy, authz.MoveMoney), s.createTransferBatch)
 
 	s.router = router
 }
@@ -6,6 +6,9 @@ import (
 	"net/http"
 	"time"
 
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/token"
+	"github.com/dxtym/bankrupt/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,8 +17,10 @@ type RenewTokenRequst struct {
 }
 
 type RenewTokenResponse struct {
-	AccessToken          string    `json:"access_token"`
-	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
 }
 
 func (s *Server) RenewToken(ctx *gin.Context) {
@@ -25,13 +30,14 @@ func (s *Server) RenewToken(ctx *gin.Context) {
 		return
 	}
 
-	payload, err := s.token.VerifyToken(req.RefreshToken)
+	payload, err := s.token.VerifyToken(req.RefreshToken, token.RefreshToken)
 	if err != nil {
 		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
 		return
 	}
 
-	session, err := s.store.GetSession(ctx, payload.Id)
+	// role may have changed since login
+	user, err := s.store.GetUser(ctx, payload.Username)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
@@ -42,36 +48,43 @@ func (s *Server) RenewToken(ctx *gin.Context) {
 		return
 	}
 
-	// check session is blocked
-	if session.IsBlocked {
-		err := errors.New("session is blocked")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
-	}
-
-	// check username
-	if session.Username != payload.Username {
-		err := errors.New("username is not match")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
-	}
+	// rotate refresh token, the new one keeps the expiry of the login
+	var refreshToken string
+	var refreshPayload *token.Payload
+	_, err = s.store.RotateSessionTx(ctx, db.RotateSessionTxParams{
+		ID:               payload.Id,
+		Username:         payload.Username,
+		RefreshTokenHash: utils.HashToken(req.RefreshToken),
+		NextSession: func(rotated db.Session) (db.CreateSessionParams, error) {
+			refreshToken, refreshPayload, err = s.token.CreateToken(user.Username, user.Role, token.RefreshToken, time.Until(rotated.ExpiresAt))
+			if err != nil {
+				return db.CreateSessionParams{}, err
+			}
 
-	// check refresh token
-	if session.RefreshToken != req.RefreshToken {
-		err := errors.New("refresh token is not valid")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
-		return
-	}
-
-	// check refresh token is expired
-	if time.Now().After(session.ExpiresAt) {
-		err := errors.New("refresh token is expired")
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return db.CreateSessionParams{
+				ID:               refreshPayload.Id,
+				RefreshTokenHash: utils.HashToken(refreshToken),
+				UserAgent:        ctx.Request.UserAgent(),
+				ClientIp:         ctx.ClientIP(),
+				ExpiresAt:        refreshPayload.ExpiredAt,
+			}, nil
+		},
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		case errors.Is(err, db.ErrSessionMismatch), errors.Is(err, db.ErrSessionBlocked),
+			errors.Is(err, db.ErrSessionExpired), errors.Is(err, db.ErrRefreshTokenReused):
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
 		return
 	}
 
 	// create token for user
-	accessToken, accessPayload, err := s.token.CreateToken(payload.Username, s.config.TokenDuration)
+	accessToken, accessPayload, err := s.token.CreateToken(user.Username, user.Role, token.AccessToken, s.config.TokenDuration)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -79,8 +92,10 @@ func (s *Server) RenewToken(ctx *gin.Context) {
 
 	// send token and user response
 	res := RenewTokenResponse{
-		AccessToken:          accessToken,
-		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshPayload.ExpiredAt,
 	}
 	ctx.JSON(http.StatusOK, res)
 }
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/dxtym/bankrupt/db/mock"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/token"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRenewTokenAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.Role = utils.DepositorRole
+
+	testCases := []struct {
+		name          string
+		tokenType     token.TokenType
+		body          func(refreshToken string) gin.H
+		buildStubs    func(s *mockdb.MockStore, payload *token.Payload, refreshToken string)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string)
+	}{
+		{
+			name:      "OK",
+			tokenType: token.RefreshToken,
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(s *mockdb.MockStore, payload *token.Payload, refreshToken string) {
+				s.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				s.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.RotateSessionTxParams) (db.RotateSessionTxResult, error) {
+						require.Equal(t, payload.Id, arg.ID)
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, utils.HashToken(refreshToken), arg.RefreshTokenHash)
+
+						rotated := db.Session{ID: payload.Id, Username: user.Username, ExpiresAt: payload.ExpiredAt}
+						next, err := arg.NextSession(rotated)
+						require.NoError(t, err)
+						require.NotEqual(t, payload.Id, next.ID)
+						require.WithinDuration(t, payload.ExpiredAt, next.ExpiresAt, time.Second)
+						return db.RotateSessionTxResult{Rotated: rotated}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res RenewTokenResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.NotEmpty(t, res.AccessToken)
+				require.NotEmpty(t, res.RefreshToken)
+				require.NotEqual(t, refreshToken, res.RefreshToken)
+			},
+		},
+		{
+			name:      "TokenReused",
+			tokenType: token.RefreshToken,
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(s *mockdb.MockStore, payload *token.Payload, refreshToken string) {
+				s.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				s.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RotateSessionTxResult{}, db.ErrRefreshTokenReused)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "SessionNotFound",
+			tokenType: token.RefreshToken,
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(s *mockdb.MockStore, payload *token.Payload, refreshToken string) {
+				s.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				s.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RotateSessionTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "AccessToken",
+			tokenType: token.AccessToken,
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubs: func(s *mockdb.MockStore, payload *token.Payload, refreshToken string) {
+				s.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+				s.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidToken",
+			tokenType: token.RefreshToken,
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": "invalid"}
+			},
+			buildStubs: func(s *mockdb.MockStore, payload *token.Payload, refreshToken string) {
+				s.EXPECT().
+					RotateSessionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, refreshToken string) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			refreshToken, payload, err := server.token.CreateToken(user.Username, user.Role, tc.tokenType, time.Hour)
+			require.NoError(t, err)
+			tc.buildStubs(store, payload, refreshToken)
+
+			data, err := json.Marshal(tc.body(refreshToken))
+			require.NoError(t, err)
+
+			url := "/tokens/renew"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, refreshToken)
+		})
+	}
+}
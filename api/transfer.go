@@ -5,17 +5,96 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/dxtym/bankrupt/authz"
 	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/limits"
 	"github.com/dxtym/bankrupt/token"
+	"github.com/dxtym/bankrupt/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+type transferResponse struct {
+	ID              int64        `json:"id"`
+	FromAccountID   int64        `json:"from_account_id"`
+	ToAccountID     int64        `json:"to_account_id"`
+	Amount          utils.Money  `json:"amount"`
+	ConvertedAmount *utils.Money `json:"converted_amount,omitempty"` // cross-currency transfers only
+	FXRate          string       `json:"fx_rate,omitempty"`
+	ReversalOf      int64        `json:"reversal_of,omitempty"`
+	ReversedAmount  utils.Money  `json:"reversed_amount"`
+	Status          string       `json:"status"`
+	FailureReason   string       `json:"failure_reason,omitempty"`
+	Memo            string       `json:"memo"`
+	CreatedAt       time.Time    `json:"created_at"`
+}
+
+func newTransferResponse(transfer db.Transfer) transferResponse {
+	res := transferResponse{
+		ID:             transfer.ID,
+		FromAccountID:  transfer.FromAccountID,
+		ToAccountID:    transfer.ToAccountID,
+		Amount:         utils.NewMoney(transfer.Amount, transfer.Currency),
+		ReversalOf:     transfer.ReversalOf.Int64,
+		ReversedAmount: utils.NewMoney(transfer.ReversedAmount, transfer.Currency),
+		Status:         transfer.Status,
+		FailureReason:  transfer.FailureReason.String,
+		Memo:           transfer.Memo,
+		CreatedAt:      transfer.CreatedAt,
+	}
+	if transfer.ToAmount.Valid {
+		converted := utils.NewMoney(transfer.ToAmount.Int64, transfer.ToCurrency.String)
+		res.ConvertedAmount = &converted
+		res.FXRate = transfer.FxRate.String
+	}
+	return res
+}
+
+type entryResponse struct {
+	ID           int64       `json:"id"`
+	AccountID    int64       `json:"account_id"`
+	Amount       utils.Money `json:"amount"`
+	BalanceAfter utils.Money `json:"balance_after"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+func newEntryResponse(entry db.Entry, currency string) entryResponse {
+	return entryResponse{
+		ID:           entry.ID,
+		AccountID:    entry.AccountID,
+		Amount:       utils.NewMoney(entry.Amount, currency),
+		BalanceAfter: utils.NewMoney(entry.BalanceAfter, currency),
+		CreatedAt:    entry.CreatedAt,
+	}
+}
+
+type transferTxResponse struct {
+	Transfer    transferResponse `json:"transfer"`
+	FromAccount accountResponse  `json:"from_account"`
+	ToAccount   accountResponse  `json:"to_account"`
+	FromEntry   entryResponse    `json:"from_entry"`
+	ToEntry     entryResponse    `json:"to_entry"`
+}
+
+func newTransferTxResponse(result db.TransferTxResult) transferTxResponse {
+	return transferTxResponse{
+		Transfer:    newTransferResponse(result.Transfer),
+		FromAccount: newAccountResponse(result.FromAccount),
+		ToAccount:   newAccountResponse(result.ToAccount),
+		FromEntry:   newEntryResponse(result.FromEntry, result.FromAccount.Currency),
+		ToEntry:     newEntryResponse(result.ToEntry, result.ToAccount.Currency),
+	}
+}
+
 type createTransferRequest struct {
 	FromAccountId int64  `json:"from_account_id" binding:"required,min=1"`
 	ToAccountId   int64  `json:"to_account_id" binding:"required,min=1"`
-	Amount        int64  `json:"amount" binding:"required,gt=8"`
+	Amount        string `json:"amount" binding:"required"` // decimal string, e.g. "12.34"
 	Currency      string `json:"currency" binding:"required,currency"`
+	FXQuoteID     string `json:"fx_quote_id" binding:"omitempty,uuid"` // required when accounts hold different currencies
+	Memo          string `json:"memo" binding:"max=140"`
 }
 
 func (s *Server) createTransfer(ctx *gin.Context) {
@@ -25,10 +104,28 @@ func (s *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
+	amount, err := utils.ParseMoney(req.Amount, req.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !amount.IsPositive() {
+		err := errors.New("amount must be greater than zero")
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if err := s.currencies.CheckTransferAmount(amount); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	arg := db.TransferTxParams{
 		FromAccountId: req.FromAccountId,
 		ToAccountId:   req.ToAccountId,
-		Amount:        req.Amount,
+		Amount:        amount,
+		Memo:          req.Memo,
 	}
 
 	fromAccount, valid := s.validateCurrency(ctx, arg.FromAccountId, req.Currency)
@@ -37,26 +134,74 @@ func (s *Server) createTransfer(ctx *gin.Context) {
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if authPayload.Username != fromAccount.Owner {
-		err := errors.New("account has limited privileges")
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, err)
+	if err := s.policy.AuthorizeOwner(authPayload, authz.MoveMoney, fromAccount.Owner); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if !s.requireVerifiedEmail(ctx, authPayload) {
+		return
+	}
+
+	// quoted transfers convert into the currency of to account
+	var toAccount db.Account
+	if req.FXQuoteID == "" {
+		toAccount, valid = s.validateCurrency(ctx, arg.ToAccountId, req.Currency)
+	} else {
+		toAccount, valid = s.loadAccount(ctx, arg.ToAccountId)
+	}
+	if !valid {
 		return
 	}
 
-	if _, valid = s.validateCurrency(ctx, arg.ToAccountId, req.Currency); !valid {
+	if toAccount.IsSystem() {
+		err := errors.New("cannot transfer to system account")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
 	}
 
-	result, err := s.store.TransferTx(ctx, arg)
+	idempotency, err := getIdempotency(ctx, authPayload.Username, req)
 	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	arg.Idempotency = idempotency
+
+	var result db.TransferTxResult
+	if req.FXQuoteID == "" {
+		result, err = s.store.TransferTx(ctx, arg)
+	} else {
+		var fxResult db.FXTransferTxResult
+		fxResult, err = s.store.FXTransferTx(ctx, db.FXTransferTxParams{
+			FromAccountId: arg.FromAccountId,
+			ToAccountId:   arg.ToAccountId,
+			Amount:        arg.Amount,
+			QuoteID:       uuid.MustParse(req.FXQuoteID),
+			Username:      authPayload.Username,
+			Memo:          arg.Memo,
+			Idempotency:   arg.Idempotency,
+		})
+		result = fxResult.TransferTxResult
+	}
+	if err != nil {
+		if errors.Is(err, db.ErrFXQuoteNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrIdempotencyKeyReused) || errors.Is(err, utils.ErrCurrencyMismatch) ||
+			errors.Is(err, db.ErrFXQuoteExpired) || errors.Is(err, db.ErrFXQuoteUsed) || errors.Is(err, db.ErrFXQuoteMismatch) ||
+			errors.Is(err, limits.ErrExceeded) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, result)
+	ctx.JSON(http.StatusOK, newTransferTxResponse(result))
 }
 
-func (s *Server) validateCurrency(ctx *gin.Context, accountId int64, currency string) (db.Account, bool) {
+func (s *Server) loadAccount(ctx *gin.Context, accountId int64) (db.Account, bool) {
 	account, err := s.store.GetAccount(ctx, accountId)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -68,6 +213,15 @@ func (s *Server) validateCurrency(ctx *gin.Context, accountId int64, currency st
 		return account, false
 	}
 
+	return account, true
+}
+
+func (s *Server) validateCurrency(ctx *gin.Context, accountId int64, currency string) (db.Account, bool) {
+	account, valid := s.loadAccount(ctx, accountId)
+	if !valid {
+		return account, false
+	}
+
 	if account.Currency != currency {
 		err := fmt.Errorf("account [%d] mismatch %s vs %s", accountId, account.Currency, currency)
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/batch"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/limits"
+	"github.com/dxtym/bankrupt/token"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type transferBatchLegRequest struct {
+	ToAccountId int64  `json:"to_account_id" binding:"required,min=1"`
+	Amount      string `json:"amount" binding:"required"` // decimal string, e.g. "12.34"
+	Memo        string `json:"memo" binding:"max=140"`
+}
+
+type createTransferBatchRequest struct {
+	FromAccountId int64                     `json:"from_account_id" binding:"required,min=1"`
+	Currency      string                    `json:"currency" binding:"required,currency"` // of every leg
+	Legs          []transferBatchLegRequest `json:"legs" binding:"required,min=1,max=5000,dive"`
+	Mode          string                    `json:"mode" binding:"omitempty,oneof=atomic chunked"` // atomic by default
+	ChunkSize     int                       `json:"chunk_size" binding:"min=0,max=500"`
+}
+
+type transferBatchResponse struct {
+	ID            int64      `json:"id"`
+	FromAccountID int64      `json:"from_account_id"`
+	Mode          string     `json:"mode"`
+	Status        string     `json:"status"`
+	LegCount      int32      `json:"leg_count"`
+	PostedCount   int32      `json:"posted_count"`
+	FailedCount   int32      `json:"failed_count"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func newTransferBatchResponse(transferBatch db.TransferBatch) transferBatchResponse {
+	res := transferBatchResponse{
+		ID:            transferBatch.ID,
+		FromAccountID: transferBatch.FromAccountID,
+		Mode:          transferBatch.Mode,
+		Status:        transferBatch.Status,
+		LegCount:      transferBatch.LegCount,
+		PostedCount:   transferBatch.PostedCount,
+		FailedCount:   transferBatch.FailedCount,
+		CreatedAt:     transferBatch.CreatedAt,
+	}
+	if transferBatch.FinishedAt.Valid {
+		res.FinishedAt = &transferBatch.FinishedAt.Time
+	}
+	return res
+}
+
+type transferBatchLegResponse struct {
+	Index    int               `json:"index"`
+	Transfer *transferResponse `json:"transfer,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+type createTransferBatchResponse struct {
+	Batch   transferBatchResponse      `json:"batch"`
+	Results []transferBatchLegResponse `json:"results"`
+}
+
+func (s *Server) createTransferBatch(ctx *gin.Context) {
+	var req createTransferBatchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	legs := make([]db.TransferBatchLeg, len(req.Legs))
+	for i, leg := range req.Legs {
+		amount, err := utils.ParseMoney(leg.Amount, req.Currency)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		if !amount.IsPositive() {
+			err := errors.New("amount must be greater than zero")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		if err := s.currencies.CheckTransferAmount(amount); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		if leg.ToAccountId == req.FromAccountId {
+			err := errors.New("cannot transfer to the same account")
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+
+		legs[i] = db.TransferBatchLeg{
+			ToAccountId: leg.ToAccountId,
+			Amount:      amount,
+			Memo:        leg.Memo,
+		}
+	}
+
+	fromAccount, valid := s.validateCurrency(ctx, req.FromAccountId, req.Currency)
+	if !valid {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if err := s.policy.AuthorizeOwner(authPayload, authz.MoveMoney, fromAccount.Owner); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	if !s.requireVerifiedEmail(ctx, authPayload) {
+		return
+	}
+
+	idempotency, err := getIdempotency(ctx, authPayload.Username, req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = db.BatchAtomic
+	}
+
+	rsp := createTransferBatchResponse{Results: make([]transferBatchLegResponse, 0, len(legs))}
+	transferBatch, err := batch.Run(ctx, s.store, batch.Params{
+		FromAccountID: req.FromAccountId,
+		Owner:         fromAccount.Owner,
+		Legs:          legs,
+		Mode:          mode,
+		ChunkSize:     req.ChunkSize,
+		Idempotency:   idempotency,
+	}, func(progress batch.Progress) error {
+		for _, result := range progress.Results {
+			leg := transferBatchLegResponse{Index: result.Index}
+			if result.Err != nil {
+				leg.Error = result.Err.Error()
+			} else {
+				transfer := newTransferResponse(result.Transfer)
+				leg.Transfer = &transfer
+			}
+			rsp.Results = append(rsp.Results, leg)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrSystemAccount) || errors.Is(err, utils.ErrCurrencyMismatch) ||
+			errors.Is(err, limits.ErrExceeded) || errors.Is(err, db.ErrIdempotencyKeyReused) {
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+			return
+		}
+		if errors.Is(err, db.ErrBatchConflict) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rsp.Batch = newTransferBatchResponse(transferBatch)
+	ctx.JSON(http.StatusOK, rsp)
+}
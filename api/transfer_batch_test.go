@@ -0,0 +1,280 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/dxtym/bankrupt/db/mock"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/token"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateTransferBatchAPI(t *testing.T) {
+	amount := utils.NewMoney(1050, utils.USD)
+
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+
+	account1 := randomAccount(user1.Username)
+	account2 := randomAccount(user2.Username)
+	account3 := randomAccount(user2.Username)
+	account1.Currency = utils.USD
+
+	legs := []gin.H{
+		{"to_account_id": account2.ID, "amount": amount.Decimal()},
+		{"to_account_id": account3.ID, "amount": amount.Decimal(), "memo": "salary"},
+	}
+	transferBatch := db.TransferBatch{
+		ID:            utils.RandomInt(1, 1000),
+		FromAccountID: account1.ID,
+		Mode:          db.BatchAtomic,
+		Status:        db.BatchRunning,
+		LegCount:      2,
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(s *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"currency":        utils.USD,
+				"legs":            legs,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account1.ID)).
+					Times(1).Return(account1, nil)
+				s.EXPECT().
+					CreateTransferBatch(gomock.Any(), gomock.Eq(db.CreateTransferBatchParams{
+						FromAccountID: account1.ID,
+						Owner:         account1.Owner,
+						Mode:          db.BatchAtomic,
+						LegCount:      2,
+					})).
+					Times(1).Return(transferBatch, nil)
+				s.EXPECT().
+					TransferBatchTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.TransferBatchTxParams) (db.TransferBatchTxResult, error) {
+						require.True(t, arg.Atomic)
+						require.Len(t, arg.Legs, 2)
+						require.Equal(t, "salary", arg.Legs[1].Memo)
+
+						result := db.TransferBatchTxResult{Legs: make([]db.TransferBatchLegResult, len(arg.Legs))}
+						for i, leg := range arg.Legs {
+							result.Legs[i].Transfer = db.Transfer{
+								FromAccountID: arg.FromAccountId,
+								ToAccountID:   leg.ToAccountId,
+								Amount:        leg.Amount.Amount,
+								Currency:      leg.Amount.Currency,
+							}
+						}
+						return result, nil
+					})
+				s.EXPECT().
+					UpdateTransferBatch(gomock.Any(), gomock.Eq(db.UpdateTransferBatchParams{
+						ID:          transferBatch.ID,
+						Status:      db.BatchPosted,
+						PostedCount: 2,
+					})).
+					Times(1).Return(transferBatch, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp createTransferBatchResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, transferBatch.ID, rsp.Batch.ID)
+				require.Len(t, rsp.Results, 2)
+				require.Equal(t, amount, rsp.Results[0].Transfer.Amount)
+			},
+		},
+		{
+			name: "IdempotentReplay",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"currency":        utils.USD,
+				"legs":            legs,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
+				request.Header.Set(idempotencyKeyHeader, "payroll-2026-10")
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				var requestHash string
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account1.ID)).
+					Times(1).Return(account1, nil)
+				s.EXPECT().
+					CreateTransferBatch(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateTransferBatchParams) (db.TransferBatch, error) {
+						require.Equal(t, "payroll-2026-10", arg.IdempotencyKey.String)
+						requestHash = arg.RequestHash.String
+						return db.TransferBatch{}, sql.ErrNoRows
+					})
+				s.EXPECT().
+					GetTransferBatchByIdempotencyKey(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.GetTransferBatchByIdempotencyKeyParams) (db.TransferBatch, error) {
+						require.Equal(t, account1.Owner, arg.Owner)
+						posted := transferBatch
+						posted.Status = db.BatchPosted
+						posted.PostedCount = 2
+						posted.RequestHash = sql.NullString{String: requestHash, Valid: true}
+						return posted, nil
+					})
+				s.EXPECT().
+					TransferBatchTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp createTransferBatchResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, transferBatch.ID, rsp.Batch.ID)
+				require.Equal(t, db.BatchPosted, rsp.Batch.Status)
+				require.Empty(t, rsp.Results)
+			},
+		},
+		{
+			name: "InsufficientFunds",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"currency":        utils.USD,
+				"legs":            legs,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account1.ID)).
+					Times(1).Return(account1, nil)
+				s.EXPECT().
+					CreateTransferBatch(gomock.Any(), gomock.Any()).
+					Times(1).Return(transferBatch, nil)
+				s.EXPECT().
+					TransferBatchTx(gomock.Any(), gomock.Any()).
+					Times(1).Return(db.TransferBatchTxResult{}, fmt.Errorf("leg [1]: %w", db.ErrInsufficientFunds))
+				s.EXPECT().
+					UpdateTransferBatch(gomock.Any(), gomock.Any()).
+					Times(1).Return(transferBatch, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "UnAuthorized",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"currency":        utils.USD,
+				"legs":            legs,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, "unauthorized_user", utils.DepositorRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account1.ID)).
+					Times(1).Return(account1, nil)
+				s.EXPECT().
+					CreateTransferBatch(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "SameAccount",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"currency":        utils.USD,
+				"legs":            []gin.H{{"to_account_id": account1.ID, "amount": amount.Decimal()}},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+				s.EXPECT().
+					CreateTransferBatch(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidMode",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"currency":        utils.USD,
+				"legs":            legs,
+				"mode":            "eventually",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					CreateTransferBatch(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/transfer_batches"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.token)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -14,12 +14,13 @@ import (
 	"github.com/dxtym/bankrupt/token"
 	"github.com/dxtym/bankrupt/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
 func TestCreateTransferAPI(t *testing.T) {
-	amount := int64(10)
+	amount := utils.NewMoney(1050, utils.USD)
 
 	user1, _ := randomUser(t)
 	user2, _ := randomUser(t)
@@ -33,6 +34,8 @@ func TestCreateTransferAPI(t *testing.T) {
 	account2.Currency = utils.USD
 	account3.Currency = utils.EUR
 
+	quoteID := uuid.New()
+
 	testCases := []struct {
 		name          string
 		body          gin.H
@@ -45,11 +48,11 @@ func TestCreateTransferAPI(t *testing.T) {
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
-				"amount":          amount,
+				"amount":          amount.Decimal(),
 				"currency":        utils.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -65,10 +68,27 @@ func TestCreateTransferAPI(t *testing.T) {
 					Amount:        amount,
 				}
 				s.EXPECT().
-					TransferTx(gomock.Any(), gomock.Eq(arg)).AnyTimes()
+					TransferTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.TransferTxResult{
+						Transfer: db.Transfer{
+							FromAccountID: account1.ID,
+							ToAccountID:   account2.ID,
+							Amount:        amount.Amount,
+							Currency:      amount.Currency,
+						},
+						FromAccount: account1,
+						ToAccount:   account2,
+					}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
+
+				// amounts go back as decimal strings like the request
+				var rsp map[string]map[string]any
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, map[string]any{"amount": "10.50", "currency": utils.USD}, rsp["transfer"]["amount"])
 			},
 		},
 		{
@@ -76,11 +96,11 @@ func TestCreateTransferAPI(t *testing.T) {
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
-				"amount":          amount,
+				"amount":          amount.Decimal(),
 				"currency":        utils.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, "unauthorized_user", time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, "unauthorized_user", utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -96,16 +116,38 @@ func TestCreateTransferAPI(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name: "BankerCannotMoveOtherMoney",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount.Decimal(),
+				"currency":        utils.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, "banker", utils.BankerRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account1.ID)).
+					Times(1).Return(account1, nil)
+				s.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 		{
 			name: "FromAccountNotFound",
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
-				"amount":          amount,
+				"amount":          amount.Decimal(),
 				"currency":        utils.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -125,11 +167,11 @@ func TestCreateTransferAPI(t *testing.T) {
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
-				"amount":          amount,
+				"amount":          amount.Decimal(),
 				"currency":        utils.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -150,11 +192,11 @@ func TestCreateTransferAPI(t *testing.T) {
 			body: gin.H{
 				"from_account_id": account3.ID,
 				"to_account_id":   account1.ID,
-				"amount":          amount,
+				"amount":          amount.Decimal(),
 				"currency":        utils.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -174,11 +216,11 @@ func TestCreateTransferAPI(t *testing.T) {
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account3.ID,
-				"amount":          amount,
+				"amount":          amount.Decimal(),
 				"currency":        utils.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -199,11 +241,11 @@ func TestCreateTransferAPI(t *testing.T) {
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
-				"amount":          amount,
+				"amount":          amount.Decimal(),
 				"currency":        "XYZ",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -220,11 +262,11 @@ func TestCreateTransferAPI(t *testing.T) {
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
-				"amount":          amount,
+				"amount":          amount.Decimal(),
 				"currency":        utils.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -241,11 +283,53 @@ func TestCreateTransferAPI(t *testing.T) {
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
-				"amount":          -amount,
+				"amount":          "-" + amount.Decimal(),
+				"currency":        utils.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				s.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidAmountPrecision",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount.Decimal() + "1",
+				"currency":        utils.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				s.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AmountAboveLimit",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          "10000.01",
 				"currency":        utils.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -257,16 +341,127 @@ func TestCreateTransferAPI(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name: "InsufficientFunds",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount.Decimal(),
+				"currency":        utils.USD,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account1.ID)).
+					Times(1).Return(account1, nil)
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account2.ID)).
+					Times(1).Return(account2, nil)
+				s.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).Return(db.TransferTxResult{}, db.ErrInsufficientFunds)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "FXTransferOK",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account3.ID,
+				"amount":          amount.Decimal(),
+				"currency":        utils.USD,
+				"fx_quote_id":     quoteID.String(),
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account1.ID)).
+					Times(1).Return(account1, nil)
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account3.ID)).
+					Times(1).Return(account3, nil)
+
+				arg := db.FXTransferTxParams{
+					FromAccountId: account1.ID,
+					ToAccountId:   account3.ID,
+					Amount:        amount,
+					QuoteID:       quoteID,
+					Username:      account1.Owner,
+				}
+				s.EXPECT().
+					FXTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+				s.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "FXQuoteExpired",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account3.ID,
+				"amount":          amount.Decimal(),
+				"currency":        utils.USD,
+				"fx_quote_id":     quoteID.String(),
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account1.ID)).
+					Times(1).Return(account1, nil)
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account3.ID)).
+					Times(1).Return(account3, nil)
+				s.EXPECT().
+					FXTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).Return(db.FXTransferTxResult{}, db.ErrFXQuoteExpired)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidFXQuoteID",
+			body: gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account3.ID,
+				"amount":          amount.Decimal(),
+				"currency":        utils.USD,
+				"fx_quote_id":     "quote",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubs: func(s *mockdb.MockStore) {
+				s.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				s.EXPECT().
+					FXTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "TransferTxError",
 			body: gin.H{
 				"from_account_id": account1.ID,
 				"to_account_id":   account2.ID,
-				"amount":          amount,
+				"amount":          amount.Decimal(),
 				"currency":        utils.USD,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationType, account1.Owner, utils.DepositorRole, time.Minute)
 			},
 			buildStubs: func(s *mockdb.MockStore) {
 				s.EXPECT().
@@ -310,3 +505,83 @@ func TestCreateTransferAPI(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateTransferAPIVerifiedEmail(t *testing.T) {
+	amount := utils.NewMoney(1050, utils.USD)
+
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+
+	account1 := randomAccount(user1.Username)
+	account2 := randomAccount(user2.Username)
+	account1.Currency = utils.USD
+	account2.Currency = utils.USD
+
+	verified := user1
+	verified.IsEmailVerified = true
+
+	testCases := []struct {
+		name          string
+		user          db.User
+		transfers     int
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "Verified",
+			user:      verified,
+			transfers: 1,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotVerified",
+			user: user1,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(account1.ID)).
+				Times(1).Return(account1, nil)
+			store.EXPECT().
+				GetUser(gomock.Any(), gomock.Eq(user1.Username)).
+				Times(1).Return(tc.user, nil)
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(account2.ID)).
+				Times(tc.transfers).Return(account2, nil)
+			store.EXPECT().
+				TransferTx(gomock.Any(), gomock.Any()).
+				Times(tc.transfers).
+				Return(db.TransferTxResult{Transfer: db.Transfer{Amount: amount.Amount, Currency: amount.Currency}}, nil)
+
+			server := newTestServer(t, store)
+			server.config.RequireVerifiedEmail = true
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"from_account_id": account1.ID,
+				"to_account_id":   account2.ID,
+				"amount":          amount.Decimal(),
+				"currency":        utils.USD,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.token, authorizationType, user1.Username, utils.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -2,11 +2,14 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"time"
 
 	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/token"
 	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/worker"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
@@ -50,14 +53,18 @@ func (s *Server) CreateUser(ctx *gin.Context) {
 		return
 	}
 
-	arg := db.CreateUserParams{
-		Username:       req.Username,
-		HashedPassword: hashedPassword,
-		FullName:       req.FullName,
-		Email:          req.Email,
+	// verification email goes through the outbox, so it is only sent if the user is committed
+	arg := db.CreateUserTxParams{
+		CreateUserParams: db.CreateUserParams{
+			Username:       req.Username,
+			HashedPassword: hashedPassword,
+			FullName:       req.FullName,
+			Email:          req.Email,
+		},
+		Outbox: []db.OutboxTask{worker.VerifyEmailTask(req.Username)},
 	}
 
-	user, err := s.store.CreateUser(ctx, arg)
+	txResult, err := s.store.CreateUserTx(ctx, arg)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code.Name() {
@@ -71,10 +78,34 @@ func (s *Server) CreateUser(ctx *gin.Context) {
 	}
 
 	// keep the password hash from return
-	res := NewUserResponse(user)
+	res := NewUserResponse(txResult.User)
 	ctx.JSON(http.StatusOK, res)
 }
 
+// money movement can be limited to users who verified their email
+func (s *Server) requireVerifiedEmail(ctx *gin.Context, payload *token.Payload) bool {
+	if !s.config.RequireVerifiedEmail {
+		return true
+	}
+
+	user, err := s.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	if !user.IsEmailVerified {
+		err := errors.New("email address is not verified")
+		ctx.JSON(http.StatusForbidden, errorResponse(err))
+		return false
+	}
+	return true
+}
+
 type LoginUserRequest struct {
 	Username string `json:"username" binding:"required,alphanum"`
 	Password string `json:"password" binding:"required,min=6"`
@@ -115,26 +146,27 @@ func (s *Server) LoginUser(ctx *gin.Context) {
 	}
 
 	// create token for user
-	accessToken, accessPayload, err := s.token.CreateToken(user.Username, s.config.TokenDuration)
+	accessToken, accessPayload, err := s.token.CreateToken(user.Username, user.Role, token.AccessToken, s.config.TokenDuration)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	refreshToken, refreshPayload, err := s.token.CreateToken(user.Username, s.config.RefreshDuration)
+	refreshToken, refreshPayload, err := s.token.CreateToken(user.Username, user.Role, token.RefreshToken, s.config.RefreshDuration)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
 	session, err := s.store.CreateSession(ctx, db.CreateSessionParams{
-		ID:           refreshPayload.Id,
-		Username:     user.Username,
-		RefreshToken: refreshToken,
-		UserAgent:    ctx.Request.UserAgent(),
-		ClientIp:     ctx.ClientIP(),
-		IsBlocked:    false,
-		ExpiresAt:    refreshPayload.ExpiredAt,
+		ID:               refreshPayload.Id,
+		FamilyID:         refreshPayload.Id,
+		Username:         user.Username,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		UserAgent:        ctx.Request.UserAgent(),
+		ClientIp:         ctx.ClientIP(),
+		IsBlocked:        false,
+		ExpiresAt:        refreshPayload.ExpiredAt,
 	})
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
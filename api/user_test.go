@@ -13,19 +13,20 @@ import (
 	mockdb "github.com/dxtym/bankrupt/db/mock"
 	db "github.com/dxtym/bankrupt/db/sqlc"
 	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/worker"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
-// custom matcher for password and arg
-type eqCreateUserParamsMatcher struct {
+// custom matcher for password and arg, the verification email must be queued with the user
+type eqCreateUserTxParamsMatcher struct {
 	arg      db.CreateUserParams
 	password string
 }
 
-func (e eqCreateUserParamsMatcher) Matches(x interface{}) bool {
-	arg, ok := x.(db.CreateUserParams)
+func (e eqCreateUserTxParamsMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(db.CreateUserTxParams)
 	if !ok {
 		return false
 	}
@@ -36,17 +37,21 @@ func (e eqCreateUserParamsMatcher) Matches(x interface{}) bool {
 		return false
 	}
 
+	if !reflect.DeepEqual([]db.OutboxTask{worker.VerifyEmailTask(e.arg.Username)}, arg.Outbox) {
+		return false
+	}
+
 	// set user password to hashed password
 	e.arg.HashedPassword = arg.HashedPassword
-	return reflect.DeepEqual(e.arg, arg) // check if arg is equal to e.arg
+	return reflect.DeepEqual(e.arg, arg.CreateUserParams) // check if arg is equal to e.arg
 }
 
-func (e eqCreateUserParamsMatcher) String() string {
+func (e eqCreateUserTxParamsMatcher) String() string {
 	return fmt.Sprintf("matches arg %v and password %v", e.arg, e.password)
 }
 
-func EqCreateUserParams(arg db.CreateUserParams, password string) gomock.Matcher {
-	return eqCreateUserParamsMatcher{arg: arg, password: password}
+func EqCreateUserTxParams(arg db.CreateUserParams, password string) gomock.Matcher {
+	return eqCreateUserTxParamsMatcher{arg: arg, password: password}
 }
 
 func TestCreateUserAPI(t *testing.T) {
@@ -73,9 +78,9 @@ func TestCreateUserAPI(t *testing.T) {
 					Email:    user.Email,
 				}
 				s.EXPECT().
-					CreateUser(gomock.Any(), EqCreateUserParams(arg, password)).
+					CreateUserTx(gomock.Any(), EqCreateUserTxParams(arg, password)).
 					Times(1).
-					Return(user, nil)
+					Return(db.CreateUserTxResult{User: user}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -1,15 +1,17 @@
 package api
 
 import (
-	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/currency"
 	"github.com/go-playground/validator/v10"
 )
 
-var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
-	if currency, ok := fieldLevel.Field().Interface().(string); ok {
-		// check if valid currency
-		return utils.CurrencySupported(currency)
-	}
+func validCurrency(currencies *currency.Registry) validator.Func {
+	return func(fieldLevel validator.FieldLevel) bool {
+		if currency, ok := fieldLevel.Field().Interface().(string); ok {
+			// check if currency is registered and enabled
+			return currencies.Supported(currency)
+		}
 
-	return false
+		return false
+	}
 }
@@ -0,0 +1,87 @@
+package authz
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/dxtym/bankrupt/token"
+	"github.com/dxtym/bankrupt/utils"
+)
+
+var (
+	ErrForbidden = errors.New("user role is not allowed to perform the operation")
+	ErrNotOwner  = errors.New("resource doesn't belong to the user")
+)
+
+type Operation string
+
+const (
+	CreateAccount   Operation = "create_account"
+	ReadAccount     Operation = "read_account" // account, its transfers and statements
+	ListAccounts    Operation = "list_accounts"
+	DeleteAccount   Operation = "delete_account"
+	MoveMoney       Operation = "move_money" // transfers, batches, holds and schedules
+	ReverseTransfer Operation = "reverse_transfer"
+	ReverseSystem   Operation = "reverse_system" // reversal that pays into a system account
+	Deposit         Operation = "deposit"
+	Withdraw        Operation = "withdraw"
+	ReadRates       Operation = "read_rates" // currencies and fx rates
+	ManageRates     Operation = "manage_rates"
+	ManageLimits    Operation = "manage_limits" // transfer limits and user tiers
+	UpdateUser      Operation = "update_user"
+	ManageSessions  Operation = "manage_sessions"
+)
+
+// who may call an operation, on own resources or on anyone's
+type Rule struct {
+	Own []string
+	Any []string
+}
+
+type Policy map[Operation]Rule
+
+var (
+	everyone = []string{utils.DepositorRole, utils.BankerRole}
+	bankers  = []string{utils.BankerRole}
+)
+
+// depositors act on their own resources, bankers may read and service any account
+var DefaultPolicy = Policy{
+	CreateAccount:   {Own: everyone},
+	ReadAccount:     {Own: everyone, Any: bankers},
+	ListAccounts:    {Own: everyone},
+	DeleteAccount:   {Own: everyone},
+	MoveMoney:       {Own: everyone},
+	ReverseTransfer: {Own: everyone, Any: bankers}, // own is the recipient
+	ReverseSystem:   {Any: bankers},                // like a withdrawal, so bankers only
+	Deposit:         {Any: bankers},
+	Withdraw:        {Any: bankers},
+	ReadRates:       {Own: everyone},
+	ManageRates:     {Any: bankers},
+	ManageLimits:    {Any: bankers},
+	UpdateUser:      {Own: everyone},
+	ManageSessions:  {Own: everyone, Any: bankers}, // bankers can revoke a compromised session
+}
+
+// check role may call the operation at all, unknown operations are denied
+func (p Policy) Authorize(payload *token.Payload, op Operation) error {
+	rule := p[op]
+	if slices.Contains(rule.Own, payload.Role) || slices.Contains(rule.Any, payload.Role) {
+		return nil
+	}
+	return ErrForbidden
+}
+
+// check user may call the operation on a resource of owner
+func (p Policy) AuthorizeOwner(payload *token.Payload, op Operation, owner string) error {
+	rule := p[op]
+	switch {
+	case slices.Contains(rule.Any, payload.Role):
+		return nil
+	case !slices.Contains(rule.Own, payload.Role):
+		return ErrForbidden
+	case owner != payload.Username:
+		return ErrNotOwner
+	}
+	return nil
+}
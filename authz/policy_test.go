@@ -0,0 +1,61 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/dxtym/bankrupt/token"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorize(t *testing.T) {
+	depositor := &token.Payload{Username: utils.RandomOwner(), Role: utils.DepositorRole}
+	banker := &token.Payload{Username: utils.RandomOwner(), Role: utils.BankerRole}
+	system := &token.Payload{Username: utils.RandomOwner(), Role: utils.SystemRole}
+
+	require.NoError(t, DefaultPolicy.Authorize(depositor, MoveMoney))
+	require.NoError(t, DefaultPolicy.Authorize(banker, MoveMoney))
+	require.NoError(t, DefaultPolicy.Authorize(banker, Deposit))
+
+	require.ErrorIs(t, DefaultPolicy.Authorize(depositor, Deposit), ErrForbidden)
+	require.ErrorIs(t, DefaultPolicy.Authorize(depositor, ManageLimits), ErrForbidden)
+	require.ErrorIs(t, DefaultPolicy.Authorize(system, ReadAccount), ErrForbidden)
+	require.ErrorIs(t, DefaultPolicy.Authorize(banker, Operation("unknown")), ErrForbidden)
+}
+
+func TestAuthorizeOwner(t *testing.T) {
+	depositor := &token.Payload{Username: utils.RandomOwner(), Role: utils.DepositorRole}
+	banker := &token.Payload{Username: utils.RandomOwner(), Role: utils.BankerRole}
+	other := utils.RandomOwner()
+
+	testCases := []struct {
+		name    string
+		payload *token.Payload
+		op      Operation
+		owner   string
+		err     error
+	}{
+		{"DepositorReadsOwn", depositor, ReadAccount, depositor.Username, nil},
+		{"DepositorReadsOther", depositor, ReadAccount, other, ErrNotOwner},
+		{"BankerReadsOther", banker, ReadAccount, other, nil},
+		{"BankerMovesOwn", banker, MoveMoney, banker.Username, nil},
+		{"BankerMovesOther", banker, MoveMoney, other, ErrNotOwner},
+		{"BankerDeposits", banker, Deposit, other, nil},
+		{"DepositorReversesDeposit", depositor, ReverseSystem, other, ErrForbidden},
+		{"BankerReversesDeposit", banker, ReverseSystem, other, nil},
+		{"DepositorDepositsOwn", depositor, Deposit, depositor.Username, ErrForbidden},
+		{"DepositorRevokesOther", depositor, ManageSessions, other, ErrNotOwner},
+		{"BankerRevokesOther", banker, ManageSessions, other, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := DefaultPolicy.AuthorizeOwner(tc.payload, tc.op, tc.owner)
+			if tc.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tc.err)
+			}
+		})
+	}
+}
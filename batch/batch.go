@@ -0,0 +1,226 @@
+package batch
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+)
+
+const (
+	DefaultChunkSize = 100
+	MaxChunkSize     = 500
+	MaxLegs          = 5000
+)
+
+// queries needed to run a batch, satisfied by db.Store
+type Store interface {
+	CreateTransferBatch(ctx context.Context, arg db.CreateTransferBatchParams) (db.TransferBatch, error)
+	GetTransferBatchByIdempotencyKey(ctx context.Context, arg db.GetTransferBatchByIdempotencyKeyParams) (db.TransferBatch, error)
+	UpdateTransferBatch(ctx context.Context, arg db.UpdateTransferBatchParams) (db.TransferBatch, error)
+	TransferBatchTx(ctx context.Context, arg db.TransferBatchTxParams) (db.TransferBatchTxResult, error)
+}
+
+type Params struct {
+	FromAccountID int64
+	Owner         string // of the from account
+	Legs          []db.TransferBatchLeg
+	Mode          string                // db.BatchAtomic or db.BatchChunked
+	ChunkSize     int                   // legs per transaction in chunked mode and per progress report
+	Idempotency   *db.IdempotencyParams // optional, a retried request gets the batch it started first
+}
+
+type LegResult struct {
+	Index    int
+	Transfer db.Transfer // zero when the leg failed
+	Err      error
+}
+
+// results are final and only cover legs since the previous report
+type Progress struct {
+	Batch     db.TransferBatch
+	Processed int
+	Posted    int
+	Failed    int
+	Results   []LegResult
+}
+
+// run the batch to the end, progress is optional and its last report carries the finished batch,
+// an error from progress stops a chunked batch before the next chunk, a retry with the same
+// idempotency key carries on with the legs that weren't processed
+func Run(ctx context.Context, store Store, arg Params, progress func(Progress) error) (db.TransferBatch, error) {
+	if progress == nil {
+		progress = func(Progress) error { return nil }
+	}
+	if arg.ChunkSize <= 0 {
+		arg.ChunkSize = DefaultChunkSize
+	}
+
+	createArg := db.CreateTransferBatchParams{
+		FromAccountID: arg.FromAccountID,
+		Owner:         arg.Owner,
+		Mode:          arg.Mode,
+		LegCount:      int32(len(arg.Legs)),
+	}
+	if arg.Idempotency != nil {
+		createArg.IdempotencyKey = sql.NullString{String: arg.Idempotency.Key, Valid: true}
+		createArg.RequestHash = sql.NullString{String: arg.Idempotency.RequestHash, Valid: true}
+	}
+
+	batch, err := store.CreateTransferBatch(ctx, createArg)
+	if errors.Is(err, sql.ErrNoRows) && arg.Idempotency != nil {
+		if batch, err = existing(ctx, store, arg); err != nil {
+			return batch, err
+		}
+	} else if err != nil {
+		return batch, fmt.Errorf("cannot create batch: %w", err)
+	}
+
+	// a retry carries on after the legs the first request processed
+	state := Progress{
+		Batch:     batch,
+		Processed: int(batch.PostedCount + batch.FailedCount),
+		Posted:    int(batch.PostedCount),
+		Failed:    int(batch.FailedCount),
+	}
+	if state.Processed == len(arg.Legs) {
+		// nothing left to post, report the finished batch
+		return batch, progress(state)
+	}
+	if arg.Mode == db.BatchAtomic {
+		err = runAtomic(ctx, store, arg, &state, progress)
+	} else {
+		err = runChunked(ctx, store, arg, &state, progress)
+	}
+	if errors.Is(err, db.ErrBatchConflict) {
+		// another request is running the batch and finishes it
+		return state.Batch, err
+	}
+
+	// record the outcome even when the caller went away
+	state.Batch, err = finish(context.WithoutCancel(ctx), store, state, len(arg.Legs), err)
+	if err != nil {
+		return state.Batch, err
+	}
+	return state.Batch, progress(state)
+}
+
+// get the batch created with the same key, legs it processed are not run again
+func existing(ctx context.Context, store Store, arg Params) (db.TransferBatch, error) {
+	batch, err := store.GetTransferBatchByIdempotencyKey(ctx, db.GetTransferBatchByIdempotencyKeyParams{
+		Owner:          arg.Owner,
+		IdempotencyKey: sql.NullString{String: arg.Idempotency.Key, Valid: true},
+	})
+	if err != nil {
+		return batch, fmt.Errorf("cannot get batch: %w", err)
+	}
+
+	if batch.RequestHash.String != arg.Idempotency.RequestHash {
+		return batch, db.ErrIdempotencyKeyReused
+	}
+	return batch, nil
+}
+
+// everything in one transaction, until it commits only the processed count moves
+func runAtomic(ctx context.Context, store Store, arg Params, state *Progress, progress func(Progress) error) error {
+	// counts are queued and sent from another goroutine, so a slow caller never holds the account locks
+	reports := make(chan Progress, len(arg.Legs)/arg.ChunkSize)
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+		for report := range reports {
+			// the transaction fails by itself once the caller is gone
+			_ = progress(report)
+		}
+	}()
+
+	result, err := store.TransferBatchTx(ctx, db.TransferBatchTxParams{
+		BatchID:       state.Batch.ID,
+		FromAccountId: arg.FromAccountID,
+		Legs:          arg.Legs,
+		Atomic:        true,
+		OnLeg: func(index int, err error) {
+			if processed := index + 1; processed%arg.ChunkSize == 0 && processed < len(arg.Legs) {
+				reports <- Progress{Batch: state.Batch, Processed: processed}
+			}
+		},
+	})
+	close(reports)
+	<-sent
+	if err != nil {
+		state.Processed = len(arg.Legs)
+		state.Failed = len(arg.Legs)
+		return err
+	}
+
+	state.collect(0, result)
+	return nil
+}
+
+// one transaction per chunk, failed legs are reported and skipped
+func runChunked(ctx context.Context, store Store, arg Params, state *Progress, progress func(Progress) error) error {
+	for start := state.Processed; start < len(arg.Legs); start += arg.ChunkSize {
+		end := min(start+arg.ChunkSize, len(arg.Legs))
+
+		result, err := store.TransferBatchTx(ctx, db.TransferBatchTxParams{
+			BatchID:       state.Batch.ID,
+			Offset:        start,
+			FromAccountId: arg.FromAccountID,
+			Legs:          arg.Legs[start:end],
+		})
+		if err != nil {
+			return fmt.Errorf("legs [%d-%d]: %w", start, end-1, err)
+		}
+		state.Batch = result.Batch
+		state.collect(start, result)
+
+		// the last chunk is reported with the finished batch
+		if end == len(arg.Legs) {
+			break
+		}
+		if err := progress(*state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (state *Progress) collect(offset int, result db.TransferBatchTxResult) {
+	state.Results = make([]LegResult, len(result.Legs))
+	for i, leg := range result.Legs {
+		state.Results[i] = LegResult{Index: offset + i, Transfer: leg.Transfer, Err: leg.Err}
+		if leg.Err != nil {
+			state.Failed++
+		} else {
+			state.Posted++
+		}
+	}
+	state.Processed += len(result.Legs)
+}
+
+// store final counts and status, runErr is returned unless recording fails
+func finish(ctx context.Context, store Store, state Progress, legCount int, runErr error) (db.TransferBatch, error) {
+	status := db.BatchPartial
+	switch {
+	case state.Posted == legCount:
+		status = db.BatchPosted
+	case state.Posted == 0:
+		status = db.BatchFailed
+	}
+
+	batch, err := store.UpdateTransferBatch(ctx, db.UpdateTransferBatchParams{
+		ID:          state.Batch.ID,
+		Status:      status,
+		PostedCount: int32(state.Posted),
+		FailedCount: int32(state.Failed),
+	})
+	if err != nil {
+		if runErr != nil {
+			return state.Batch, fmt.Errorf("%w, and cannot finish batch: %v", runErr, err)
+		}
+		return state.Batch, fmt.Errorf("cannot finish batch: %w", err)
+	}
+	return batch, runErr
+}
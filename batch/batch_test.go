@@ -0,0 +1,314 @@
+package batch
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var errRejected = errors.New("rejected")
+
+// fails legs to rejected accounts, atomic calls fail as a whole
+type fakeStore struct {
+	rejected map[int64]bool
+	batch    db.TransferBatch
+	txCalls  int
+	onCommit func() // called when a transaction ends
+}
+
+func (f *fakeStore) CreateTransferBatch(ctx context.Context, arg db.CreateTransferBatchParams) (db.TransferBatch, error) {
+	if arg.IdempotencyKey.Valid && f.batch.Owner == arg.Owner && f.batch.IdempotencyKey == arg.IdempotencyKey {
+		return db.TransferBatch{}, sql.ErrNoRows
+	}
+	f.batch = db.TransferBatch{
+		ID:             1,
+		FromAccountID:  arg.FromAccountID,
+		Owner:          arg.Owner,
+		Mode:           arg.Mode,
+		Status:         db.BatchRunning,
+		LegCount:       arg.LegCount,
+		IdempotencyKey: arg.IdempotencyKey,
+		RequestHash:    arg.RequestHash,
+	}
+	return f.batch, nil
+}
+
+func (f *fakeStore) GetTransferBatchByIdempotencyKey(ctx context.Context, arg db.GetTransferBatchByIdempotencyKeyParams) (db.TransferBatch, error) {
+	if f.batch.Owner != arg.Owner || f.batch.IdempotencyKey != arg.IdempotencyKey {
+		return db.TransferBatch{}, sql.ErrNoRows
+	}
+	return f.batch, nil
+}
+
+func (f *fakeStore) UpdateTransferBatch(ctx context.Context, arg db.UpdateTransferBatchParams) (db.TransferBatch, error) {
+	f.batch.Status = arg.Status
+	f.batch.PostedCount = arg.PostedCount
+	f.batch.FailedCount = arg.FailedCount
+	return f.batch, nil
+}
+
+func (f *fakeStore) TransferBatchTx(ctx context.Context, arg db.TransferBatchTxParams) (db.TransferBatchTxResult, error) {
+	f.txCalls++
+	if int(f.batch.PostedCount+f.batch.FailedCount) != arg.Offset {
+		return db.TransferBatchTxResult{}, db.ErrBatchConflict
+	}
+	result := db.TransferBatchTxResult{Legs: make([]db.TransferBatchLegResult, len(arg.Legs))}
+	for i, leg := range arg.Legs {
+		if f.rejected[leg.ToAccountId] {
+			if arg.Atomic {
+				return db.TransferBatchTxResult{}, fmt.Errorf("leg [%d]: %w", i, errRejected)
+			}
+			result.Legs[i].Err = errRejected
+			f.batch.FailedCount++
+		} else {
+			result.Legs[i].Transfer = db.Transfer{ID: leg.ToAccountId, ToAccountID: leg.ToAccountId}
+			f.batch.PostedCount++
+		}
+		if arg.OnLeg != nil {
+			arg.OnLeg(i, result.Legs[i].Err)
+		}
+	}
+	if f.onCommit != nil {
+		f.onCommit()
+	}
+	result.Batch = f.batch
+	return result, nil
+}
+
+func randomLegs(n int) []db.TransferBatchLeg {
+	legs := make([]db.TransferBatchLeg, n)
+	for i := range legs {
+		legs[i] = db.TransferBatchLeg{ToAccountId: int64(i + 1), Amount: utils.NewMoney(utils.RandomInt(1, 100), utils.USD)}
+	}
+	return legs
+}
+
+func TestRunAtomic(t *testing.T) {
+	store := &fakeStore{}
+
+	var reports []Progress
+	batch, err := Run(context.Background(), store, Params{
+		FromAccountID: 100,
+		Legs:          randomLegs(25),
+		Mode:          db.BatchAtomic,
+		ChunkSize:     10,
+	}, func(p Progress) error {
+		reports = append(reports, p)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, store.txCalls)
+	require.Equal(t, db.BatchPosted, batch.Status)
+	require.Equal(t, int32(25), batch.PostedCount)
+
+	// two counts while the transaction is open, then every result at once
+	require.Len(t, reports, 3)
+	require.Equal(t, 10, reports[0].Processed)
+	require.Empty(t, reports[0].Results)
+	require.Equal(t, 20, reports[1].Processed)
+	last := reports[2]
+	require.Equal(t, batch, last.Batch)
+	require.Len(t, last.Results, 25)
+	require.Equal(t, 24, last.Results[24].Index)
+}
+
+func TestRunAtomicSlowProgress(t *testing.T) {
+	committed := make(chan struct{})
+	store := &fakeStore{onCommit: func() { close(committed) }}
+
+	// reports wait for the commit, the transaction must not wait for them
+	var reports []Progress
+	_, err := Run(context.Background(), store, Params{
+		FromAccountID: 100,
+		Legs:          randomLegs(25),
+		Mode:          db.BatchAtomic,
+		ChunkSize:     10,
+	}, func(p Progress) error {
+		<-committed
+		reports = append(reports, p)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, reports, 3)
+	require.Equal(t, 10, reports[0].Processed)
+	require.Equal(t, 20, reports[1].Processed)
+	require.Len(t, reports[2].Results, 25)
+}
+
+func TestRunAtomicFailed(t *testing.T) {
+	store := &fakeStore{rejected: map[int64]bool{7: true}}
+
+	batch, err := Run(context.Background(), store, Params{
+		FromAccountID: 100,
+		Legs:          randomLegs(10),
+		Mode:          db.BatchAtomic,
+	}, nil)
+	require.ErrorIs(t, err, errRejected)
+	require.Equal(t, db.BatchFailed, batch.Status)
+	require.Zero(t, batch.PostedCount)
+	require.Equal(t, int32(10), batch.FailedCount)
+}
+
+func TestRunChunked(t *testing.T) {
+	store := &fakeStore{rejected: map[int64]bool{3: true, 12: true}}
+
+	var results []LegResult
+	batch, err := Run(context.Background(), store, Params{
+		FromAccountID: 100,
+		Legs:          randomLegs(12),
+		Mode:          db.BatchChunked,
+		ChunkSize:     5,
+	}, func(p Progress) error {
+		results = append(results, p.Results...)
+		require.Equal(t, len(results), p.Processed)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, store.txCalls)
+	require.Equal(t, db.BatchPartial, batch.Status)
+	require.Equal(t, int32(10), batch.PostedCount)
+	require.Equal(t, int32(2), batch.FailedCount)
+
+	require.Len(t, results, 12)
+	for i, result := range results {
+		require.Equal(t, i, result.Index)
+		if i == 2 || i == 11 {
+			require.ErrorIs(t, result.Err, errRejected)
+			continue
+		}
+		require.NoError(t, result.Err)
+		require.Equal(t, int64(i+1), result.Transfer.ToAccountID)
+	}
+}
+
+func TestRunChunkedStopped(t *testing.T) {
+	store := &fakeStore{}
+	errGone := errors.New("client gone")
+
+	batch, err := Run(context.Background(), store, Params{
+		FromAccountID: 100,
+		Legs:          randomLegs(12),
+		Mode:          db.BatchChunked,
+		ChunkSize:     5,
+	}, func(p Progress) error {
+		return errGone
+	})
+	require.ErrorIs(t, err, errGone)
+	require.Equal(t, 1, store.txCalls)
+	require.Equal(t, db.BatchPartial, batch.Status)
+	require.Equal(t, int32(5), batch.PostedCount)
+}
+
+func TestRunIdempotent(t *testing.T) {
+	store := &fakeStore{}
+	arg := Params{
+		FromAccountID: 100,
+		Owner:         "alice",
+		Legs:          randomLegs(12),
+		Mode:          db.BatchChunked,
+		ChunkSize:     5,
+		Idempotency:   &db.IdempotencyParams{Username: "alice", Key: "payroll", RequestHash: "hash"},
+	}
+
+	batch, err := Run(context.Background(), store, arg, nil)
+	require.NoError(t, err)
+	require.Equal(t, 3, store.txCalls)
+
+	// a retry reports the first batch without posting again
+	var reports []Progress
+	replayed, err := Run(context.Background(), store, arg, func(p Progress) error {
+		reports = append(reports, p)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, store.txCalls)
+	require.Equal(t, batch, replayed)
+	require.Len(t, reports, 1)
+	require.Equal(t, 12, reports[0].Processed)
+	require.Equal(t, 12, reports[0].Posted)
+
+	arg.Idempotency.RequestHash = "other"
+	_, err = Run(context.Background(), store, arg, nil)
+	require.ErrorIs(t, err, db.ErrIdempotencyKeyReused)
+	require.Equal(t, 3, store.txCalls)
+}
+
+func TestRunChunkedResumed(t *testing.T) {
+	store := &fakeStore{rejected: map[int64]bool{3: true}}
+	errGone := errors.New("client gone")
+	arg := Params{
+		FromAccountID: 100,
+		Owner:         "alice",
+		Legs:          randomLegs(12),
+		Mode:          db.BatchChunked,
+		ChunkSize:     5,
+		Idempotency:   &db.IdempotencyParams{Username: "alice", Key: "payroll", RequestHash: "hash"},
+	}
+
+	// the stream goes away after the first chunk
+	batch, err := Run(context.Background(), store, arg, func(p Progress) error {
+		return errGone
+	})
+	require.ErrorIs(t, err, errGone)
+	require.Equal(t, 1, store.txCalls)
+	require.Equal(t, db.BatchPartial, batch.Status)
+	require.Equal(t, int32(4), batch.PostedCount)
+	require.Equal(t, int32(1), batch.FailedCount)
+
+	// the retry posts the rest without running the first chunk again
+	var results []LegResult
+	batch, err = Run(context.Background(), store, arg, func(p Progress) error {
+		results = append(results, p.Results...)
+		require.Equal(t, 5+len(results), p.Processed)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, store.txCalls)
+	require.Equal(t, db.BatchPartial, batch.Status)
+	require.Equal(t, int32(11), batch.PostedCount)
+	require.Equal(t, int32(1), batch.FailedCount)
+	require.Len(t, results, 7)
+	require.Equal(t, 5, results[0].Index)
+	require.Equal(t, 11, results[6].Index)
+
+	// a process that died mid batch leaves it running, the retry finishes it too
+	arg.Idempotency.Key = "rent"
+	store.rejected = nil
+	store.batch = db.TransferBatch{}
+	_, err = Run(context.Background(), store, arg, func(p Progress) error {
+		return errGone
+	})
+	require.ErrorIs(t, err, errGone)
+	store.batch.Status = db.BatchRunning
+
+	batch, err = Run(context.Background(), store, arg, nil)
+	require.NoError(t, err)
+	require.Equal(t, db.BatchPosted, batch.Status)
+	require.Equal(t, int32(12), batch.PostedCount)
+}
+
+func TestRunConflict(t *testing.T) {
+	store := &fakeStore{}
+	arg := Params{
+		FromAccountID: 100,
+		Owner:         "alice",
+		Legs:          randomLegs(12),
+		Mode:          db.BatchChunked,
+		ChunkSize:     5,
+		Idempotency:   &db.IdempotencyParams{Username: "alice", Key: "payroll", RequestHash: "hash"},
+	}
+
+	// another request posts a chunk between ours, ours stops without finishing the batch
+	_, err := Run(context.Background(), store, arg, func(p Progress) error {
+		store.batch.PostedCount++
+		return nil
+	})
+	require.ErrorIs(t, err, db.ErrBatchConflict)
+	require.Equal(t, db.BatchRunning, store.batch.Status)
+}
@@ -0,0 +1,121 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/rs/zerolog/log"
+)
+
+var ErrAmountOutOfRange = errors.New("amount out of range")
+
+// wait before reloading again after a failed refresh
+const refreshBackoff = 5 * time.Second
+
+// where currencies are loaded from, usually db.Store
+type Source interface {
+	ListCurrencies(ctx context.Context) ([]db.Currency, error)
+}
+
+// in-process cache of currencies table
+type Registry struct {
+	source     Source
+	ttl        time.Duration
+	mu         sync.RWMutex
+	currencies map[string]db.Currency
+	expiresAt  time.Time
+}
+
+func NewRegistry(source Source, ttl time.Duration) *Registry {
+	return &Registry{
+		source: source,
+		ttl:    ttl,
+	}
+}
+
+// reload currencies and make them known to money parsing
+func (r *Registry) Refresh(ctx context.Context) error {
+	list, err := r.source.ListCurrencies(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot list currencies: %w", err)
+	}
+
+	currencies := make(map[string]db.Currency, len(list))
+	for _, currency := range list {
+		currencies[currency.Code] = currency
+		utils.RegisterCurrency(currency.Code, int(currency.Exponent))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.currencies = currencies
+	r.expiresAt = time.Now().Add(r.ttl)
+	return nil
+}
+
+// drop cache so next lookup reloads currencies
+func (r *Registry) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expiresAt = time.Time{}
+}
+
+func (r *Registry) Get(code string) (db.Currency, bool) {
+	// stale cache is still better than rejecting every request
+	if r.claimRefresh() {
+		if err := r.Refresh(context.Background()); err != nil {
+			log.Error().Err(err).Msg("cannot refresh currency registry")
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	currency, ok := r.currencies[code]
+	return currency, ok
+}
+
+// only one caller reloads an expired cache, the others keep reading the stale
+// one, a failed reload is retried after the backoff rather than on every call
+func (r *Registry) claimRefresh() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if !now.After(r.expiresAt) {
+		return false
+	}
+	r.expiresAt = now.Add(min(refreshBackoff, r.ttl))
+	return true
+}
+
+// check currency exists and is enabled
+func (r *Registry) Supported(code string) bool {
+	currency, ok := r.Get(code)
+	return ok && currency.Enabled
+}
+
+// check amount is within currency transfer limits
+func (r *Registry) CheckTransferAmount(amount utils.Money) error {
+	currency, ok := r.Get(amount.Currency)
+	if !ok || !currency.Enabled {
+		return utils.ErrUnsupportedCurrency
+	}
+
+	if amount.Amount < currency.MinTransferAmount {
+		min := utils.NewMoney(currency.MinTransferAmount, currency.Code)
+		return fmt.Errorf("%w: must be at least %s", ErrAmountOutOfRange, min)
+	}
+	if currency.MaxTransferAmount.Valid && amount.Amount > currency.MaxTransferAmount.Int64 {
+		max := utils.NewMoney(currency.MaxTransferAmount.Int64, currency.Code)
+		return fmt.Errorf("%w: must be at most %s", ErrAmountOutOfRange, max)
+	}
+	return nil
+}
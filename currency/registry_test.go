@@ -0,0 +1,70 @@
+package currency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	currencies []db.Currency
+	err        error
+	calls      int
+}
+
+func (f *fakeSource) ListCurrencies(ctx context.Context) ([]db.Currency, error) {
+	f.calls++
+	return f.currencies, f.err
+}
+
+func TestRegistry(t *testing.T) {
+	source := &fakeSource{
+		currencies: []db.Currency{
+			{Code: utils.USD, Exponent: 2, Enabled: true, MinTransferAmount: 100, MaxTransferAmount: sql.NullInt64{Int64: 10000, Valid: true}},
+			{Code: utils.EUR, Exponent: 2, Enabled: false, MinTransferAmount: 1},
+			{Code: "KWD", Exponent: 3, Enabled: true, MinTransferAmount: 1},
+		},
+	}
+	registry := NewRegistry(source, time.Minute)
+
+	require.True(t, registry.Supported(utils.USD))
+	require.False(t, registry.Supported(utils.EUR))
+	require.False(t, registry.Supported(utils.CAD))
+	require.Equal(t, 1, source.calls)
+
+	// registered currency exponents are used for parsing
+	money, err := utils.ParseMoney("1.234", "KWD")
+	require.NoError(t, err)
+	require.Equal(t, int64(1234), money.Amount)
+
+	require.NoError(t, registry.CheckTransferAmount(utils.NewMoney(100, utils.USD)))
+	require.ErrorIs(t, registry.CheckTransferAmount(utils.NewMoney(99, utils.USD)), ErrAmountOutOfRange)
+	require.ErrorIs(t, registry.CheckTransferAmount(utils.NewMoney(10001, utils.USD)), ErrAmountOutOfRange)
+	require.ErrorIs(t, registry.CheckTransferAmount(utils.NewMoney(100, utils.EUR)), utils.ErrUnsupportedCurrency)
+
+	// cache is reused until invalidated
+	source.currencies[1].Enabled = true
+	require.False(t, registry.Supported(utils.EUR))
+	require.Equal(t, 1, source.calls)
+
+	registry.Invalidate()
+	require.True(t, registry.Supported(utils.EUR))
+	require.Equal(t, 2, source.calls)
+
+	// stale cache is kept when reload fails
+	source.err = errors.New("connection refused")
+	registry.Invalidate()
+	require.True(t, registry.Supported(utils.USD))
+	require.Equal(t, 3, source.calls)
+
+	// and the source isn't hit again until the backoff passes
+	require.True(t, registry.Supported(utils.USD))
+	require.False(t, registry.Supported(utils.CAD))
+	require.Equal(t, 3, source.calls)
+}
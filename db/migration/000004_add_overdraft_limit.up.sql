@@ -0,0 +1,10 @@
+ALTER TABLE "accounts" ADD COLUMN "overdraft_limit" bigint DEFAULT 0;
+
+COMMENT ON COLUMN "accounts"."overdraft_limit" IS 'null means unlimited overdraft';
+
+-- keep accounts that are already negative valid
+UPDATE "accounts" SET "overdraft_limit" = -"balance" WHERE "balance" < 0;
+
+ALTER TABLE "accounts" ADD CONSTRAINT "overdraft_limit_check" CHECK ("overdraft_limit" IS NULL OR "overdraft_limit" >= 0);
+
+ALTER TABLE "accounts" ADD CONSTRAINT "balance_overdraft_check" CHECK ("overdraft_limit" IS NULL OR "balance" >= -"overdraft_limit");
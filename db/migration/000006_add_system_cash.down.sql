@@ -0,0 +1,10 @@
+DELETE FROM "entries" WHERE "account_id" IN (SELECT "id" FROM "accounts" WHERE "owner" = 'system_cash');
+
+DELETE FROM "transfers" WHERE "from_account_id" IN (SELECT "id" FROM "accounts" WHERE "owner" = 'system_cash')
+  OR "to_account_id" IN (SELECT "id" FROM "accounts" WHERE "owner" = 'system_cash');
+
+DELETE FROM "accounts" WHERE "owner" = 'system_cash';
+
+DELETE FROM "users" WHERE "username" = 'system_cash';
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "role";
@@ -0,0 +1,5 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'depositor';
+
+-- owner of per-currency cash accounts, has no password so can't login
+INSERT INTO "users" ("username", "role", "hashed_password", "full_name", "email")
+VALUES ('system_cash', 'system', '', 'System Cash', 'cash@system.bankrupt');
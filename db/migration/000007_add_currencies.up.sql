@@ -0,0 +1,24 @@
+CREATE TABLE "currencies" (
+  "code" varchar PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "exponent" int NOT NULL,
+  "enabled" boolean NOT NULL DEFAULT true,
+  "min_transfer_amount" bigint NOT NULL DEFAULT 1,
+  "max_transfer_amount" bigint,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "currencies"."exponent" IS 'ISO 4217 minor unit digits';
+
+COMMENT ON COLUMN "currencies"."max_transfer_amount" IS 'null means no upper limit';
+
+ALTER TABLE "currencies" ADD CONSTRAINT "exponent_check" CHECK ("exponent" BETWEEN 0 AND 4);
+
+ALTER TABLE "currencies" ADD CONSTRAINT "transfer_amount_check" CHECK ("min_transfer_amount" > 0 AND ("max_transfer_amount" IS NULL OR "max_transfer_amount" >= "min_transfer_amount"));
+
+-- currencies that used to be hardcoded
+INSERT INTO "currencies" ("code", "name", "exponent")
+VALUES ('USD', 'US Dollar', 2), ('EUR', 'Euro', 2), ('CAD', 'Canadian Dollar', 2);
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
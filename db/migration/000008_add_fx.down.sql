@@ -0,0 +1,23 @@
+DELETE FROM "entries" WHERE "account_id" IN (SELECT "id" FROM "accounts" WHERE "owner" = 'system_fx');
+
+DELETE FROM "transfers" WHERE "fx_quote_id" IS NOT NULL;
+
+DELETE FROM "accounts" WHERE "owner" = 'system_fx';
+
+DELETE FROM "users" WHERE "username" = 'system_fx';
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "fx_quote_id";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "fx_spread_bps";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "fx_rate";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "to_currency";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "to_amount";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "currency";
+
+DROP TABLE IF EXISTS "fx_quotes";
+
+DROP TABLE IF EXISTS "fx_rates";
@@ -0,0 +1,68 @@
+CREATE TABLE "fx_rates" (
+  "from_currency" varchar NOT NULL,
+  "to_currency" varchar NOT NULL,
+  "rate" numeric(24,12) NOT NULL,
+  "spread_bps" int NOT NULL DEFAULT 50,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("from_currency", "to_currency")
+);
+
+COMMENT ON COLUMN "fx_rates"."rate" IS 'mid-market units of to_currency per unit of from_currency';
+
+COMMENT ON COLUMN "fx_rates"."spread_bps" IS 'bank margin in basis points taken off the mid rate';
+
+ALTER TABLE "fx_rates" ADD CONSTRAINT "fx_rate_check" CHECK ("rate" > 0 AND "spread_bps" BETWEEN 0 AND 10000 AND "from_currency" <> "to_currency");
+
+ALTER TABLE "fx_rates" ADD FOREIGN KEY ("from_currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "fx_rates" ADD FOREIGN KEY ("to_currency") REFERENCES "currencies" ("code");
+
+CREATE TABLE "fx_quotes" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "from_currency" varchar NOT NULL,
+  "to_currency" varchar NOT NULL,
+  "from_amount" bigint NOT NULL,
+  "to_amount" bigint NOT NULL,
+  "rate" numeric(24,12) NOT NULL,
+  "spread_bps" int NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "fx_quotes"."rate" IS 'applied rate with spread already taken off';
+
+CREATE INDEX ON "fx_quotes" ("expires_at");
+
+ALTER TABLE "fx_quotes" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "fx_quotes" ADD FOREIGN KEY ("from_currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "fx_quotes" ADD FOREIGN KEY ("to_currency") REFERENCES "currencies" ("code");
+
+-- transfers know their currency so either side can render them
+ALTER TABLE "transfers" ADD COLUMN "currency" varchar;
+
+UPDATE "transfers" SET "currency" = "accounts"."currency"
+FROM "accounts" WHERE "accounts"."id" = "transfers"."from_account_id";
+
+ALTER TABLE "transfers" ALTER COLUMN "currency" SET NOT NULL;
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "transfers" ADD COLUMN "to_amount" bigint;
+
+ALTER TABLE "transfers" ADD COLUMN "to_currency" varchar REFERENCES "currencies" ("code");
+
+ALTER TABLE "transfers" ADD COLUMN "fx_rate" numeric(24,12);
+
+ALTER TABLE "transfers" ADD COLUMN "fx_spread_bps" int;
+
+ALTER TABLE "transfers" ADD COLUMN "fx_quote_id" uuid REFERENCES "fx_quotes" ("id");
+
+COMMENT ON COLUMN "transfers"."to_amount" IS 'set for cross-currency transfers only';
+
+-- owner of per-currency fx house accounts that balance conversions
+INSERT INTO "users" ("username", "role", "hashed_password", "full_name", "email")
+VALUES ('system_fx', 'system', '', 'System FX', 'fx@system.bankrupt');
@@ -0,0 +1,17 @@
+-- keep only the latest rate of each pair
+DELETE FROM "fx_rates" r USING "fx_rates" newer
+WHERE r."from_currency" = newer."from_currency"
+  AND r."to_currency" = newer."to_currency"
+  AND r."effective_from" < newer."effective_from";
+
+ALTER TABLE IF EXISTS "fx_rates" DROP CONSTRAINT IF EXISTS "fx_rates_effective_key";
+
+ALTER TABLE IF EXISTS "fx_rates" RENAME COLUMN "created_at" TO "updated_at";
+
+ALTER TABLE IF EXISTS "fx_rates" DROP COLUMN IF EXISTS "source";
+
+ALTER TABLE IF EXISTS "fx_rates" DROP COLUMN IF EXISTS "effective_from";
+
+ALTER TABLE IF EXISTS "fx_rates" DROP COLUMN IF EXISTS "id";
+
+ALTER TABLE "fx_rates" ADD PRIMARY KEY ("from_currency", "to_currency");
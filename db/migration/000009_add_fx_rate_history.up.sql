@@ -0,0 +1,16 @@
+-- keep every rate so conversions can be explained after the fact
+ALTER TABLE "fx_rates" DROP CONSTRAINT "fx_rates_pkey";
+
+ALTER TABLE "fx_rates" ADD COLUMN "id" BIGSERIAL PRIMARY KEY;
+
+ALTER TABLE "fx_rates" ADD COLUMN "effective_from" timestamptz NOT NULL DEFAULT (now());
+
+ALTER TABLE "fx_rates" ADD COLUMN "source" varchar NOT NULL DEFAULT 'manual';
+
+UPDATE "fx_rates" SET "effective_from" = "updated_at";
+
+ALTER TABLE "fx_rates" RENAME COLUMN "updated_at" TO "created_at";
+
+ALTER TABLE "fx_rates" ADD CONSTRAINT "fx_rates_effective_key" UNIQUE ("from_currency", "to_currency", "effective_from");
+
+COMMENT ON COLUMN "fx_rates"."source" IS 'manual or name of rate provider';
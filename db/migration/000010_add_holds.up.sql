@@ -0,0 +1,36 @@
+CREATE TABLE "holds" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "captured_amount" bigint NOT NULL DEFAULT 0,
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "holds"."amount" IS 'reserved in account_id, must be positive';
+
+COMMENT ON COLUMN "holds"."status" IS 'active, captured, voided or expired';
+
+COMMENT ON COLUMN "holds"."transfer_id" IS 'set once the hold is captured';
+
+ALTER TABLE "holds" ADD CONSTRAINT "hold_amount_check" CHECK ("amount" > 0 AND "captured_amount" BETWEEN 0 AND "amount");
+
+ALTER TABLE "holds" ADD CONSTRAINT "hold_status_check" CHECK ("status" IN ('active', 'captured', 'voided', 'expired'));
+
+-- active holds are summed on every debit
+CREATE INDEX ON "holds" ("account_id") WHERE "status" = 'active';
+
+CREATE INDEX ON "holds" ("expires_at") WHERE "status" = 'active';
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
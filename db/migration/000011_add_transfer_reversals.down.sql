@@ -0,0 +1,6 @@
+-- reversals stay behind as ordinary transfers so balances still match entries
+ALTER TABLE IF EXISTS "transfers" DROP CONSTRAINT IF EXISTS "transfer_reversed_amount_check";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "reversed_amount";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "reversal_of";
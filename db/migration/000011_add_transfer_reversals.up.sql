@@ -0,0 +1,12 @@
+ALTER TABLE "transfers" ADD COLUMN "reversal_of" bigint REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfers" ADD COLUMN "reversed_amount" bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "transfers"."reversal_of" IS 'original transfer this one compensates';
+
+COMMENT ON COLUMN "transfers"."reversed_amount" IS 'sum of reversals posted against this transfer';
+
+-- reversals can never give back more than was sent
+ALTER TABLE "transfers" ADD CONSTRAINT "transfer_reversed_amount_check" CHECK ("reversed_amount" BETWEEN 0 AND "amount");
+
+CREATE INDEX ON "transfers" ("reversal_of");
@@ -0,0 +1,14 @@
+-- pending transfers never moved money, failed and cancelled ones never will
+DELETE FROM "transfers" WHERE "status" IN ('pending', 'failed', 'cancelled');
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "reversed_at";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "cancelled_at";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "failed_at";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "posted_at";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "failure_reason";
+
+ALTER TABLE IF EXISTS "transfers" DROP COLUMN IF EXISTS "status";
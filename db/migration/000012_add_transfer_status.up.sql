@@ -0,0 +1,29 @@
+ALTER TABLE "transfers" ADD COLUMN "status" varchar NOT NULL DEFAULT 'posted';
+
+ALTER TABLE "transfers" ADD COLUMN "failure_reason" varchar;
+
+ALTER TABLE "transfers" ADD COLUMN "posted_at" timestamptz;
+
+ALTER TABLE "transfers" ADD COLUMN "failed_at" timestamptz;
+
+ALTER TABLE "transfers" ADD COLUMN "cancelled_at" timestamptz;
+
+ALTER TABLE "transfers" ADD COLUMN "reversed_at" timestamptz;
+
+COMMENT ON COLUMN "transfers"."status" IS 'pending, posted, failed, reversed or cancelled';
+
+COMMENT ON COLUMN "transfers"."failure_reason" IS 'set for failed transfers only';
+
+-- every existing transfer was posted when it was created
+UPDATE "transfers" SET "posted_at" = "created_at";
+
+UPDATE "transfers" SET "status" = 'reversed', "reversed_at" = (
+  SELECT max("r"."created_at") FROM "transfers" "r" WHERE "r"."reversal_of" = "transfers"."id"
+)
+WHERE "reversed_amount" > 0 AND "reversed_amount" = "amount";
+
+ALTER TABLE "transfers" ADD CONSTRAINT "transfer_status_check" CHECK ("status" IN ('pending', 'posted', 'failed', 'reversed', 'cancelled'));
+
+ALTER TABLE "transfers" ADD CONSTRAINT "transfer_failure_reason_check" CHECK (("status" = 'failed') = ("failure_reason" IS NOT NULL));
+
+CREATE INDEX ON "transfers" ("status");
@@ -0,0 +1,62 @@
+CREATE TABLE "scheduled_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "schedule" varchar NOT NULL,
+  "start_at" timestamptz NOT NULL,
+  "end_at" timestamptz,
+  "next_run_at" timestamptz,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "scheduled_transfers"."schedule" IS 'cron spec, CRON_TZ= prefix picks the timezone';
+
+COMMENT ON COLUMN "scheduled_transfers"."next_run_at" IS 'null once the schedule has no runs left';
+
+COMMENT ON COLUMN "scheduled_transfers"."status" IS 'active, paused, finished or cancelled';
+
+ALTER TABLE "scheduled_transfers" ADD CONSTRAINT "scheduled_transfer_amount_check" CHECK ("amount" > 0 AND "from_account_id" <> "to_account_id");
+
+ALTER TABLE "scheduled_transfers" ADD CONSTRAINT "scheduled_transfer_status_check" CHECK ("status" IN ('active', 'paused', 'finished', 'cancelled'));
+
+CREATE INDEX ON "scheduled_transfers" ("owner");
+
+-- the worker only ever looks for due active schedules
+CREATE INDEX ON "scheduled_transfers" ("next_run_at") WHERE "status" = 'active';
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+CREATE TABLE "scheduled_transfer_runs" (
+  "id" bigserial PRIMARY KEY,
+  "scheduled_transfer_id" bigint NOT NULL,
+  "scheduled_for" timestamptz NOT NULL,
+  "status" varchar NOT NULL,
+  "transfer_id" bigint,
+  "failure_reason" varchar,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "scheduled_transfer_runs"."status" IS 'posted or failed';
+
+ALTER TABLE "scheduled_transfer_runs" ADD CONSTRAINT "scheduled_transfer_run_status_check" CHECK (
+  ("status" = 'posted' AND "transfer_id" IS NOT NULL AND "failure_reason" IS NULL) OR
+  ("status" = 'failed' AND "transfer_id" IS NULL AND "failure_reason" IS NOT NULL)
+);
+
+-- each occurrence runs once
+CREATE UNIQUE INDEX ON "scheduled_transfer_runs" ("scheduled_transfer_id", "scheduled_for");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("scheduled_transfer_id") REFERENCES "scheduled_transfers" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
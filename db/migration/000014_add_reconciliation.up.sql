@@ -0,0 +1,34 @@
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint REFERENCES "transfers" ("id");
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'transfer that posted the entry, null for legacy or orphan entries';
+
+-- entries and their transfer were written in one transaction and share now()
+UPDATE "entries" "e" SET "transfer_id" = "t"."id"
+FROM "transfers" "t"
+WHERE "e"."created_at" = "t"."created_at" AND (
+  "e"."account_id" IN ("t"."from_account_id", "t"."to_account_id") OR
+  ("t"."fx_quote_id" IS NOT NULL AND "e"."account_id" IN (SELECT "id" FROM "accounts" WHERE "owner" = 'system_fx'))
+);
+
+CREATE INDEX ON "entries" ("transfer_id");
+
+CREATE TABLE "reconciliation_runs" (
+  "id" bigserial PRIMARY KEY,
+  "source" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'running',
+  "accounts_checked" bigint NOT NULL DEFAULT 0,
+  "transfers_checked" bigint NOT NULL DEFAULT 0,
+  "critical_findings" int NOT NULL DEFAULT 0,
+  "findings" jsonb NOT NULL DEFAULT '[]',
+  "error" varchar,
+  "started_at" timestamptz NOT NULL DEFAULT (now()),
+  "finished_at" timestamptz
+);
+
+COMMENT ON COLUMN "reconciliation_runs"."source" IS 'task or cli';
+
+COMMENT ON COLUMN "reconciliation_runs"."status" IS 'running, clean, warning, critical or errored';
+
+ALTER TABLE "reconciliation_runs" ADD CONSTRAINT "reconciliation_run_status_check" CHECK ("status" IN ('running', 'clean', 'warning', 'critical', 'errored'));
+
+CREATE INDEX ON "reconciliation_runs" ("started_at");
@@ -0,0 +1,22 @@
+ALTER TABLE "entries" ADD COLUMN "balance_after" bigint;
+
+COMMENT ON COLUMN "entries"."balance_after" IS 'account balance right after the entry was posted';
+
+-- walk back from the current balance, accounts may have been opened with a balance
+UPDATE "entries" "e" SET "balance_after" = "r"."balance_after"
+FROM (
+  SELECT
+    "e"."id",
+    "a"."balance" - COALESCE(SUM("e"."amount") OVER (
+      PARTITION BY "e"."account_id"
+      ORDER BY "e"."created_at" DESC, "e"."id" DESC
+      ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+    ), 0) AS "balance_after"
+  FROM "entries" "e"
+  JOIN "accounts" "a" ON "a"."id" = "e"."account_id"
+) "r"
+WHERE "e"."id" = "r"."id";
+
+ALTER TABLE "entries" ALTER COLUMN "balance_after" SET NOT NULL;
+
+CREATE INDEX ON "entries" ("account_id", "created_at", "id");
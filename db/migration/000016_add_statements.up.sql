@@ -0,0 +1,21 @@
+CREATE TABLE "statements" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "period_start" timestamptz NOT NULL,
+  "period_end" timestamptz NOT NULL,
+  "opening_balance" bigint NOT NULL,
+  "closing_balance" bigint NOT NULL,
+  "entry_count" int NOT NULL,
+  "emailed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "statements"."period_end" IS 'exclusive';
+
+COMMENT ON COLUMN "statements"."emailed_at" IS 'null until sent to the owner';
+
+ALTER TABLE "statements" ADD CONSTRAINT "statement_period_check" CHECK ("period_end" > "period_start");
+
+ALTER TABLE "statements" ADD CONSTRAINT "account_period_key" UNIQUE ("account_id", "period_start", "period_end");
+
+ALTER TABLE "statements" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS "transfers_to_account_id_amount_id_idx";
+
+DROP INDEX IF EXISTS "transfers_from_account_id_amount_id_idx";
+
+DROP INDEX IF EXISTS "transfers_memo_search_idx";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "memo";
@@ -0,0 +1,10 @@
+ALTER TABLE "transfers" ADD COLUMN "memo" varchar NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN "transfers"."memo" IS 'free text from the sender, searchable';
+
+CREATE INDEX "transfers_memo_search_idx" ON "transfers" USING gin (to_tsvector('simple', "memo"));
+
+-- amount range and sorting within an account
+CREATE INDEX ON "transfers" ("from_account_id", "amount", "id");
+
+CREATE INDEX ON "transfers" ("to_account_id", "amount", "id");
@@ -0,0 +1,41 @@
+ALTER TABLE "users" ADD COLUMN "tier" varchar NOT NULL DEFAULT 'standard';
+
+CREATE TABLE "transfer_limits" (
+  "id" bigserial PRIMARY KEY,
+  "tier" varchar,
+  "account_id" bigint,
+  "currency" varchar,
+  "max_per_transfer" bigint,
+  "max_daily" bigint,
+  "max_weekly" bigint,
+  "max_monthly" bigint,
+  "max_hourly_count" bigint,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "transfer_limits"."tier" IS 'set for tier limits, global when tier and account_id are null';
+
+COMMENT ON COLUMN "transfer_limits"."account_id" IS 'set for account limits';
+
+COMMENT ON COLUMN "transfer_limits"."currency" IS 'amount limits need a currency, count limits have none';
+
+COMMENT ON COLUMN "transfer_limits"."max_daily" IS 'rolling 24 hours';
+
+COMMENT ON COLUMN "transfer_limits"."max_weekly" IS 'rolling 7 days';
+
+COMMENT ON COLUMN "transfer_limits"."max_monthly" IS 'rolling 30 days';
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "transfer_limit_target_check" CHECK ("tier" IS NULL OR "account_id" IS NULL);
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "transfer_limit_amount_check" CHECK (
+  "currency" IS NOT NULL OR ("max_per_transfer" IS NULL AND "max_daily" IS NULL AND "max_weekly" IS NULL AND "max_monthly" IS NULL)
+);
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "transfer_limit_count_check" CHECK ("currency" IS NULL OR "max_hourly_count" IS NULL);
+
+ALTER TABLE "transfer_limits" ADD CONSTRAINT "transfer_limit_key" UNIQUE NULLS NOT DISTINCT ("tier", "account_id", "currency");
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "transfer_limits" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
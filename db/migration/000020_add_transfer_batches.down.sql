@@ -0,0 +1,3 @@
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "batch_id";
+
+DROP TABLE IF EXISTS "transfer_batches";
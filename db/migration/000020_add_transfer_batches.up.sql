@@ -0,0 +1,29 @@
+CREATE TABLE "transfer_batches" (
+  "id" bigserial PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "mode" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'running',
+  "leg_count" int NOT NULL,
+  "posted_count" int NOT NULL DEFAULT 0,
+  "failed_count" int NOT NULL DEFAULT 0,
+  "finished_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "transfer_batches"."mode" IS 'atomic posts all legs or none, chunked posts what it can';
+
+COMMENT ON COLUMN "transfer_batches"."status" IS 'running, posted, partial or failed';
+
+ALTER TABLE "transfer_batches" ADD CONSTRAINT "transfer_batch_mode_check" CHECK ("mode" IN ('atomic', 'chunked'));
+
+ALTER TABLE "transfer_batches" ADD CONSTRAINT "transfer_batch_status_check" CHECK ("status" IN ('running', 'posted', 'partial', 'failed'));
+
+ALTER TABLE "transfer_batches" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+CREATE INDEX ON "transfer_batches" ("from_account_id");
+
+ALTER TABLE "transfers" ADD COLUMN "batch_id" bigint REFERENCES "transfer_batches" ("id");
+
+COMMENT ON COLUMN "transfers"."batch_id" IS 'set for legs of a batch transfer';
+
+CREATE INDEX ON "transfers" ("batch_id");
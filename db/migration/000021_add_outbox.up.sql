@@ -0,0 +1,22 @@
+CREATE TABLE "outbox_messages" (
+  "id" bigserial PRIMARY KEY,
+  "task_type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "queue" varchar NOT NULL,
+  "max_retry" int NOT NULL DEFAULT 0,
+  "timeout_seconds" int NOT NULL DEFAULT 0,
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "available_at" timestamptz NOT NULL DEFAULT (now()),
+  "published_at" timestamptz,
+  "dead_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "outbox_messages"."available_at" IS 'not published before, pushed back after a failed attempt';
+
+COMMENT ON COLUMN "outbox_messages"."dead_at" IS 'set when the relay gave up publishing';
+
+CREATE INDEX ON "outbox_messages" ("id") WHERE "published_at" IS NULL AND "dead_at" IS NULL;
+
+CREATE INDEX ON "outbox_messages" ("published_at");
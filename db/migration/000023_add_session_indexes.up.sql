@@ -0,0 +1,3 @@
+CREATE INDEX ON "sessions" ("username", "expires_at");
+
+CREATE INDEX ON "sessions" ("expires_at");
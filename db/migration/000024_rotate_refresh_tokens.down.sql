@@ -0,0 +1,12 @@
+-- raw tokens can't be recovered from hashes, so existing sessions are revoked
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "refresh_token" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "sessions" ALTER COLUMN "refresh_token" DROP DEFAULT;
+
+UPDATE "sessions" SET "is_blocked" = TRUE;
+
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "rotated_at";
+
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "refresh_token_hash";
+
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "family_id";
@@ -0,0 +1,17 @@
+ALTER TABLE "sessions" ADD COLUMN "family_id" uuid;
+
+ALTER TABLE "sessions" ADD COLUMN "refresh_token_hash" varchar;
+
+ALTER TABLE "sessions" ADD COLUMN "rotated_at" timestamptz;
+
+UPDATE "sessions"
+SET "family_id" = "id",
+    "refresh_token_hash" = encode(sha256(convert_to("refresh_token", 'UTF8')), 'hex');
+
+ALTER TABLE "sessions" ALTER COLUMN "family_id" SET NOT NULL;
+
+ALTER TABLE "sessions" ALTER COLUMN "refresh_token_hash" SET NOT NULL;
+
+ALTER TABLE "sessions" DROP COLUMN "refresh_token";
+
+CREATE INDEX ON "sessions" ("family_id");
@@ -0,0 +1,7 @@
+ALTER TABLE "transfer_batches" DROP CONSTRAINT IF EXISTS "transfer_batch_idempotency_key";
+
+ALTER TABLE "transfer_batches" DROP COLUMN IF EXISTS "request_hash";
+
+ALTER TABLE "transfer_batches" DROP COLUMN IF EXISTS "idempotency_key";
+
+ALTER TABLE "transfer_batches" DROP COLUMN IF EXISTS "owner";
@@ -0,0 +1,18 @@
+ALTER TABLE "transfer_batches" ADD COLUMN "owner" varchar;
+
+UPDATE "transfer_batches" b
+SET "owner" = a."owner"
+FROM "accounts" a
+WHERE a."id" = b."from_account_id";
+
+ALTER TABLE "transfer_batches" ALTER COLUMN "owner" SET NOT NULL;
+
+ALTER TABLE "transfer_batches" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_batches" ADD COLUMN "idempotency_key" varchar;
+
+ALTER TABLE "transfer_batches" ADD COLUMN "request_hash" varchar;
+
+COMMENT ON COLUMN "transfer_batches"."idempotency_key" IS 'retried requests with the same key get this batch back';
+
+ALTER TABLE "transfer_batches" ADD CONSTRAINT "transfer_batch_idempotency_key" UNIQUE ("owner", "idempotency_key");
@@ -22,6 +22,13 @@ ORDER BY id
 LIMIT $2
 OFFSET $3;
 
+-- name: ListAccountsPage :many
+SELECT * FROM accounts
+WHERE owner = sqlc.arg(owner)
+  AND (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(limit_count);
+
 -- name: UpdateAccount :one
 UPDATE accounts
 SET balance = $2
@@ -36,4 +43,27 @@ RETURNING *;
 
 -- name: DeleteAccount :exec
 DELETE FROM accounts
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = sqlc.narg(overdraft_limit)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateSystemAccount :one
+INSERT INTO accounts (
+  owner, balance, currency, overdraft_limit
+) VALUES (
+  $1, 0, $2, NULL
+)
+ON CONFLICT (owner, currency) DO UPDATE
+SET owner = EXCLUDED.owner
+RETURNING *;
+
+-- name: GetAccountsForUpdate :many
+-- lock in id order like pairwise transfers do, so batches can't deadlock with them
+SELECT * FROM accounts
+WHERE id = ANY(sqlc.arg(ids)::bigint[])
+ORDER BY id
+FOR NO KEY UPDATE;
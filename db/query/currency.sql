@@ -0,0 +1,29 @@
+-- name: CreateCurrency :one
+INSERT INTO currencies (
+  code, name, exponent, enabled, min_transfer_amount, max_transfer_amount
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetCurrency :one
+SELECT * FROM currencies
+WHERE code = $1 LIMIT 1;
+
+-- name: ListCurrencies :many
+SELECT * FROM currencies
+ORDER BY code;
+
+-- name: UpdateCurrency :one
+UPDATE currencies
+SET
+  name = COALESCE(sqlc.narg(name), name),
+  enabled = COALESCE(sqlc.narg(enabled), enabled),
+  min_transfer_amount = COALESCE(sqlc.narg(min_transfer_amount), min_transfer_amount),
+  max_transfer_amount = CASE
+    WHEN sqlc.arg(set_max_transfer_amount)::boolean THEN sqlc.narg(max_transfer_amount)
+    ELSE max_transfer_amount
+  END,
+  updated_at = now()
+WHERE code = sqlc.arg(code)
+RETURNING *;
@@ -1,8 +1,9 @@
 -- name: CreateEntry :one
+-- clock time rather than tx start, so entries of a locked account sort in posting order
 INSERT INTO entries (
-  account_id, amount
+  account_id, amount, transfer_id, balance_after, created_at
 ) VALUES (
-  $1, $2
+  $1, $2, $3, $4, clock_timestamp()
 )
 RETURNING *;
 
@@ -15,4 +16,42 @@ SELECT * FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
-OFFSET $3;
\ No newline at end of file
+OFFSET $3;
+
+-- name: ListEntriesPage :many
+SELECT * FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(limit_count);
+
+-- name: ListStatementEntries :many
+SELECT
+  e.id, e.account_id, e.amount, e.balance_after, e.transfer_id, e.created_at,
+  COALESCE(CASE
+    WHEN t.from_account_id = e.account_id THEN t.to_account_id
+    ELSE t.from_account_id
+  END, 0)::bigint AS counterparty_account_id
+FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE e.account_id = sqlc.arg(account_id)
+  AND e.created_at >= sqlc.arg(from_time)::timestamptz
+  AND e.created_at < sqlc.arg(to_time)::timestamptz
+  AND e.id > sqlc.arg(after_id)::bigint
+-- ids follow posting order within an account, tx start times do not
+ORDER BY e.id
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count); -- deprecated, only for page_id requests
+
+-- name: GetBalanceBefore :one
+-- last entry before the time, else the opening balance of the first entry, else no entries at all,
+-- ordered like the (account_id, created_at, id) index so each lookup reads a single row
+SELECT COALESCE(
+  (SELECT e.balance_after FROM entries e
+   WHERE e.account_id = sqlc.arg(account_id) AND e.created_at < sqlc.arg(before)::timestamptz
+   ORDER BY e.created_at DESC, e.id DESC LIMIT 1),
+  (SELECT e.balance_after - e.amount FROM entries e
+   WHERE e.account_id = sqlc.arg(account_id)
+   ORDER BY e.created_at, e.id LIMIT 1),
+  (SELECT a.balance FROM accounts a WHERE a.id = sqlc.arg(account_id))
+)::bigint AS balance;
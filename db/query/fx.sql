@@ -0,0 +1,51 @@
+-- name: CreateFXRate :one
+INSERT INTO fx_rates (
+  from_currency, to_currency, rate, spread_bps, effective_from, source
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (from_currency, to_currency, effective_from) DO NOTHING
+RETURNING *;
+
+-- name: GetFXRate :one
+SELECT * FROM fx_rates
+WHERE from_currency = $1 AND to_currency = $2 AND effective_from <= now()
+ORDER BY effective_from DESC
+LIMIT 1;
+
+-- name: GetFXRateAt :one
+SELECT * FROM fx_rates
+WHERE from_currency = $1 AND to_currency = $2 AND effective_from <= sqlc.arg(at)
+ORDER BY effective_from DESC
+LIMIT 1;
+
+-- name: ListFXRates :many
+SELECT DISTINCT ON (from_currency, to_currency) * FROM fx_rates
+WHERE effective_from <= now()
+ORDER BY from_currency, to_currency, effective_from DESC;
+
+-- name: ListFXRateHistory :many
+SELECT * FROM fx_rates
+WHERE from_currency = $1 AND to_currency = $2
+ORDER BY effective_from DESC
+LIMIT $3
+OFFSET $4;
+
+-- name: CreateFXQuote :one
+INSERT INTO fx_quotes (
+  id, username, from_currency, to_currency, from_amount, to_amount, rate, spread_bps, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetFXQuoteForUpdate :one
+SELECT * FROM fx_quotes
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: MarkFXQuoteUsed :one
+UPDATE fx_quotes
+SET used_at = now()
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,40 @@
+-- name: CreateOutboxMessage :one
+INSERT INTO outbox_messages (
+  task_type, payload, queue, max_retry, timeout_seconds
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetOutboxMessage :one
+SELECT * FROM outbox_messages
+WHERE id = $1 LIMIT 1;
+
+-- name: ListPendingOutboxMessages :many
+-- rows stay claimed until the transaction ends, other relays skip them
+SELECT * FROM outbox_messages
+WHERE published_at IS NULL AND dead_at IS NULL
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxMessagePublished :exec
+UPDATE outbox_messages
+SET
+  attempts = attempts + 1,
+  published_at = now()
+WHERE id = $1;
+
+-- name: MarkOutboxMessageFailed :one
+UPDATE outbox_messages
+SET
+  attempts = attempts + 1,
+  last_error = sqlc.arg(last_error),
+  available_at = sqlc.arg(available_at),
+  dead_at = CASE WHEN sqlc.arg(dead)::bool THEN now() END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeletePublishedOutboxMessages :execrows
+DELETE FROM outbox_messages
+WHERE published_at < sqlc.arg(published_before)::timestamptz;
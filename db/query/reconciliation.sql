@@ -0,0 +1,55 @@
+-- name: ReconcileAccounts :many
+SELECT
+  a.id, a.balance, a.currency,
+  COALESCE(SUM(e.amount), 0)::bigint AS entries_sum
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+WHERE a.id > sqlc.arg(after_id)
+GROUP BY a.id
+ORDER BY a.id
+LIMIT sqlc.arg(limit_count);
+
+-- name: ReconcileTransfers :many
+SELECT
+  t.id, t.status, (t.to_amount IS NOT NULL)::bool AS converted,
+  COUNT(e.id)::bigint AS entry_count,
+  COALESCE(SUM(e.amount) FILTER (WHERE a.currency = t.currency), 0)::bigint AS currency_sum,
+  COALESCE(SUM(e.amount) FILTER (WHERE a.currency <> t.currency), 0)::bigint AS to_currency_sum
+FROM transfers t
+LEFT JOIN entries e ON e.transfer_id = t.id
+LEFT JOIN accounts a ON a.id = e.account_id
+WHERE t.id > sqlc.arg(after_id)
+GROUP BY t.id
+ORDER BY t.id
+LIMIT sqlc.arg(limit_count);
+
+-- name: ListOrphanEntries :many
+SELECT * FROM entries
+WHERE transfer_id IS NULL AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(limit_count);
+
+-- name: CreateReconciliationRun :one
+INSERT INTO reconciliation_runs (
+  source
+) VALUES (
+  $1
+)
+RETURNING *;
+
+-- name: FinishReconciliationRun :one
+UPDATE reconciliation_runs
+SET
+  status = sqlc.arg(status),
+  accounts_checked = sqlc.arg(accounts_checked),
+  transfers_checked = sqlc.arg(transfers_checked),
+  critical_findings = sqlc.arg(critical_findings),
+  findings = sqlc.arg(findings),
+  error = sqlc.narg(error),
+  finished_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetReconciliationRun :one
+SELECT * FROM reconciliation_runs
+WHERE id = $1 LIMIT 1;
@@ -1,11 +1,54 @@
 -- name: CreateSession :one
 INSERT INTO sessions (
-  id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at
+  id, family_id, username, refresh_token_hash, user_agent, client_ip, is_blocked, expires_at
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7, $8
 )
 RETURNING *;
 
 -- name: GetSession :one
 SELECT * FROM sessions
-WHERE id = $1 LIMIT 1;
\ No newline at end of file
+WHERE id = $1 LIMIT 1;
+
+-- name: GetSessionForUpdate :one
+SELECT * FROM sessions
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: RotateSession :one
+UPDATE sessions
+SET rotated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListActiveSessions :many
+SELECT * FROM sessions
+WHERE username = $1
+  AND is_blocked = FALSE
+  AND rotated_at IS NULL
+  AND expires_at > now()
+ORDER BY created_at DESC, id;
+
+-- name: BlockSession :one
+UPDATE sessions
+SET is_blocked = TRUE
+WHERE id = $1
+RETURNING *;
+
+-- name: BlockUserSessions :execrows
+UPDATE sessions
+SET is_blocked = TRUE
+WHERE username = $1
+  AND is_blocked = FALSE
+  AND rotated_at IS NULL
+  AND expires_at > now();
+
+-- name: BlockSessionFamily :execrows
+UPDATE sessions
+SET is_blocked = TRUE
+WHERE family_id = $1
+  AND is_blocked = FALSE;
+
+-- name: DeleteExpiredSessions :execrows
+DELETE FROM sessions
+WHERE expires_at < sqlc.arg(expired_before)::timestamptz;
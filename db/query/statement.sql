@@ -0,0 +1,35 @@
+-- name: CreateStatement :one
+-- ledger is append only, so a rebuilt statement for the same period is identical
+INSERT INTO statements (
+  account_id, period_start, period_end, opening_balance, closing_balance, entry_count
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (account_id, period_start, period_end) DO UPDATE
+SET entry_count = EXCLUDED.entry_count
+RETURNING *;
+
+-- name: GetStatement :one
+SELECT * FROM statements
+WHERE id = $1 LIMIT 1;
+
+-- name: ListStatements :many
+SELECT * FROM statements
+WHERE account_id = $1
+ORDER BY period_start DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: MarkStatementEmailed :one
+UPDATE statements
+SET emailed_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListStatementAccounts :many
+SELECT * FROM accounts
+WHERE id > sqlc.arg(after_id)
+  AND owner NOT IN ('system_cash', 'system_fx')
+  AND created_at < sqlc.arg(created_before)::timestamptz
+ORDER BY id
+LIMIT sqlc.arg(limit_count);
@@ -1,8 +1,25 @@
 -- name: CreateTransfer :one
 INSERT INTO transfers (
-  from_account_id, to_account_id, amount
+  from_account_id, to_account_id, amount, currency, reversal_of, memo, batch_id, status, posted_at
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, sqlc.narg(reversal_of), sqlc.arg(memo), sqlc.narg(batch_id), 'posted', now()
+)
+RETURNING *;
+
+-- name: CreatePendingTransfer :one
+INSERT INTO transfers (
+  from_account_id, to_account_id, amount, currency, status
+) VALUES (
+  $1, $2, $3, $4, 'pending'
+)
+RETURNING *;
+
+-- name: CreateFXTransfer :one
+INSERT INTO transfers (
+  from_account_id, to_account_id, amount, currency,
+  to_amount, to_currency, fx_rate, fx_spread_bps, fx_quote_id, memo, status, posted_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 'posted', now()
 )
 RETURNING *;
 
@@ -10,11 +27,219 @@ RETURNING *;
 SELECT * FROM transfers
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: AddTransferReversedAmount :one
+UPDATE transfers
+SET reversed_amount = reversed_amount + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListTransferReversals :many
+SELECT * FROM transfers
+WHERE reversal_of = $1
+ORDER BY id;
+
+-- name: UpdateTransferStatus :one
+UPDATE transfers
+SET
+  status = sqlc.arg(status),
+  failure_reason = sqlc.narg(failure_reason),
+  posted_at = CASE WHEN sqlc.arg(status) = 'posted' THEN now() ELSE posted_at END,
+  failed_at = CASE WHEN sqlc.arg(status) = 'failed' THEN now() ELSE failed_at END,
+  cancelled_at = CASE WHEN sqlc.arg(status) = 'cancelled' THEN now() ELSE cancelled_at END,
+  reversed_at = CASE WHEN sqlc.arg(status) = 'reversed' THEN now() ELSE reversed_at END
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status)
+RETURNING *;
+
 -- name: ListTransfers :many
 SELECT * FROM transfers
 WHERE
-    from_account_id = $1 OR
-    to_account_id = $2
+    (from_account_id = $1 OR to_account_id = $2) AND
+    (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
 ORDER BY id
 LIMIT $3
-OFFSET $4;
\ No newline at end of file
+OFFSET $4;
+
+-- name: ListTransfersPage :many
+SELECT * FROM transfers
+WHERE
+    (from_account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id)) AND
+    (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status)) AND
+    (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+ORDER BY created_at, id
+LIMIT sqlc.arg(limit_count);
+
+-- name: SearchTransfersByCreatedAt :many
+-- one query per sort order, outgoing and incoming are separate branches so each can walk
+-- its account index, incoming skips transfers already listed as outgoing and matches the
+-- currency it was credited in
+WITH owned AS (
+  SELECT a.id FROM accounts a
+  WHERE
+    a.owner = sqlc.arg(owner) AND
+    (sqlc.narg(account_id)::bigint IS NULL OR a.id = sqlc.narg(account_id))
+)
+(SELECT t.* FROM transfers t
+  WHERE
+    sqlc.arg(outgoing)::bool AND
+    t.from_account_id IN (SELECT id FROM owned) AND
+    (sqlc.narg(counterparty_id)::bigint IS NULL OR t.to_account_id = sqlc.narg(counterparty_id)) AND
+    (sqlc.narg(currency)::varchar IS NULL OR t.currency = sqlc.narg(currency)) AND
+    (sqlc.narg(min_amount)::bigint IS NULL OR t.amount >= sqlc.narg(min_amount)) AND
+    (sqlc.narg(max_amount)::bigint IS NULL OR t.amount <= sqlc.narg(max_amount)) AND
+    (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time)) AND
+    (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time)) AND
+    (sqlc.narg(status)::varchar IS NULL OR t.status = sqlc.narg(status)) AND
+    (sqlc.narg(query)::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', sqlc.narg(query))) AND
+    (sqlc.narg(after_id)::bigint IS NULL OR (t.created_at, t.id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.narg(after_id)))
+  ORDER BY t.created_at, t.id
+  LIMIT sqlc.arg(limit_count))
+UNION ALL
+(SELECT t.* FROM transfers t
+  WHERE
+    sqlc.arg(incoming)::bool AND
+    t.to_account_id IN (SELECT id FROM owned) AND
+    NOT (sqlc.arg(outgoing)::bool AND t.from_account_id IN (SELECT id FROM owned)) AND
+    (sqlc.narg(counterparty_id)::bigint IS NULL OR t.from_account_id = sqlc.narg(counterparty_id)) AND
+    (sqlc.narg(currency)::varchar IS NULL OR COALESCE(t.to_currency, t.currency) = sqlc.narg(currency)) AND
+    (sqlc.narg(min_amount)::bigint IS NULL OR t.amount >= sqlc.narg(min_amount)) AND
+    (sqlc.narg(max_amount)::bigint IS NULL OR t.amount <= sqlc.narg(max_amount)) AND
+    (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time)) AND
+    (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time)) AND
+    (sqlc.narg(status)::varchar IS NULL OR t.status = sqlc.narg(status)) AND
+    (sqlc.narg(query)::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', sqlc.narg(query))) AND
+    (sqlc.narg(after_id)::bigint IS NULL OR (t.created_at, t.id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.narg(after_id)))
+  ORDER BY t.created_at, t.id
+  LIMIT sqlc.arg(limit_count))
+ORDER BY created_at, id
+LIMIT sqlc.arg(limit_count);
+
+-- name: SearchTransfersByCreatedAtDesc :many
+WITH owned AS (
+  SELECT a.id FROM accounts a
+  WHERE
+    a.owner = sqlc.arg(owner) AND
+    (sqlc.narg(account_id)::bigint IS NULL OR a.id = sqlc.narg(account_id))
+)
+(SELECT t.* FROM transfers t
+  WHERE
+    sqlc.arg(outgoing)::bool AND
+    t.from_account_id IN (SELECT id FROM owned) AND
+    (sqlc.narg(counterparty_id)::bigint IS NULL OR t.to_account_id = sqlc.narg(counterparty_id)) AND
+    (sqlc.narg(currency)::varchar IS NULL OR t.currency = sqlc.narg(currency)) AND
+    (sqlc.narg(min_amount)::bigint IS NULL OR t.amount >= sqlc.narg(min_amount)) AND
+    (sqlc.narg(max_amount)::bigint IS NULL OR t.amount <= sqlc.narg(max_amount)) AND
+    (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time)) AND
+    (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time)) AND
+    (sqlc.narg(status)::varchar IS NULL OR t.status = sqlc.narg(status)) AND
+    (sqlc.narg(query)::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', sqlc.narg(query))) AND
+    (sqlc.narg(after_id)::bigint IS NULL OR (t.created_at, t.id) < (sqlc.arg(after_created_at)::timestamptz, sqlc.narg(after_id)))
+  ORDER BY t.created_at DESC, t.id DESC
+  LIMIT sqlc.arg(limit_count))
+UNION ALL
+(SELECT t.* FROM transfers t
+  WHERE
+    sqlc.arg(incoming)::bool AND
+    t.to_account_id IN (SELECT id FROM owned) AND
+    NOT (sqlc.arg(outgoing)::bool AND t.from_account_id IN (SELECT id FROM owned)) AND
+    (sqlc.narg(counterparty_id)::bigint IS NULL OR t.from_account_id = sqlc.narg(counterparty_id)) AND
+    (sqlc.narg(currency)::varchar IS NULL OR COALESCE(t.to_currency, t.currency) = sqlc.narg(currency)) AND
+    (sqlc.narg(min_amount)::bigint IS NULL OR t.amount >= sqlc.narg(min_amount)) AND
+    (sqlc.narg(max_amount)::bigint IS NULL OR t.amount <= sqlc.narg(max_amount)) AND
+    (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time)) AND
+    (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time)) AND
+    (sqlc.narg(status)::varchar IS NULL OR t.status = sqlc.narg(status)) AND
+    (sqlc.narg(query)::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', sqlc.narg(query))) AND
+    (sqlc.narg(after_id)::bigint IS NULL OR (t.created_at, t.id) < (sqlc.arg(after_created_at)::timestamptz, sqlc.narg(after_id)))
+  ORDER BY t.created_at DESC, t.id DESC
+  LIMIT sqlc.arg(limit_count))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(limit_count);
+
+-- name: SearchTransfersByAmount :many
+WITH owned AS (
+  SELECT a.id FROM accounts a
+  WHERE
+    a.owner = sqlc.arg(owner) AND
+    (sqlc.narg(account_id)::bigint IS NULL OR a.id = sqlc.narg(account_id))
+)
+(SELECT t.* FROM transfers t
+  WHERE
+    sqlc.arg(outgoing)::bool AND
+    t.from_account_id IN (SELECT id FROM owned) AND
+    (sqlc.narg(counterparty_id)::bigint IS NULL OR t.to_account_id = sqlc.narg(counterparty_id)) AND
+    (sqlc.narg(currency)::varchar IS NULL OR t.currency = sqlc.narg(currency)) AND
+    (sqlc.narg(min_amount)::bigint IS NULL OR t.amount >= sqlc.narg(min_amount)) AND
+    (sqlc.narg(max_amount)::bigint IS NULL OR t.amount <= sqlc.narg(max_amount)) AND
+    (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time)) AND
+    (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time)) AND
+    (sqlc.narg(status)::varchar IS NULL OR t.status = sqlc.narg(status)) AND
+    (sqlc.narg(query)::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', sqlc.narg(query))) AND
+    (sqlc.narg(after_id)::bigint IS NULL OR (t.amount, t.id) > (sqlc.arg(after_amount)::bigint, sqlc.narg(after_id)))
+  ORDER BY t.amount, t.id
+  LIMIT sqlc.arg(limit_count))
+UNION ALL
+(SELECT t.* FROM transfers t
+  WHERE
+    sqlc.arg(incoming)::bool AND
+    t.to_account_id IN (SELECT id FROM owned) AND
+    NOT (sqlc.arg(outgoing)::bool AND t.from_account_id IN (SELECT id FROM owned)) AND
+    (sqlc.narg(counterparty_id)::bigint IS NULL OR t.from_account_id = sqlc.narg(counterparty_id)) AND
+    (sqlc.narg(currency)::varchar IS NULL OR COALESCE(t.to_currency, t.currency) = sqlc.narg(currency)) AND
+    (sqlc.narg(min_amount)::bigint IS NULL OR t.amount >= sqlc.narg(min_amount)) AND
+    (sqlc.narg(max_amount)::bigint IS NULL OR t.amount <= sqlc.narg(max_amount)) AND
+    (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time)) AND
+    (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time)) AND
+    (sqlc.narg(status)::varchar IS NULL OR t.status = sqlc.narg(status)) AND
+    (sqlc.narg(query)::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', sqlc.narg(query))) AND
+    (sqlc.narg(after_id)::bigint IS NULL OR (t.amount, t.id) > (sqlc.arg(after_amount)::bigint, sqlc.narg(after_id)))
+  ORDER BY t.amount, t.id
+  LIMIT sqlc.arg(limit_count))
+ORDER BY amount, id
+LIMIT sqlc.arg(limit_count);
+
+-- name: SearchTransfersByAmountDesc :many
+WITH owned AS (
+  SELECT a.id FROM accounts a
+  WHERE
+    a.owner = sqlc.arg(owner) AND
+    (sqlc.narg(account_id)::bigint IS NULL OR a.id = sqlc.narg(account_id))
+)
+(SELECT t.* FROM transfers t
+  WHERE
+    sqlc.arg(outgoing)::bool AND
+    t.from_account_id IN (SELECT id FROM owned) AND
+    (sqlc.narg(counterparty_id)::bigint IS NULL OR t.to_account_id = sqlc.narg(counterparty_id)) AND
+    (sqlc.narg(currency)::varchar IS NULL OR t.currency = sqlc.narg(currency)) AND
+    (sqlc.narg(min_amount)::bigint IS NULL OR t.amount >= sqlc.narg(min_amount)) AND
+    (sqlc.narg(max_amount)::bigint IS NULL OR t.amount <= sqlc.narg(max_amount)) AND
+    (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time)) AND
+    (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time)) AND
+    (sqlc.narg(status)::varchar IS NULL OR t.status = sqlc.narg(status)) AND
+    (sqlc.narg(query)::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', sqlc.narg(query))) AND
+    (sqlc.narg(after_id)::bigint IS NULL OR (t.amount, t.id) < (sqlc.arg(after_amount)::bigint, sqlc.narg(after_id)))
+  ORDER BY t.amount DESC, t.id DESC
+  LIMIT sqlc.arg(limit_count))
+UNION ALL
+(SELECT t.* FROM transfers t
+  WHERE
+    sqlc.arg(incoming)::bool AND
+    t.to_account_id IN (SELECT id FROM owned) AND
+    NOT (sqlc.arg(outgoing)::bool AND t.from_account_id IN (SELECT id FROM owned)) AND
+    (sqlc.narg(counterparty_id)::bigint IS NULL OR t.from_account_id = sqlc.narg(counterparty_id)) AND
+    (sqlc.narg(currency)::varchar IS NULL OR COALESCE(t.to_currency, t.currency) = sqlc.narg(currency)) AND
+    (sqlc.narg(min_amount)::bigint IS NULL OR t.amount >= sqlc.narg(min_amount)) AND
+    (sqlc.narg(max_amount)::bigint IS NULL OR t.amount <= sqlc.narg(max_amount)) AND
+    (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time)) AND
+    (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time)) AND
+    (sqlc.narg(status)::varchar IS NULL OR t.status = sqlc.narg(status)) AND
+    (sqlc.narg(query)::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', sqlc.narg(query))) AND
+    (sqlc.narg(after_id)::bigint IS NULL OR (t.amount, t.id) < (sqlc.arg(after_amount)::bigint, sqlc.narg(after_id)))
+  ORDER BY t.amount DESC, t.id DESC
+  LIMIT sqlc.arg(limit_count))
+ORDER BY amount DESC, id DESC
+LIMIT sqlc.arg(limit_count);
@@ -0,0 +1,41 @@
+-- name: CreateTransferBatch :one
+-- no row when the owner already used the idempotency key
+INSERT INTO transfer_batches (
+  from_account_id, owner, mode, leg_count, idempotency_key, request_hash
+) VALUES (
+  $1, $2, $3, $4, sqlc.narg(idempotency_key), sqlc.narg(request_hash)
+)
+ON CONFLICT (owner, idempotency_key) DO NOTHING
+RETURNING *;
+
+-- name: GetTransferBatchByIdempotencyKey :one
+SELECT * FROM transfer_batches
+WHERE owner = $1 AND idempotency_key = $2 LIMIT 1;
+
+-- name: GetTransferBatch :one
+SELECT * FROM transfer_batches
+WHERE id = $1 LIMIT 1;
+
+-- name: GetTransferBatchForUpdate :one
+SELECT * FROM transfer_batches
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: AddTransferBatchProgress :one
+-- counts move in the transaction that posts the legs
+UPDATE transfer_batches
+SET
+  posted_count = posted_count + sqlc.arg(posted),
+  failed_count = failed_count + sqlc.arg(failed)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateTransferBatch :one
+UPDATE transfer_batches
+SET
+  status = sqlc.arg(status),
+  posted_count = sqlc.arg(posted_count),
+  failed_count = sqlc.arg(failed_count),
+  finished_at = CASE WHEN sqlc.arg(status) = 'running' THEN NULL ELSE now() END
+WHERE id = sqlc.arg(id)
+RETURNING *;
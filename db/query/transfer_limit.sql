@@ -0,0 +1,48 @@
+-- name: SetTransferLimit :one
+-- unset limits keep their value, limits named in clear are removed
+INSERT INTO transfer_limits (
+  tier, account_id, currency, max_per_transfer, max_daily, max_weekly, max_monthly, max_hourly_count
+) VALUES (
+  sqlc.narg(tier), sqlc.narg(account_id), sqlc.narg(currency), sqlc.narg(max_per_transfer),
+  sqlc.narg(max_daily), sqlc.narg(max_weekly), sqlc.narg(max_monthly), sqlc.narg(max_hourly_count)
+)
+ON CONFLICT (tier, account_id, currency) DO UPDATE
+SET
+  max_per_transfer = CASE WHEN 'per_transfer' = ANY(sqlc.arg(clear)::varchar[]) THEN NULL
+    ELSE COALESCE(EXCLUDED.max_per_transfer, transfer_limits.max_per_transfer) END,
+  max_daily = CASE WHEN 'daily' = ANY(sqlc.arg(clear)::varchar[]) THEN NULL
+    ELSE COALESCE(EXCLUDED.max_daily, transfer_limits.max_daily) END,
+  max_weekly = CASE WHEN 'weekly' = ANY(sqlc.arg(clear)::varchar[]) THEN NULL
+    ELSE COALESCE(EXCLUDED.max_weekly, transfer_limits.max_weekly) END,
+  max_monthly = CASE WHEN 'monthly' = ANY(sqlc.arg(clear)::varchar[]) THEN NULL
+    ELSE COALESCE(EXCLUDED.max_monthly, transfer_limits.max_monthly) END,
+  max_hourly_count = CASE WHEN 'hourly_count' = ANY(sqlc.arg(clear)::varchar[]) THEN NULL
+    ELSE COALESCE(EXCLUDED.max_hourly_count, transfer_limits.max_hourly_count) END,
+  updated_at = now()
+RETURNING *;
+
+-- name: ListTransferLimits :many
+-- global, tier and account limits that apply to a transfer in the currency
+SELECT * FROM transfer_limits
+WHERE
+    (currency IS NULL OR currency = sqlc.arg(currency)::varchar) AND
+    ((tier IS NULL AND account_id IS NULL) OR tier = sqlc.arg(tier)::varchar OR account_id = sqlc.arg(account_id)::bigint)
+ORDER BY id;
+
+-- name: GetTransferUsage :one
+-- rolling windows ending now, reversals and transfers that never moved money don't count,
+-- a pending transfer being posted is left out with exclude_id
+SELECT
+  COALESCE(SUM(t.amount) FILTER (WHERE t.currency = sqlc.arg(currency) AND t.created_at > now() - interval '1 day'), 0)::bigint AS daily,
+  COALESCE(SUM(t.amount) FILTER (WHERE t.currency = sqlc.arg(currency) AND t.created_at > now() - interval '7 days'), 0)::bigint AS weekly,
+  COALESCE(SUM(t.amount) FILTER (WHERE t.currency = sqlc.arg(currency)), 0)::bigint AS monthly,
+  COUNT(*) FILTER (WHERE t.created_at > now() - interval '1 hour') AS hourly_count
+FROM transfers t
+JOIN accounts a ON a.id = t.from_account_id
+WHERE
+    a.owner = sqlc.arg(owner) AND
+    (sqlc.narg(account_id)::bigint IS NULL OR t.from_account_id = sqlc.narg(account_id)) AND
+    t.status IN ('pending', 'posted') AND
+    t.reversal_of IS NULL AND
+    (sqlc.narg(exclude_id)::bigint IS NULL OR t.id <> sqlc.narg(exclude_id)) AND
+    t.created_at > now() - interval '30 days';
@@ -16,6 +16,25 @@ SET
   hashed_password = COALESCE(sqlc.narg(hashed_password), hashed_password),
   password_changed_at = COALESCE(sqlc.narg(password_changed_at), password_changed_at),
   full_name = COALESCE(sqlc.narg(full_name), full_name),
-  email = COALESCE(sqlc.narg(email), email)
+  email = COALESCE(sqlc.narg(email), email),
+  -- a new address has to be verified again
+  is_email_verified = is_email_verified AND COALESCE(sqlc.narg(email) = email, TRUE)
 WHERE username = sqlc.arg(username)
 RETURNING *;
+
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE username = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: UpdateUserTier :one
+UPDATE users
+SET tier = sqlc.arg(tier)
+WHERE username = sqlc.arg(username)
+RETURNING *;
+
+-- name: VerifyUserEmail :one
+UPDATE users
+SET is_email_verified = TRUE
+WHERE username = sqlc.arg(username) AND email = sqlc.arg(email)
+RETURNING *;
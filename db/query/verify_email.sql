@@ -0,0 +1,18 @@
+-- name: CreateVerifyEmail :one
+INSERT INTO verify_emails (
+  username, email, secret_code
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetVerifyEmailForUpdate :one
+SELECT * FROM verify_emails
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: UseVerifyEmail :one
+UPDATE verify_emails
+SET is_used = TRUE
+WHERE id = $1
+RETURNING *;
@@ -11,11 +11,15 @@ import (
 )
 
 func createRandomAccount(t *testing.T) Account {
+	return createFundedAccount(t, utils.RandomMoney())
+}
+
+func createFundedAccount(t *testing.T, balance utils.Money) Account {
 	user := createRandomUser(t)
 	arg := CreateAccountParams{
 		Owner:    user.Username,
-		Balance:  utils.RandomMoney(),
-		Currency: utils.RandomCurrency(),
+		Balance:  balance.Amount,
+		Currency: balance.Currency,
 	}
 	account, err := testQueries.CreateAccount(context.Background(), arg)
 	require.NoError(t, err)
@@ -55,7 +59,7 @@ func TestUpdateAccount(t *testing.T) {
 	account := createRandomAccount(t)
 	arg := UpdateAccountParams{
 		ID:      account.ID,
-		Balance: utils.RandomMoney(),
+		Balance: utils.RandomMoney().Amount,
 	}
 
 	updatedAccount, err := testQueries.UpdateAccount(context.Background(), arg)
@@ -64,6 +68,45 @@ func TestUpdateAccount(t *testing.T) {
 	require.Equal(t, updatedAccount.Balance, arg.Balance)
 }
 
+// test update account overdraft limit
+func TestUpdateAccountOverdraftLimit(t *testing.T) {
+	account := createRandomAccount(t)
+	require.Equal(t, OverdraftNone, account.OverdraftPolicy())
+
+	arg := UpdateAccountOverdraftLimitParams{
+		ID:             account.ID,
+		OverdraftLimit: OverdraftLimit(OverdraftLimited, utils.RandomMoney().Amount+1),
+	}
+	updatedAccount, err := testQueries.UpdateAccountOverdraftLimit(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.OverdraftLimit, updatedAccount.OverdraftLimit)
+	require.Equal(t, OverdraftLimited, updatedAccount.OverdraftPolicy())
+
+	arg.OverdraftLimit = OverdraftLimit(OverdraftUnlimited, 0)
+	updatedAccount, err = testQueries.UpdateAccountOverdraftLimit(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, updatedAccount.OverdraftLimit.Valid)
+	require.Equal(t, OverdraftUnlimited, updatedAccount.OverdraftPolicy())
+}
+
+// test create system account
+func TestCreateSystemAccount(t *testing.T) {
+	arg := CreateSystemAccountParams{
+		Owner:    SystemCashOwner,
+		Currency: utils.RandomCurrency(),
+	}
+	account1, err := testQueries.CreateSystemAccount(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Owner, account1.Owner)
+	require.Equal(t, arg.Currency, account1.Currency)
+	require.Equal(t, OverdraftUnlimited, account1.OverdraftPolicy())
+
+	// same account is returned for the currency
+	account2, err := testQueries.CreateSystemAccount(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, account2.ID)
+}
+
 // test delete account
 func TestDeleteAccount(t *testing.T) {
 	account := createRandomAccount(t)
@@ -96,3 +139,35 @@ func TestListAccount(t *testing.T) {
 		require.Equal(t, lastAccount.Owner, acc.Owner)
 	}
 }
+
+// test list accounts by cursor
+func TestListAccountPage(t *testing.T) {
+	user := createRandomUser(t)
+	for _, currency := range []string{utils.USD, utils.EUR, utils.CAD} {
+		_, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    user.Username,
+			Currency: currency,
+		})
+		require.NoError(t, err)
+	}
+
+	arg := ListAccountsPageParams{
+		Owner:      user.Username,
+		LimitCount: 2,
+	}
+	page1, err := testQueries.ListAccountsPage(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page1, 2)
+
+	last := page1[len(page1)-1]
+	arg.AfterCreatedAt = last.CreatedAt
+	arg.AfterID = last.ID
+	page2, err := testQueries.ListAccountsPage(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page2, 1)
+
+	for _, acc := range page1 {
+		require.Equal(t, user.Username, acc.Owner)
+		require.NotEqual(t, page2[0].ID, acc.ID)
+	}
+}
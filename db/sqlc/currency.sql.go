@@ -0,0 +1,157 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: currency.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createCurrency = `-- name: CreateCurrency :one
+INSERT INTO currencies (
+  code, name, exponent, enabled, min_transfer_amount, max_transfer_amount
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING code, name, exponent, enabled, min_transfer_amount, max_transfer_amount, updated_at, created_at
+`
+
+type CreateCurrencyParams struct {
+	Code              string        `json:"code"`
+	Name              string        `json:"name"`
+	Exponent          int32         `json:"exponent"`
+	Enabled           bool          `json:"enabled"`
+	MinTransferAmount int64         `json:"min_transfer_amount"`
+	MaxTransferAmount sql.NullInt64 `json:"max_transfer_amount"`
+}
+
+func (q *Queries) CreateCurrency(ctx context.Context, arg CreateCurrencyParams) (Currency, error) {
+	row := q.db.QueryRowContext(ctx, createCurrency,
+		arg.Code,
+		arg.Name,
+		arg.Exponent,
+		arg.Enabled,
+		arg.MinTransferAmount,
+		arg.MaxTransferAmount,
+	)
+	var i Currency
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.Exponent,
+		&i.Enabled,
+		&i.MinTransferAmount,
+		&i.MaxTransferAmount,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getCurrency = `-- name: GetCurrency :one
+SELECT code, name, exponent, enabled, min_transfer_amount, max_transfer_amount, updated_at, created_at FROM currencies
+WHERE code = $1 LIMIT 1
+`
+
+func (q *Queries) GetCurrency(ctx context.Context, code string) (Currency, error) {
+	row := q.db.QueryRowContext(ctx, getCurrency, code)
+	var i Currency
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.Exponent,
+		&i.Enabled,
+		&i.MinTransferAmount,
+		&i.MaxTransferAmount,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listCurrencies = `-- name: ListCurrencies :many
+SELECT code, name, exponent, enabled, min_transfer_amount, max_transfer_amount, updated_at, created_at FROM currencies
+ORDER BY code
+`
+
+func (q *Queries) ListCurrencies(ctx context.Context) ([]Currency, error) {
+	rows, err := q.db.QueryContext(ctx, listCurrencies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Currency{}
+	for rows.Next() {
+		var i Currency
+		if err := rows.Scan(
+			&i.Code,
+			&i.Name,
+			&i.Exponent,
+			&i.Enabled,
+			&i.MinTransferAmount,
+			&i.MaxTransferAmount,
+			&i.UpdatedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateCurrency = `-- name: UpdateCurrency :one
+UPDATE currencies
+SET
+  name = COALESCE($1, name),
+  enabled = COALESCE($2, enabled),
+  min_transfer_amount = COALESCE($3, min_transfer_amount),
+  max_transfer_amount = CASE
+    WHEN $4::boolean THEN $5
+    ELSE max_transfer_amount
+  END,
+  updated_at = now()
+WHERE code = $6
+RETURNING code, name, exponent, enabled, min_transfer_amount, max_transfer_amount, updated_at, created_at
+`
+
+type UpdateCurrencyParams struct {
+	Name                 sql.NullString `json:"name"`
+	Enabled              sql.NullBool   `json:"enabled"`
+	MinTransferAmount    sql.NullInt64  `json:"min_transfer_amount"`
+	SetMaxTransferAmount bool           `json:"set_max_transfer_amount"`
+	MaxTransferAmount    sql.NullInt64  `json:"max_transfer_amount"`
+	Code                 string         `json:"code"`
+}
+
+func (q *Queries) UpdateCurrency(ctx context.Context, arg UpdateCurrencyParams) (Currency, error) {
+	row := q.db.QueryRowContext(ctx, updateCurrency,
+		arg.Name,
+		arg.Enabled,
+		arg.MinTransferAmount,
+		arg.SetMaxTransferAmount,
+		arg.MaxTransferAmount,
+		arg.Code,
+	)
+	var i Currency
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.Exponent,
+		&i.Enabled,
+		&i.MinTransferAmount,
+		&i.MaxTransferAmount,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
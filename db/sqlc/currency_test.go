@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomCurrency(t *testing.T) Currency {
+	arg := CreateCurrencyParams{
+		Code:              strings.ToUpper(utils.RandomString(3)),
+		Name:              utils.RandomOwner(),
+		Exponent:          int32(utils.RandomInt(0, 4)),
+		Enabled:           true,
+		MinTransferAmount: 1,
+	}
+
+	// random code can clash with seeded or earlier currency
+	if _, err := testQueries.GetCurrency(context.Background(), arg.Code); err == nil {
+		return createRandomCurrency(t)
+	}
+
+	currency, err := testQueries.CreateCurrency(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, currency)
+
+	require.Equal(t, arg.Code, currency.Code)
+	require.Equal(t, arg.Name, currency.Name)
+	require.Equal(t, arg.Exponent, currency.Exponent)
+	require.True(t, currency.Enabled)
+	require.False(t, currency.MaxTransferAmount.Valid)
+	require.NotZero(t, currency.CreatedAt)
+
+	return currency
+}
+
+// test create currency
+func TestCreateCurrency(t *testing.T) {
+	createRandomCurrency(t)
+}
+
+// test seeded currencies
+func TestListCurrencies(t *testing.T) {
+	currencies, err := testQueries.ListCurrencies(context.Background())
+	require.NoError(t, err)
+
+	codes := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		codes = append(codes, currency.Code)
+	}
+	require.Subset(t, codes, []string{utils.USD, utils.EUR, utils.CAD})
+}
+
+// test update currency
+func TestUpdateCurrency(t *testing.T) {
+	currency := createRandomCurrency(t)
+
+	updatedCurrency, err := testQueries.UpdateCurrency(context.Background(), UpdateCurrencyParams{
+		Code:                 currency.Code,
+		Enabled:              sql.NullBool{Bool: false, Valid: true},
+		SetMaxTransferAmount: true,
+		MaxTransferAmount:    sql.NullInt64{Int64: 1000, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, currency.Name, updatedCurrency.Name)
+	require.False(t, updatedCurrency.Enabled)
+	require.Equal(t, int64(1000), updatedCurrency.MaxTransferAmount.Int64)
+
+	// max limit is kept unless explicitly set
+	updatedCurrency, err = testQueries.UpdateCurrency(context.Background(), UpdateCurrencyParams{
+		Code: currency.Code,
+		Name: sql.NullString{String: "renamed", Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "renamed", updatedCurrency.Name)
+	require.True(t, updatedCurrency.MaxTransferAmount.Valid)
+
+	updatedCurrency, err = testQueries.UpdateCurrency(context.Background(), UpdateCurrencyParams{
+		Code:                 currency.Code,
+		SetMaxTransferAmount: true,
+	})
+	require.NoError(t, err)
+	require.False(t, updatedCurrency.MaxTransferAmount.Valid)
+}
@@ -7,36 +7,76 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
-  account_id, amount
+  account_id, amount, transfer_id, balance_after, created_at
 ) VALUES (
-  $1, $2
+  $1, $2, $3, $4, clock_timestamp()
 )
-RETURNING id, account_id, amount, created_at
+RETURNING id, account_id, amount, created_at, transfer_id, balance_after
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"account_id"`
-	Amount    int64 `json:"amount"`
+	AccountID    int64         `json:"account_id"`
+	Amount       int64         `json:"amount"`
+	TransferID   sql.NullInt64 `json:"transfer_id"`
+	BalanceAfter int64         `json:"balance_after"`
 }
 
+// clock time rather than tx start, so entries of a locked account sort in posting order
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createEntry,
+		arg.AccountID,
+		arg.Amount,
+		arg.TransferID,
+		arg.BalanceAfter,
+	)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+		&i.BalanceAfter,
 	)
 	return i, err
 }
 
+const getBalanceBefore = `-- name: GetBalanceBefore :one
+
+SELECT COALESCE(
+  (SELECT e.balance_after FROM entries e
+   WHERE e.account_id = $1 AND e.created_at < $2::timestamptz
+   ORDER BY e.created_at DESC, e.id DESC LIMIT 1),
+  (SELECT e.balance_after - e.amount FROM entries e
+   WHERE e.account_id = $1
+   ORDER BY e.created_at, e.id LIMIT 1),
+  (SELECT a.balance FROM accounts a WHERE a.id = $1)
+)::bigint AS balance
+`
+
+type GetBalanceBeforeParams struct {
+	AccountID int64     `json:"account_id"`
+	Before    time.Time `json:"before"`
+}
+
+// deprecated, only for page_id requests
+// last entry before the time, else the opening balance of the first entry, else no entries at all,
+// ordered like the (account_id, created_at, id) index so each lookup reads a single row
+func (q *Queries) GetBalanceBefore(ctx context.Context, arg GetBalanceBeforeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getBalanceBefore, arg.AccountID, arg.Before)
+	var balance int64
+	err := row.Scan(&balance)
+	return balance, err
+}
+
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id, balance_after FROM entries
 WHERE id = $1 LIMIT 1
 `
 
@@ -48,12 +88,14 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+		&i.BalanceAfter,
 	)
 	return i, err
 }
 
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id, balance_after FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
@@ -80,6 +122,134 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
+			&i.BalanceAfter,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntriesPage = `-- name: ListEntriesPage :many
+SELECT id, account_id, amount, created_at, transfer_id, balance_after FROM entries
+WHERE account_id = $1
+  AND (created_at, id) > ($2::timestamptz, $3::bigint)
+ORDER BY created_at, id
+LIMIT $4
+`
+
+type ListEntriesPageParams struct {
+	AccountID      int64     `json:"account_id"`
+	AfterCreatedAt time.Time `json:"after_created_at"`
+	AfterID        int64     `json:"after_id"`
+	LimitCount     int32     `json:"limit_count"`
+}
+
+func (q *Queries) ListEntriesPage(ctx context.Context, arg ListEntriesPageParams) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listEntriesPage,
+		arg.AccountID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+			&i.BalanceAfter,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStatementEntries = `-- name: ListStatementEntries :many
+SELECT
+  e.id, e.account_id, e.amount, e.balance_after, e.transfer_id, e.created_at,
+  COALESCE(CASE
+    WHEN t.from_account_id = e.account_id THEN t.to_account_id
+    ELSE t.from_account_id
+  END, 0)::bigint AS counterparty_account_id
+FROM entries e
+LEFT JOIN transfers t ON t.id = e.transfer_id
+WHERE e.account_id = $1
+  AND e.created_at >= $2::timestamptz
+  AND e.created_at < $3::timestamptz
+  AND e.id > $4::bigint
+ORDER BY e.id
+LIMIT $6
+OFFSET $5
+`
+
+type ListStatementEntriesParams struct {
+	AccountID   int64     `json:"account_id"`
+	FromTime    time.Time `json:"from_time"`
+	ToTime      time.Time `json:"to_time"`
+	AfterID     int64     `json:"after_id"`
+	OffsetCount int32     `json:"offset_count"`
+	LimitCount  int32     `json:"limit_count"`
+}
+
+type ListStatementEntriesRow struct {
+	ID                    int64         `json:"id"`
+	AccountID             int64         `json:"account_id"`
+	Amount                int64         `json:"amount"`
+	BalanceAfter          int64         `json:"balance_after"`
+	TransferID            sql.NullInt64 `json:"transfer_id"`
+	CreatedAt             time.Time     `json:"created_at"`
+	CounterpartyAccountID int64         `json:"counterparty_account_id"`
+}
+
+// ids follow posting order within an account, tx start times do not
+func (q *Queries) ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStatementEntries,
+		arg.AccountID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.AfterID,
+		arg.OffsetCount,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListStatementEntriesRow{}
+	for rows.Next() {
+		var i ListStatementEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.BalanceAfter,
+			&i.TransferID,
+			&i.CreatedAt,
+			&i.CounterpartyAccountID,
 		); err != nil {
 			return nil, err
 		}
@@ -10,9 +10,11 @@ import (
 )
 
 func createRandomEntry(t *testing.T, account Account) Entry {
+	amount := utils.RandomMoney().Amount
 	arg := CreateEntryParams{
-		AccountID: account.ID,
-		Amount:    utils.RandomMoney(),
+		AccountID:    account.ID,
+		Amount:       amount,
+		BalanceAfter: account.Balance + amount,
 	}
 	entry, err := testQueries.CreateEntry(context.Background(), arg)
 	require.NoError(t, err)
@@ -20,6 +22,7 @@ func createRandomEntry(t *testing.T, account Account) Entry {
 
 	require.Equal(t, arg.AccountID, entry.AccountID)
 	require.Equal(t, arg.Amount, entry.Amount)
+	require.Equal(t, arg.BalanceAfter, entry.BalanceAfter)
 
 	require.NotZero(t, entry.ID)
 	require.NotZero(t, entry.CreatedAt)
@@ -69,3 +72,146 @@ func TestListEntries(t *testing.T) {
 		require.True(t, entry.AccountID == account.ID)
 	}
 }
+
+// test list entries by cursor
+func TestListEntriesPage(t *testing.T) {
+	account := createRandomAccount(t)
+
+	for i := 0; i < 5; i++ {
+		createRandomEntry(t, account)
+	}
+	arg := ListEntriesPageParams{
+		AccountID:  account.ID,
+		LimitCount: 3,
+	}
+
+	var got []Entry
+	for {
+		entries, err := testQueries.ListEntriesPage(context.Background(), arg)
+		require.NoError(t, err)
+		got = append(got, entries...)
+		if len(entries) < int(arg.LimitCount) {
+			break
+		}
+		arg.AfterCreatedAt = entries[len(entries)-1].CreatedAt
+		arg.AfterID = entries[len(entries)-1].ID
+	}
+	require.Len(t, got, 5)
+
+	for i := 1; i < len(got); i++ {
+		require.Equal(t, account.ID, got[i].AccountID)
+		require.Less(t, got[i-1].ID, got[i].ID)
+	}
+}
+
+// test statement balances come from running balances
+func TestStatement(t *testing.T) {
+	store := NewStore(testDB)
+	amount := utils.NewMoney(10, utils.RandomCurrency())
+
+	account1 := createFundedAccount(t, utils.NewMoney(100, amount.Currency))
+	account2 := createFundedAccount(t, utils.NewMoney(0, amount.Currency))
+
+	// no entries yet, balance is what the account was opened with
+	opening, err := testQueries.GetBalanceBefore(context.Background(), GetBalanceBeforeParams{
+		AccountID: account1.ID,
+		Before:    time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(100), opening)
+
+	fromTime := time.Now()
+	var transfers []Transfer
+	for i := 0; i < 3; i++ {
+		result, err := store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountId: account1.ID,
+			ToAccountId:   account2.ID,
+			Amount:        amount,
+		})
+		require.NoError(t, err)
+		transfers = append(transfers, result.Transfer)
+	}
+	toTime := time.Now()
+
+	entries, err := testQueries.ListStatementEntries(context.Background(), ListStatementEntriesParams{
+		AccountID:  account1.ID,
+		FromTime:   fromTime,
+		ToTime:     toTime,
+		LimitCount: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	balance := int64(100)
+	for i, entry := range entries {
+		balance += entry.Amount
+		require.Equal(t, -amount.Amount, entry.Amount)
+		require.Equal(t, balance, entry.BalanceAfter)
+		require.Equal(t, transfers[i].ID, entry.TransferID.Int64)
+		require.Equal(t, account2.ID, entry.CounterpartyAccountID)
+	}
+
+	// before any entry the opening balance is derived from the first one
+	opening, err = testQueries.GetBalanceBefore(context.Background(), GetBalanceBeforeParams{
+		AccountID: account1.ID,
+		Before:    fromTime,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(100), opening)
+
+	closing, err := testQueries.GetBalanceBefore(context.Background(), GetBalanceBeforeParams{
+		AccountID: account1.ID,
+		Before:    toTime,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(100)-3*amount.Amount, closing)
+}
+
+// test concurrent transfers still give a continuous running balance
+func TestStatementConcurrentTransfers(t *testing.T) {
+	store := NewStore(testDB)
+	amount := utils.NewMoney(10, utils.RandomCurrency())
+
+	account1 := createFundedAccount(t, utils.NewMoney(100, amount.Currency))
+	account2 := createFundedAccount(t, utils.NewMoney(0, amount.Currency))
+
+	n := 5
+	fromTime := time.Now()
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+			})
+			errs <- err
+		}()
+	}
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+	toTime := time.Now()
+
+	entries, err := testQueries.ListStatementEntries(context.Background(), ListStatementEntriesParams{
+		AccountID:  account1.ID,
+		FromTime:   fromTime,
+		ToTime:     toTime,
+		LimitCount: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, n)
+
+	balance := int64(100)
+	for _, entry := range entries {
+		balance += entry.Amount
+		require.Equal(t, balance, entry.BalanceAfter)
+	}
+
+	closing, err := testQueries.GetBalanceBefore(context.Background(), GetBalanceBeforeParams{
+		AccountID: account1.ID,
+		Before:    toTime,
+	})
+	require.NoError(t, err)
+	require.Equal(t, balance, closing)
+}
@@ -0,0 +1,303 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: fx.sql
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createFXQuote = `-- name: CreateFXQuote :one
+INSERT INTO fx_quotes (
+  id, username, from_currency, to_currency, from_amount, to_amount, rate, spread_bps, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING id, username, from_currency, to_currency, from_amount, to_amount, rate, spread_bps, expires_at, used_at, created_at
+`
+
+type CreateFXQuoteParams struct {
+	ID           uuid.UUID `json:"id"`
+	Username     string    `json:"username"`
+	FromCurrency string    `json:"from_currency"`
+	ToCurrency   string    `json:"to_currency"`
+	FromAmount   int64     `json:"from_amount"`
+	ToAmount     int64     `json:"to_amount"`
+	Rate         string    `json:"rate"`
+	SpreadBps    int32     `json:"spread_bps"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateFXQuote(ctx context.Context, arg CreateFXQuoteParams) (FxQuote, error) {
+	row := q.db.QueryRowContext(ctx, createFXQuote,
+		arg.ID,
+		arg.Username,
+		arg.FromCurrency,
+		arg.ToCurrency,
+		arg.FromAmount,
+		arg.ToAmount,
+		arg.Rate,
+		arg.SpreadBps,
+		arg.ExpiresAt,
+	)
+	var i FxQuote
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.FromCurrency,
+		&i.ToCurrency,
+		&i.FromAmount,
+		&i.ToAmount,
+		&i.Rate,
+		&i.SpreadBps,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createFXRate = `-- name: CreateFXRate :one
+INSERT INTO fx_rates (
+  from_currency, to_currency, rate, spread_bps, effective_from, source
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (from_currency, to_currency, effective_from) DO NOTHING
+RETURNING from_currency, to_currency, rate, spread_bps, created_at, id, effective_from, source
+`
+
+type CreateFXRateParams struct {
+	FromCurrency  string    `json:"from_currency"`
+	ToCurrency    string    `json:"to_currency"`
+	Rate          string    `json:"rate"`
+	SpreadBps     int32     `json:"spread_bps"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	Source        string    `json:"source"`
+}
+
+func (q *Queries) CreateFXRate(ctx context.Context, arg CreateFXRateParams) (FxRate, error) {
+	row := q.db.QueryRowContext(ctx, createFXRate,
+		arg.FromCurrency,
+		arg.ToCurrency,
+		arg.Rate,
+		arg.SpreadBps,
+		arg.EffectiveFrom,
+		arg.Source,
+	)
+	var i FxRate
+	err := row.Scan(
+		&i.FromCurrency,
+		&i.ToCurrency,
+		&i.Rate,
+		&i.SpreadBps,
+		&i.CreatedAt,
+		&i.ID,
+		&i.EffectiveFrom,
+		&i.Source,
+	)
+	return i, err
+}
+
+const getFXQuoteForUpdate = `-- name: GetFXQuoteForUpdate :one
+SELECT id, username, from_currency, to_currency, from_amount, to_amount, rate, spread_bps, expires_at, used_at, created_at FROM fx_quotes
+WHERE id = $1 LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetFXQuoteForUpdate(ctx context.Context, id uuid.UUID) (FxQuote, error) {
+	row := q.db.QueryRowContext(ctx, getFXQuoteForUpdate, id)
+	var i FxQuote
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.FromCurrency,
+		&i.ToCurrency,
+		&i.FromAmount,
+		&i.ToAmount,
+		&i.Rate,
+		&i.SpreadBps,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getFXRate = `-- name: GetFXRate :one
+SELECT from_currency, to_currency, rate, spread_bps, created_at, id, effective_from, source FROM fx_rates
+WHERE from_currency = $1 AND to_currency = $2 AND effective_from <= now()
+ORDER BY effective_from DESC
+LIMIT 1
+`
+
+type GetFXRateParams struct {
+	FromCurrency string `json:"from_currency"`
+	ToCurrency   string `json:"to_currency"`
+}
+
+func (q *Queries) GetFXRate(ctx context.Context, arg GetFXRateParams) (FxRate, error) {
+	row := q.db.QueryRowContext(ctx, getFXRate, arg.FromCurrency, arg.ToCurrency)
+	var i FxRate
+	err := row.Scan(
+		&i.FromCurrency,
+		&i.ToCurrency,
+		&i.Rate,
+		&i.SpreadBps,
+		&i.CreatedAt,
+		&i.ID,
+		&i.EffectiveFrom,
+		&i.Source,
+	)
+	return i, err
+}
+
+const getFXRateAt = `-- name: GetFXRateAt :one
+SELECT from_currency, to_currency, rate, spread_bps, created_at, id, effective_from, source FROM fx_rates
+WHERE from_currency = $1 AND to_currency = $2 AND effective_from <= $3
+ORDER BY effective_from DESC
+LIMIT 1
+`
+
+type GetFXRateAtParams struct {
+	FromCurrency string    `json:"from_currency"`
+	ToCurrency   string    `json:"to_currency"`
+	At           time.Time `json:"at"`
+}
+
+func (q *Queries) GetFXRateAt(ctx context.Context, arg GetFXRateAtParams) (FxRate, error) {
+	row := q.db.QueryRowContext(ctx, getFXRateAt, arg.FromCurrency, arg.ToCurrency, arg.At)
+	var i FxRate
+	err := row.Scan(
+		&i.FromCurrency,
+		&i.ToCurrency,
+		&i.Rate,
+		&i.SpreadBps,
+		&i.CreatedAt,
+		&i.ID,
+		&i.EffectiveFrom,
+		&i.Source,
+	)
+	return i, err
+}
+
+const listFXRateHistory = `-- name: ListFXRateHistory :many
+SELECT from_currency, to_currency, rate, spread_bps, created_at, id, effective_from, source FROM fx_rates
+WHERE from_currency = $1 AND to_currency = $2
+ORDER BY effective_from DESC
+LIMIT $3
+OFFSET $4
+`
+
+type ListFXRateHistoryParams struct {
+	FromCurrency string `json:"from_currency"`
+	ToCurrency   string `json:"to_currency"`
+	Limit        int32  `json:"limit"`
+	Offset       int32  `json:"offset"`
+}
+
+func (q *Queries) ListFXRateHistory(ctx context.Context, arg ListFXRateHistoryParams) ([]FxRate, error) {
+	rows, err := q.db.QueryContext(ctx, listFXRateHistory,
+		arg.FromCurrency,
+		arg.ToCurrency,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FxRate{}
+	for rows.Next() {
+		var i FxRate
+		if err := rows.Scan(
+			&i.FromCurrency,
+			&i.ToCurrency,
+			&i.Rate,
+			&i.SpreadBps,
+			&i.CreatedAt,
+			&i.ID,
+			&i.EffectiveFrom,
+			&i.Source,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFXRates = `-- name: ListFXRates :many
+SELECT DISTINCT ON (from_currency, to_currency) from_currency, to_currency, rate, spread_bps, created_at, id, effective_from, source FROM fx_rates
+WHERE effective_from <= now()
+ORDER BY from_currency, to_currency, effective_from DESC
+`
+
+func (q *Queries) ListFXRates(ctx context.Context) ([]FxRate, error) {
+	rows, err := q.db.QueryContext(ctx, listFXRates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FxRate{}
+	for rows.Next() {
+		var i FxRate
+		if err := rows.Scan(
+			&i.FromCurrency,
+			&i.ToCurrency,
+			&i.Rate,
+			&i.SpreadBps,
+			&i.CreatedAt,
+			&i.ID,
+			&i.EffectiveFrom,
+			&i.Source,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markFXQuoteUsed = `-- name: MarkFXQuoteUsed :one
+UPDATE fx_quotes
+SET used_at = now()
+WHERE id = $1
+RETURNING id, username, from_currency, to_currency, from_amount, to_amount, rate, spread_bps, expires_at, used_at, created_at
+`
+
+func (q *Queries) MarkFXQuoteUsed(ctx context.Context, id uuid.UUID) (FxQuote, error) {
+	row := q.db.QueryRowContext(ctx, markFXQuoteUsed, id)
+	var i FxQuote
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.FromCurrency,
+		&i.ToCurrency,
+		&i.FromAmount,
+		&i.ToAmount,
+		&i.Rate,
+		&i.SpreadBps,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
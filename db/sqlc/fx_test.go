@@ -0,0 +1,160 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomFXQuote(t *testing.T, username string, amount utils.Money, toAmount utils.Money) FxQuote {
+	arg := CreateFXQuoteParams{
+		ID:           uuid.New(),
+		Username:     username,
+		FromCurrency: amount.Currency,
+		ToCurrency:   toAmount.Currency,
+		FromAmount:   amount.Amount,
+		ToAmount:     toAmount.Amount,
+		Rate:         "0.915400000000",
+		SpreadBps:    50,
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}
+
+	quote, err := testQueries.CreateFXQuote(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ID, quote.ID)
+	require.Equal(t, arg.FromAmount, quote.FromAmount)
+	require.Equal(t, arg.ToAmount, quote.ToAmount)
+	require.Equal(t, arg.Rate, quote.Rate)
+	require.False(t, quote.UsedAt.Valid)
+	require.WithinDuration(t, arg.ExpiresAt, quote.ExpiresAt, time.Second)
+
+	return quote
+}
+
+// test rate history
+func TestFXRateHistory(t *testing.T) {
+	// random pair so runs don't see each other's rates
+	currency := createRandomCurrency(t)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	arg := CreateFXRateParams{
+		FromCurrency:  utils.USD,
+		ToCurrency:    currency.Code,
+		Rate:          "1.370000000000",
+		SpreadBps:     25,
+		EffectiveFrom: now.Add(-time.Hour),
+		Source:        "test",
+	}
+	oldRate, err := testQueries.CreateFXRate(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Rate, oldRate.Rate)
+	require.Equal(t, arg.SpreadBps, oldRate.SpreadBps)
+	require.Equal(t, arg.Source, oldRate.Source)
+
+	arg.Rate = "1.380000000000"
+	arg.EffectiveFrom = now
+	newRate, err := testQueries.CreateFXRate(context.Background(), arg)
+	require.NoError(t, err)
+
+	// scheduled rate is not in effect yet
+	arg.Rate = "1.390000000000"
+	arg.EffectiveFrom = now.Add(time.Hour)
+	_, err = testQueries.CreateFXRate(context.Background(), arg)
+	require.NoError(t, err)
+
+	current, err := testQueries.GetFXRate(context.Background(), GetFXRateParams{
+		FromCurrency: utils.USD,
+		ToCurrency:   currency.Code,
+	})
+	require.NoError(t, err)
+	require.Equal(t, newRate.ID, current.ID)
+
+	past, err := testQueries.GetFXRateAt(context.Background(), GetFXRateAtParams{
+		FromCurrency: utils.USD,
+		ToCurrency:   currency.Code,
+		At:           now.Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	require.Equal(t, oldRate.ID, past.ID)
+
+	_, err = testQueries.GetFXRateAt(context.Background(), GetFXRateAtParams{
+		FromCurrency: utils.USD,
+		ToCurrency:   currency.Code,
+		At:           now.Add(-2 * time.Hour),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	history, err := testQueries.ListFXRateHistory(context.Background(), ListFXRateHistoryParams{
+		FromCurrency: utils.USD,
+		ToCurrency:   currency.Code,
+		Limit:        5,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+
+	// history is never rewritten
+	arg.Rate = "1.400000000000"
+	_, err = testQueries.CreateFXRate(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+// test import is all or nothing and skips unchanged rates
+func TestImportFXRatesTx(t *testing.T) {
+	store := NewStore(testDB)
+	currency := createRandomCurrency(t)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	rate := CreateFXRateParams{
+		FromCurrency:  utils.USD,
+		ToCurrency:    currency.Code,
+		Rate:          "1.370000000000",
+		SpreadBps:     25,
+		EffectiveFrom: now.Add(-time.Hour),
+		Source:        "test",
+	}
+	imported, err := store.ImportFXRatesTx(context.Background(), []CreateFXRateParams{rate})
+	require.NoError(t, err)
+	require.Equal(t, 1, imported)
+
+	// same rate later adds nothing, a new one is appended
+	unchanged := rate
+	unchanged.EffectiveFrom = now
+	changed := rate
+	changed.Rate = "1.380000000000"
+	changed.EffectiveFrom = now.Add(time.Minute)
+	imported, err = store.ImportFXRatesTx(context.Background(), []CreateFXRateParams{unchanged, changed})
+	require.NoError(t, err)
+	require.Equal(t, 1, imported)
+
+	// a conflicting rate rolls back the rows before it
+	next := rate
+	next.Rate = "1.390000000000"
+	next.EffectiveFrom = now.Add(time.Hour)
+	conflict := rate
+	conflict.Rate = "1.400000000000"
+	_, err = store.ImportFXRatesTx(context.Background(), []CreateFXRateParams{next, conflict})
+	require.ErrorIs(t, err, ErrFXRateConflict)
+
+	history, err := testQueries.ListFXRateHistory(context.Background(), ListFXRateHistoryParams{
+		FromCurrency: utils.USD,
+		ToCurrency:   currency.Code,
+		Limit:        5,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+}
+
+// test fx quote is marked used
+func TestMarkFXQuoteUsed(t *testing.T) {
+	user := createRandomUser(t)
+	quote := createRandomFXQuote(t, user.Username, utils.NewMoney(1000, utils.USD), utils.NewMoney(915, utils.EUR))
+
+	usedQuote, err := testQueries.MarkFXQuoteUsed(context.Background(), quote.ID)
+	require.NoError(t, err)
+	require.True(t, usedQuote.UsedAt.Valid)
+}
@@ -0,0 +1,172 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: hold.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createHold = `-- name: CreateHold :one
+INSERT INTO holds (
+  account_id, to_account_id, amount, currency, expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING id, account_id, to_account_id, amount, currency, status, captured_amount, transfer_id, expires_at, updated_at, created_at
+`
+
+type CreateHoldParams struct {
+	AccountID   int64     `json:"account_id"`
+	ToAccountID int64     `json:"to_account_id"`
+	Amount      int64     `json:"amount"`
+	Currency    string    `json:"currency"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error) {
+	row := q.db.QueryRowContext(ctx, createHold,
+		arg.AccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Currency,
+		arg.ExpiresAt,
+	)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const expireHolds = `-- name: ExpireHolds :execrows
+UPDATE holds
+SET status = 'expired', updated_at = now()
+WHERE status = 'active' AND expires_at <= now()
+`
+
+func (q *Queries) ExpireHolds(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, expireHolds)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getHeldAmount = `-- name: GetHeldAmount :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS held
+FROM holds
+WHERE account_id = $1 AND status = 'active' AND expires_at > now()
+`
+
+func (q *Queries) GetHeldAmount(ctx context.Context, accountID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getHeldAmount, accountID)
+	var held int64
+	err := row.Scan(&held)
+	return held, err
+}
+
+const getHold = `-- name: GetHold :one
+SELECT id, account_id, to_account_id, amount, currency, status, captured_amount, transfer_id, expires_at, updated_at, created_at FROM holds
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetHold(ctx context.Context, id int64) (Hold, error) {
+	row := q.db.QueryRowContext(ctx, getHold, id)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getHoldForUpdate = `-- name: GetHoldForUpdate :one
+SELECT id, account_id, to_account_id, amount, currency, status, captured_amount, transfer_id, expires_at, updated_at, created_at FROM holds
+WHERE id = $1 LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetHoldForUpdate(ctx context.Context, id int64) (Hold, error) {
+	row := q.db.QueryRowContext(ctx, getHoldForUpdate, id)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const updateHoldStatus = `-- name: UpdateHoldStatus :one
+UPDATE holds
+SET
+  status = $1,
+  captured_amount = COALESCE($2, captured_amount),
+  transfer_id = COALESCE($3, transfer_id),
+  updated_at = now()
+WHERE id = $4
+RETURNING id, account_id, to_account_id, amount, currency, status, captured_amount, transfer_id, expires_at, updated_at, created_at
+`
+
+type UpdateHoldStatusParams struct {
+	Status         string        `json:"status"`
+	CapturedAmount sql.NullInt64 `json:"captured_amount"`
+	TransferID     sql.NullInt64 `json:"transfer_id"`
+	ID             int64         `json:"id"`
+}
+
+func (q *Queries) UpdateHoldStatus(ctx context.Context, arg UpdateHoldStatusParams) (Hold, error) {
+	row := q.db.QueryRowContext(ctx, updateHoldStatus,
+		arg.Status,
+		arg.CapturedAmount,
+		arg.TransferID,
+		arg.ID,
+	)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Currency,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomHold(t *testing.T, account Account, toAccount Account, expiresAt time.Time) Hold {
+	arg := CreateHoldParams{
+		AccountID:   account.ID,
+		ToAccountID: toAccount.ID,
+		Amount:      10,
+		Currency:    account.Currency,
+		ExpiresAt:   expiresAt,
+	}
+
+	hold, err := testQueries.CreateHold(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, hold.ID)
+	require.Equal(t, arg.AccountID, hold.AccountID)
+	require.Equal(t, arg.ToAccountID, hold.ToAccountID)
+	require.Equal(t, arg.Amount, hold.Amount)
+	require.Equal(t, HoldActive, hold.Status)
+	require.Zero(t, hold.CapturedAmount)
+	require.False(t, hold.TransferID.Valid)
+	require.WithinDuration(t, arg.ExpiresAt, hold.ExpiresAt, time.Second)
+
+	return hold
+}
+
+// test only active unexpired holds are held
+func TestGetHeldAmount(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createFundedAccount(t, account1.BalanceMoney())
+
+	hold1 := createRandomHold(t, account1, account2, time.Now().Add(time.Hour))
+	createRandomHold(t, account1, account2, time.Now().Add(-time.Second))
+	hold3 := createRandomHold(t, account1, account2, time.Now().Add(time.Hour))
+
+	_, err := testQueries.UpdateHoldStatus(context.Background(), UpdateHoldStatusParams{
+		ID:     hold3.ID,
+		Status: HoldVoided,
+	})
+	require.NoError(t, err)
+
+	held, err := testQueries.GetHeldAmount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, hold1.Amount, held)
+}
+
+// test expire holds
+func TestExpireHolds(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createFundedAccount(t, account1.BalanceMoney())
+
+	active := createRandomHold(t, account1, account2, time.Now().Add(time.Hour))
+	expired := createRandomHold(t, account1, account2, time.Now().Add(-time.Second))
+
+	rows, err := testQueries.ExpireHolds(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, rows, int64(1))
+
+	hold, err := testQueries.GetHold(context.Background(), expired.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldExpired, hold.Status)
+
+	hold, err = testQueries.GetHold(context.Background(), active.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldActive, hold.Status)
+}
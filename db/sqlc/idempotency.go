@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+)
+
+var ErrIdempotencyKeyReused = errors.New("idempotency key was used for a different request")
+
+type IdempotencyParams struct {
+	Username    string
+	Key         string
+	RequestHash string // hash of the request the key was first used with
+}
+
+// execute callback once per idempotency key, replay its stored result afterwards
+func (store *SqlStore) execIdempotentTx(ctx context.Context, arg *IdempotencyParams, result any, fn func(*Queries) error) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if arg == nil {
+			return fn(q)
+		}
+
+		// concurrent request with the same key waits here until we commit
+		_, err := q.CreateIdempotencyKey(ctx, CreateIdempotencyKeyParams{
+			Username:    arg.Username,
+			Key:         arg.Key,
+			RequestHash: arg.RequestHash,
+		})
+		if err == sql.ErrNoRows {
+			return replayIdempotencyKey(ctx, q, arg, result)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := fn(q); err != nil {
+			return err
+		}
+
+		response, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.UpdateIdempotencyKeyResponse(ctx, UpdateIdempotencyKeyResponseParams{
+			Username: arg.Username,
+			Key:      arg.Key,
+			Response: response,
+		})
+		return err
+	})
+}
+
+func replayIdempotencyKey(ctx context.Context, q *Queries, arg *IdempotencyParams, result any) error {
+	key, err := q.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{
+		Username: arg.Username,
+		Key:      arg.Key,
+	})
+	if err != nil {
+		return err
+	}
+
+	if key.RequestHash != arg.RequestHash {
+		return ErrIdempotencyKeyReused
+	}
+
+	return json.Unmarshal(key.Response, result)
+}
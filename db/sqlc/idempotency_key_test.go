@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomIdempotencyKey(t *testing.T, user User) IdempotencyKey {
+	arg := CreateIdempotencyKeyParams{
+		Username:    user.Username,
+		Key:         utils.RandomString(16),
+		RequestHash: utils.RandomString(64),
+	}
+	key, err := testQueries.CreateIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, key)
+
+	require.Equal(t, arg.Username, key.Username)
+	require.Equal(t, arg.Key, key.Key)
+	require.Equal(t, arg.RequestHash, key.RequestHash)
+	require.NotZero(t, key.CreatedAt)
+
+	return key
+}
+
+// test create idempotency key
+func TestCreateIdempotencyKey(t *testing.T) {
+	user := createRandomUser(t)
+	key := createRandomIdempotencyKey(t, user)
+
+	// same key can't be claimed twice
+	_, err := testQueries.CreateIdempotencyKey(context.Background(), CreateIdempotencyKeyParams{
+		Username:    key.Username,
+		Key:         key.Key,
+		RequestHash: utils.RandomString(64),
+	})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
+
+// test update idempotency key response
+func TestUpdateIdempotencyKeyResponse(t *testing.T) {
+	user := createRandomUser(t)
+	key1 := createRandomIdempotencyKey(t, user)
+
+	arg := UpdateIdempotencyKeyResponseParams{
+		Username: key1.Username,
+		Key:      key1.Key,
+		Response: []byte(`{"id": 1}`),
+	}
+	_, err := testQueries.UpdateIdempotencyKeyResponse(context.Background(), arg)
+	require.NoError(t, err)
+
+	key2, err := testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Username: key1.Username,
+		Key:      key1.Key,
+	})
+	require.NoError(t, err)
+	require.Equal(t, key1.RequestHash, key2.RequestHash)
+	require.JSONEq(t, string(arg.Response), string(key2.Response))
+	require.WithinDuration(t, key1.CreatedAt, key2.CreatedAt, time.Second)
+}
+
+// test delete stale idempotency keys
+func TestDeleteIdempotencyKeysBefore(t *testing.T) {
+	user := createRandomUser(t)
+	key := createRandomIdempotencyKey(t, user)
+
+	deleted, err := testQueries.DeleteIdempotencyKeysBefore(context.Background(), key.CreatedAt.Add(time.Second))
+	require.NoError(t, err)
+	require.NotZero(t, deleted)
+
+	_, err = testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Username: key.Username,
+		Key:      key.Key,
+	})
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
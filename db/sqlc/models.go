@@ -5,6 +5,8 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,6 +18,21 @@ type Account struct {
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
+	// null means unlimited overdraft
+	OverdraftLimit sql.NullInt64 `json:"overdraft_limit"`
+}
+
+type Currency struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	// ISO 4217 minor unit digits
+	Exponent          int32 `json:"exponent"`
+	Enabled           bool  `json:"enabled"`
+	MinTransferAmount int64 `json:"min_transfer_amount"`
+	// null means no upper limit
+	MaxTransferAmount sql.NullInt64 `json:"max_transfer_amount"`
+	UpdatedAt         time.Time     `json:"updated_at"`
+	CreatedAt         time.Time     `json:"created_at"`
 }
 
 type Entry struct {
@@ -24,17 +41,153 @@ type Entry struct {
 	// can be negative or positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// transfer that posted the entry, null for legacy or orphan entries
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	// account balance right after the entry was posted
+	BalanceAfter int64 `json:"balance_after"`
 }
 
-type Session struct {
+type FxQuote struct {
 	ID           uuid.UUID `json:"id"`
 	Username     string    `json:"username"`
-	RefreshToken string    `json:"refresh_token"`
-	UserAgent    string    `json:"user_agent"`
-	ClientIp     string    `json:"client_ip"`
-	IsBlocked    bool      `json:"is_blocked"`
-	ExpiresAt    time.Time `json:"expires_at"`
-	CreatedAt    time.Time `json:"created_at"`
+	FromCurrency string    `json:"from_currency"`
+	ToCurrency   string    `json:"to_currency"`
+	FromAmount   int64     `json:"from_amount"`
+	ToAmount     int64     `json:"to_amount"`
+	// applied rate with spread already taken off
+	Rate      string       `json:"rate"`
+	SpreadBps int32        `json:"spread_bps"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type FxRate struct {
+	FromCurrency string `json:"from_currency"`
+	ToCurrency   string `json:"to_currency"`
+	// mid-market units of to_currency per unit of from_currency
+	Rate string `json:"rate"`
+	// bank margin in basis points taken off the mid rate
+	SpreadBps     int32     `json:"spread_bps"`
+	CreatedAt     time.Time `json:"created_at"`
+	ID            int64     `json:"id"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	// manual or name of rate provider
+	Source string `json:"source"`
+}
+
+type Hold struct {
+	ID          int64 `json:"id"`
+	AccountID   int64 `json:"account_id"`
+	ToAccountID int64 `json:"to_account_id"`
+	// reserved in account_id, must be positive
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	// active, captured, voided or expired
+	Status         string `json:"status"`
+	CapturedAmount int64  `json:"captured_amount"`
+	// set once the hold is captured
+	TransferID sql.NullInt64 `json:"transfer_id"`
+	ExpiresAt  time.Time     `json:"expires_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type IdempotencyKey struct {
+	Username    string          `json:"username"`
+	Key         string          `json:"key"`
+	RequestHash string          `json:"request_hash"`
+	Response    json.RawMessage `json:"response"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type OutboxMessage struct {
+	ID             int64           `json:"id"`
+	TaskType       string          `json:"task_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Queue          string          `json:"queue"`
+	MaxRetry       int32           `json:"max_retry"`
+	TimeoutSeconds int32           `json:"timeout_seconds"`
+	Attempts       int32           `json:"attempts"`
+	LastError      string          `json:"last_error"`
+	// not published before, pushed back after a failed attempt
+	AvailableAt time.Time    `json:"available_at"`
+	PublishedAt sql.NullTime `json:"published_at"`
+	// set when the relay gave up publishing
+	DeadAt    sql.NullTime `json:"dead_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type ReconciliationRun struct {
+	ID int64 `json:"id"`
+	// task or cli
+	Source string `json:"source"`
+	// running, clean, warning, critical or errored
+	Status           string          `json:"status"`
+	AccountsChecked  int64           `json:"accounts_checked"`
+	TransfersChecked int64           `json:"transfers_checked"`
+	CriticalFindings int32           `json:"critical_findings"`
+	Findings         json.RawMessage `json:"findings"`
+	Error            sql.NullString  `json:"error"`
+	StartedAt        time.Time       `json:"started_at"`
+	FinishedAt       sql.NullTime    `json:"finished_at"`
+}
+
+type ScheduledTransfer struct {
+	ID            int64  `json:"id"`
+	Owner         string `json:"owner"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	// cron spec, CRON_TZ= prefix picks the timezone
+	Schedule string       `json:"schedule"`
+	StartAt  time.Time    `json:"start_at"`
+	EndAt    sql.NullTime `json:"end_at"`
+	// null once the schedule has no runs left
+	NextRunAt sql.NullTime `json:"next_run_at"`
+	// active, paused, finished or cancelled
+	Status    string    `json:"status"`
+	UpdatedAt time.Time `json:"updated_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ScheduledTransferRun struct {
+	ID                  int64     `json:"id"`
+	ScheduledTransferID int64     `json:"scheduled_transfer_id"`
+	ScheduledFor        time.Time `json:"scheduled_for"`
+	// posted or failed
+	Status        string         `json:"status"`
+	TransferID    sql.NullInt64  `json:"transfer_id"`
+	FailureReason sql.NullString `json:"failure_reason"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+type Session struct {
+	ID               uuid.UUID    `json:"id"`
+	Username         string       `json:"username"`
+	UserAgent        string       `json:"user_agent"`
+	ClientIp         string       `json:"client_ip"`
+	IsBlocked        bool         `json:"is_blocked"`
+	ExpiresAt        time.Time    `json:"expires_at"`
+	CreatedAt        time.Time    `json:"created_at"`
+	FamilyID         uuid.UUID    `json:"family_id"`
+	RefreshTokenHash string       `json:"refresh_token_hash"`
+	RotatedAt        sql.NullTime `json:"rotated_at"`
+}
+
+type Statement struct {
+	ID          int64     `json:"id"`
+	AccountID   int64     `json:"account_id"`
+	PeriodStart time.Time `json:"period_start"`
+	// exclusive
+	PeriodEnd      time.Time `json:"period_end"`
+	OpeningBalance int64     `json:"opening_balance"`
+	ClosingBalance int64     `json:"closing_balance"`
+	EntryCount     int32     `json:"entry_count"`
+	// null until sent to the owner
+	EmailedAt sql.NullTime `json:"emailed_at"`
+	CreatedAt time.Time    `json:"created_at"`
 }
 
 type Transfer struct {
@@ -44,6 +197,67 @@ type Transfer struct {
 	// must be positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	Currency  string    `json:"currency"`
+	// set for cross-currency transfers only
+	ToAmount    sql.NullInt64  `json:"to_amount"`
+	ToCurrency  sql.NullString `json:"to_currency"`
+	FxRate      sql.NullString `json:"fx_rate"`
+	FxSpreadBps sql.NullInt32  `json:"fx_spread_bps"`
+	FxQuoteID   uuid.NullUUID  `json:"fx_quote_id"`
+	// original transfer this one compensates
+	ReversalOf sql.NullInt64 `json:"reversal_of"`
+	// sum of reversals posted against this transfer
+	ReversedAmount int64 `json:"reversed_amount"`
+	// pending, posted, failed, reversed or cancelled
+	Status string `json:"status"`
+	// set for failed transfers only
+	FailureReason sql.NullString `json:"failure_reason"`
+	PostedAt      sql.NullTime   `json:"posted_at"`
+	FailedAt      sql.NullTime   `json:"failed_at"`
+	CancelledAt   sql.NullTime   `json:"cancelled_at"`
+	ReversedAt    sql.NullTime   `json:"reversed_at"`
+	// free text from the sender, searchable
+	Memo string `json:"memo"`
+	// set for legs of a batch transfer
+	BatchID sql.NullInt64 `json:"batch_id"`
+}
+
+type TransferBatch struct {
+	ID            int64 `json:"id"`
+	FromAccountID int64 `json:"from_account_id"`
+	// atomic posts all legs or none, chunked posts what it can
+	Mode string `json:"mode"`
+	// running, posted, partial or failed
+	Status      string       `json:"status"`
+	LegCount    int32        `json:"leg_count"`
+	PostedCount int32        `json:"posted_count"`
+	FailedCount int32        `json:"failed_count"`
+	FinishedAt  sql.NullTime `json:"finished_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+	Owner       string       `json:"owner"`
+	// retried requests with the same key get this batch back
+	IdempotencyKey sql.NullString `json:"idempotency_key"`
+	RequestHash    sql.NullString `json:"request_hash"`
+}
+
+type TransferLimit struct {
+	ID int64 `json:"id"`
+	// set for tier limits, global when tier and account_id are null
+	Tier sql.NullString `json:"tier"`
+	// set for account limits
+	AccountID sql.NullInt64 `json:"account_id"`
+	// amount limits need a currency, count limits have none
+	Currency       sql.NullString `json:"currency"`
+	MaxPerTransfer sql.NullInt64  `json:"max_per_transfer"`
+	// rolling 24 hours
+	MaxDaily sql.NullInt64 `json:"max_daily"`
+	// rolling 7 days
+	MaxWeekly sql.NullInt64 `json:"max_weekly"`
+	// rolling 30 days
+	MaxMonthly     sql.NullInt64 `json:"max_monthly"`
+	MaxHourlyCount sql.NullInt64 `json:"max_hourly_count"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	CreatedAt      time.Time     `json:"created_at"`
 }
 
 type User struct {
@@ -53,4 +267,17 @@ type User struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	Role              string    `json:"role"`
+	Tier              string    `json:"tier"`
+	IsEmailVerified   bool      `json:"is_email_verified"`
+}
+
+type VerifyEmail struct {
+	ID         int64     `json:"id"`
+	Username   string    `json:"username"`
+	Email      string    `json:"email"`
+	SecretCode string    `json:"secret_code"`
+	IsUsed     bool      `json:"is_used"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiredAt  time.Time `json:"expired_at"`
 }
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// task the outbox relay publishes once the transaction has committed
+type OutboxTask struct {
+	Type     string
+	Payload  any // marshalled to json
+	Queue    string
+	MaxRetry int
+	Timeout  time.Duration
+}
+
+// write tasks in the caller's transaction, so they exist only if it commits
+func enqueueOutbox(ctx context.Context, q *Queries, tasks ...OutboxTask) error {
+	for _, task := range tasks {
+		payload, err := json.Marshal(task.Payload)
+		if err != nil {
+			return fmt.Errorf("cannot marshal %s payload: %w", task.Type, err)
+		}
+
+		_, err = q.CreateOutboxMessage(ctx, CreateOutboxMessageParams{
+			TaskType:       task.Type,
+			Payload:        payload,
+			Queue:          task.Queue,
+			MaxRetry:       int32(task.MaxRetry),
+			TimeoutSeconds: int32(task.Timeout / time.Second),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queries the relay runs on claimed messages, inside the claiming transaction
+type OutboxQuerier interface {
+	MarkOutboxMessagePublished(ctx context.Context, id int64) error
+	MarkOutboxMessageFailed(ctx context.Context, arg MarkOutboxMessageFailedParams) (OutboxMessage, error)
+}
+
+type ClaimOutboxMessagesTxParams struct {
+	Limit int32 `json:"limit"`
+	// called with the pending messages in id order, its marks commit with the claim
+	Publish func(q OutboxQuerier, messages []OutboxMessage) error `json:"-"`
+}
+
+// lock pending outbox messages for one relay, so several app instances never publish the same row at once
+func (store *SqlStore) ClaimOutboxMessagesTx(ctx context.Context, arg ClaimOutboxMessagesTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		messages, err := q.ListPendingOutboxMessages(ctx, arg.Limit)
+		if err != nil {
+			return err
+		}
+		return arg.Publish(q, messages)
+	})
+}
@@ -0,0 +1,194 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: outbox.sql
+
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const createOutboxMessage = `-- name: CreateOutboxMessage :one
+INSERT INTO outbox_messages (
+  task_type, payload, queue, max_retry, timeout_seconds
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+RETURNING id, task_type, payload, queue, max_retry, timeout_seconds, attempts, last_error, available_at, published_at, dead_at, created_at
+`
+
+type CreateOutboxMessageParams struct {
+	TaskType       string          `json:"task_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Queue          string          `json:"queue"`
+	MaxRetry       int32           `json:"max_retry"`
+	TimeoutSeconds int32           `json:"timeout_seconds"`
+}
+
+func (q *Queries) CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) (OutboxMessage, error) {
+	row := q.db.QueryRowContext(ctx, createOutboxMessage,
+		arg.TaskType,
+		arg.Payload,
+		arg.Queue,
+		arg.MaxRetry,
+		arg.TimeoutSeconds,
+	)
+	var i OutboxMessage
+	err := row.Scan(
+		&i.ID,
+		&i.TaskType,
+		&i.Payload,
+		&i.Queue,
+		&i.MaxRetry,
+		&i.TimeoutSeconds,
+		&i.Attempts,
+		&i.LastError,
+		&i.AvailableAt,
+		&i.PublishedAt,
+		&i.DeadAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deletePublishedOutboxMessages = `-- name: DeletePublishedOutboxMessages :execrows
+DELETE FROM outbox_messages
+WHERE published_at < $1::timestamptz
+`
+
+func (q *Queries) DeletePublishedOutboxMessages(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePublishedOutboxMessages, publishedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getOutboxMessage = `-- name: GetOutboxMessage :one
+SELECT id, task_type, payload, queue, max_retry, timeout_seconds, attempts, last_error, available_at, published_at, dead_at, created_at FROM outbox_messages
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetOutboxMessage(ctx context.Context, id int64) (OutboxMessage, error) {
+	row := q.db.QueryRowContext(ctx, getOutboxMessage, id)
+	var i OutboxMessage
+	err := row.Scan(
+		&i.ID,
+		&i.TaskType,
+		&i.Payload,
+		&i.Queue,
+		&i.MaxRetry,
+		&i.TimeoutSeconds,
+		&i.Attempts,
+		&i.LastError,
+		&i.AvailableAt,
+		&i.PublishedAt,
+		&i.DeadAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listPendingOutboxMessages = `-- name: ListPendingOutboxMessages :many
+SELECT id, task_type, payload, queue, max_retry, timeout_seconds, attempts, last_error, available_at, published_at, dead_at, created_at FROM outbox_messages
+WHERE published_at IS NULL AND dead_at IS NULL
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED
+`
+
+// rows stay claimed until the transaction ends, other relays skip them
+func (q *Queries) ListPendingOutboxMessages(ctx context.Context, limit int32) ([]OutboxMessage, error) {
+	rows, err := q.db.QueryContext(ctx, listPendingOutboxMessages, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OutboxMessage{}
+	for rows.Next() {
+		var i OutboxMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskType,
+			&i.Payload,
+			&i.Queue,
+			&i.MaxRetry,
+			&i.TimeoutSeconds,
+			&i.Attempts,
+			&i.LastError,
+			&i.AvailableAt,
+			&i.PublishedAt,
+			&i.DeadAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOutboxMessageFailed = `-- name: MarkOutboxMessageFailed :one
+UPDATE outbox_messages
+SET
+  attempts = attempts + 1,
+  last_error = $1,
+  available_at = $2,
+  dead_at = CASE WHEN $3::bool THEN now() END
+WHERE id = $4
+RETURNING id, task_type, payload, queue, max_retry, timeout_seconds, attempts, last_error, available_at, published_at, dead_at, created_at
+`
+
+type MarkOutboxMessageFailedParams struct {
+	LastError   string    `json:"last_error"`
+	AvailableAt time.Time `json:"available_at"`
+	Dead        bool      `json:"dead"`
+	ID          int64     `json:"id"`
+}
+
+func (q *Queries) MarkOutboxMessageFailed(ctx context.Context, arg MarkOutboxMessageFailedParams) (OutboxMessage, error) {
+	row := q.db.QueryRowContext(ctx, markOutboxMessageFailed,
+		arg.LastError,
+		arg.AvailableAt,
+		arg.Dead,
+		arg.ID,
+	)
+	var i OutboxMessage
+	err := row.Scan(
+		&i.ID,
+		&i.TaskType,
+		&i.Payload,
+		&i.Queue,
+		&i.MaxRetry,
+		&i.TimeoutSeconds,
+		&i.Attempts,
+		&i.LastError,
+		&i.AvailableAt,
+		&i.PublishedAt,
+		&i.DeadAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const markOutboxMessagePublished = `-- name: MarkOutboxMessagePublished :exec
+UPDATE outbox_messages
+SET
+  attempts = attempts + 1,
+  published_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) MarkOutboxMessagePublished(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markOutboxMessagePublished, id)
+	return err
+}
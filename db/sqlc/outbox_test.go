@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomOutboxMessage(t *testing.T) OutboxMessage {
+	arg := CreateOutboxMessageParams{
+		TaskType:       "task:" + utils.RandomString(6),
+		Payload:        json.RawMessage(`{"username":"` + utils.RandomOwner() + `"}`),
+		Queue:          "default",
+		MaxRetry:       10,
+		TimeoutSeconds: 5,
+	}
+	message, err := testQueries.CreateOutboxMessage(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.Equal(t, arg.TaskType, message.TaskType)
+	require.JSONEq(t, string(arg.Payload), string(message.Payload))
+	require.Equal(t, arg.Queue, message.Queue)
+	require.Zero(t, message.Attempts)
+	require.False(t, message.PublishedAt.Valid)
+	require.False(t, message.DeadAt.Valid)
+	return message
+}
+
+// test publish and fail outbox messages
+func TestOutboxMessageLifecycle(t *testing.T) {
+	message := createRandomOutboxMessage(t)
+
+	retryAt := time.Now().Add(time.Minute)
+	failed, err := testQueries.MarkOutboxMessageFailed(context.Background(), MarkOutboxMessageFailedParams{
+		ID:          message.ID,
+		LastError:   "redis down",
+		AvailableAt: retryAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), failed.Attempts)
+	require.Equal(t, "redis down", failed.LastError)
+	require.WithinDuration(t, retryAt, failed.AvailableAt, time.Second)
+	require.False(t, failed.DeadAt.Valid)
+
+	err = testQueries.MarkOutboxMessagePublished(context.Background(), message.ID)
+	require.NoError(t, err)
+
+	published, err := testQueries.GetOutboxMessage(context.Background(), message.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), published.Attempts)
+	require.True(t, published.PublishedAt.Valid)
+
+	dead := createRandomOutboxMessage(t)
+	dead, err = testQueries.MarkOutboxMessageFailed(context.Background(), MarkOutboxMessageFailedParams{
+		ID:          dead.ID,
+		LastError:   "redis down",
+		AvailableAt: retryAt,
+		Dead:        true,
+	})
+	require.NoError(t, err)
+	require.True(t, dead.DeadAt.Valid)
+
+	pending, err := testQueries.ListPendingOutboxMessages(context.Background(), 1000)
+	require.NoError(t, err)
+	for _, p := range pending {
+		require.NotEqual(t, message.ID, p.ID)
+		require.NotEqual(t, dead.ID, p.ID)
+	}
+
+	deleted, err := testQueries.DeletePublishedOutboxMessages(context.Background(), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, deleted, int64(1))
+
+	_, err = testQueries.GetOutboxMessage(context.Background(), message.ID)
+	require.Error(t, err)
+}
+
+// test a second relay skips the messages another one has claimed
+func TestClaimOutboxMessagesTx(t *testing.T) {
+	store := NewStore(testDB)
+	message := createRandomOutboxMessage(t)
+
+	err := store.ClaimOutboxMessagesTx(context.Background(), ClaimOutboxMessagesTxParams{
+		Limit: 1000,
+		Publish: func(q OutboxQuerier, messages []OutboxMessage) error {
+			claimed := make(map[int64]bool, len(messages))
+			for _, m := range messages {
+				claimed[m.ID] = true
+			}
+			require.True(t, claimed[message.ID])
+
+			return store.ClaimOutboxMessagesTx(context.Background(), ClaimOutboxMessagesTxParams{
+				Limit: 1000,
+				Publish: func(q OutboxQuerier, messages []OutboxMessage) error {
+					for _, m := range messages {
+						require.False(t, claimed[m.ID])
+					}
+					return nil
+				},
+			})
+		},
+	})
+	require.NoError(t, err)
+}
+
+// test outbox rows are written only if the user commits
+func TestCreateUserTxOutbox(t *testing.T) {
+	store := NewStore(testDB)
+	last := createRandomOutboxMessage(t)
+
+	username := utils.RandomOwner()
+	arg := CreateUserTxParams{
+		CreateUserParams: CreateUserParams{
+			Username:       username,
+			HashedPassword: utils.RandomString(32),
+			FullName:       utils.RandomOwner(),
+			Email:          utils.RandomEmail(),
+		},
+		Outbox: []OutboxTask{{
+			Type:     "task:send_email",
+			Payload:  map[string]string{"username": username},
+			Queue:    "critical",
+			MaxRetry: 10,
+			Timeout:  10 * time.Second,
+		}},
+	}
+
+	result, err := store.CreateUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, result.User.Username)
+
+	message, err := testQueries.GetOutboxMessage(context.Background(), last.ID+1)
+	require.NoError(t, err)
+	require.Equal(t, "task:send_email", message.TaskType)
+	require.Equal(t, "critical", message.Queue)
+	require.Equal(t, int32(10), message.TimeoutSeconds)
+	require.JSONEq(t, `{"username":"`+arg.Username+`"}`, string(message.Payload))
+
+	// same username fails, so its task is rolled back too
+	_, err = store.CreateUserTx(context.Background(), arg)
+	require.Error(t, err)
+
+	next := createRandomOutboxMessage(t)
+	require.Equal(t, message.ID+1, next.ID)
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/dxtym/bankrupt/utils"
+)
+
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+type OverdraftPolicy string
+
+const (
+	OverdraftNone      OverdraftPolicy = "none"      // balance can't go below zero
+	OverdraftLimited   OverdraftPolicy = "limited"   // balance can go down to -overdraft_limit
+	OverdraftUnlimited OverdraftPolicy = "unlimited" // system accounts only
+)
+
+// build overdraft_limit column value for the policy
+func OverdraftLimit(policy OverdraftPolicy, limit int64) sql.NullInt64 {
+	switch policy {
+	case OverdraftUnlimited:
+		return sql.NullInt64{}
+	case OverdraftLimited:
+		return sql.NullInt64{Int64: limit, Valid: true}
+	}
+	return sql.NullInt64{Int64: 0, Valid: true}
+}
+
+func (account Account) OverdraftPolicy() OverdraftPolicy {
+	if !account.OverdraftLimit.Valid {
+		return OverdraftUnlimited
+	}
+	if account.OverdraftLimit.Int64 > 0 {
+		return OverdraftLimited
+	}
+	return OverdraftNone
+}
+
+// check balance stays within overdraft limit after debit
+func (account Account) CanDebit(amount int64) bool {
+	if !account.OverdraftLimit.Valid {
+		return true
+	}
+	return account.Balance-amount >= -account.OverdraftLimit.Int64
+}
+
+// balance in minor units of the account currency
+func (account Account) BalanceMoney() utils.Money {
+	return utils.NewMoney(account.Balance, account.Currency)
+}
+
+// balance not reserved by active holds
+func (account Account) AvailableMoney(held int64) utils.Money {
+	return utils.NewMoney(account.Balance-held, account.Currency)
+}
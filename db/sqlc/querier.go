@@ -6,29 +6,136 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	// counts move in the transaction that posts the legs
+	AddTransferBatchProgress(ctx context.Context, arg AddTransferBatchProgressParams) (TransferBatch, error)
+	AddTransferReversedAmount(ctx context.Context, arg AddTransferReversedAmountParams) (Transfer, error)
+	BlockSession(ctx context.Context, id uuid.UUID) (Session, error)
+	BlockSessionFamily(ctx context.Context, familyID uuid.UUID) (int64, error)
+	BlockUserSessions(ctx context.Context, username string) (int64, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateCurrency(ctx context.Context, arg CreateCurrencyParams) (Currency, error)
+	// clock time rather than tx start, so entries of a locked account sort in posting order
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateFXQuote(ctx context.Context, arg CreateFXQuoteParams) (FxQuote, error)
+	CreateFXRate(ctx context.Context, arg CreateFXRateParams) (FxRate, error)
+	CreateFXTransfer(ctx context.Context, arg CreateFXTransferParams) (Transfer, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) (OutboxMessage, error)
+	CreatePendingTransfer(ctx context.Context, arg CreatePendingTransferParams) (Transfer, error)
+	CreateReconciliationRun(ctx context.Context, source string) (ReconciliationRun, error)
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
+	CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRun, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	// ledger is append only, so a rebuilt statement for the same period is identical
+	CreateStatement(ctx context.Context, arg CreateStatementParams) (Statement, error)
+	CreateSystemAccount(ctx context.Context, arg CreateSystemAccountParams) (Account, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	// no row when the owner already used the idempotency key
+	CreateTransferBatch(ctx context.Context, arg CreateTransferBatchParams) (TransferBatch, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error)
 	DeleteAccount(ctx context.Context, id int64) error
+	DeleteExpiredSessions(ctx context.Context, expiredBefore time.Time) (int64, error)
+	DeleteIdempotencyKeysBefore(ctx context.Context, createdAt time.Time) (int64, error)
+	DeletePublishedOutboxMessages(ctx context.Context, publishedBefore time.Time) (int64, error)
+	ExpireHolds(ctx context.Context) (int64, error)
+	FinishReconciliationRun(ctx context.Context, arg FinishReconciliationRunParams) (ReconciliationRun, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
 	GetAccountUpdate(ctx context.Context, id int64) (Account, error)
+	// lock in id order like pairwise transfers do, so batches can't deadlock with them
+	GetAccountsForUpdate(ctx context.Context, ids []int64) ([]Account, error)
+	// deprecated, only for page_id requests
+	// last entry before the time, else the opening balance of the first entry, else no entries at all,
+	// ordered like the (account_id, created_at, id) index so each lookup reads a single row
+	GetBalanceBefore(ctx context.Context, arg GetBalanceBeforeParams) (int64, error)
+	GetCurrency(ctx context.Context, code string) (Currency, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetFXQuoteForUpdate(ctx context.Context, id uuid.UUID) (FxQuote, error)
+	GetFXRate(ctx context.Context, arg GetFXRateParams) (FxRate, error)
+	GetFXRateAt(ctx context.Context, arg GetFXRateAtParams) (FxRate, error)
+	GetHeldAmount(ctx context.Context, accountID int64) (int64, error)
+	GetHold(ctx context.Context, id int64) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, id int64) (Hold, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetOutboxMessage(ctx context.Context, id int64) (OutboxMessage, error)
+	GetReconciliationRun(ctx context.Context, id int64) (ReconciliationRun, error)
+	GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetScheduledTransferForUpdate(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetScheduledTransferRun(ctx context.Context, id int64) (ScheduledTransferRun, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetSessionForUpdate(ctx context.Context, id uuid.UUID) (Session, error)
+	GetStatement(ctx context.Context, id int64) (Statement, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferBatch(ctx context.Context, id int64) (TransferBatch, error)
+	GetTransferBatchByIdempotencyKey(ctx context.Context, arg GetTransferBatchByIdempotencyKeyParams) (TransferBatch, error)
+	GetTransferBatchForUpdate(ctx context.Context, id int64) (TransferBatch, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
+	// rolling windows ending now, reversals and transfers that never moved money don't count,
+	// a pending transfer being posted is left out with exclude_id
+	GetTransferUsage(ctx context.Context, arg GetTransferUsageParams) (GetTransferUsageRow, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserForUpdate(ctx context.Context, username string) (User, error)
+	GetVerifyEmailForUpdate(ctx context.Context, id int64) (VerifyEmail, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListAccountsPage(ctx context.Context, arg ListAccountsPageParams) ([]Account, error)
+	ListActiveSessions(ctx context.Context, username string) ([]Session, error)
+	ListCurrencies(ctx context.Context) ([]Currency, error)
+	ListDueScheduledTransfers(ctx context.Context, arg ListDueScheduledTransfersParams) ([]ScheduledTransfer, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListEntriesPage(ctx context.Context, arg ListEntriesPageParams) ([]Entry, error)
+	ListFXRateHistory(ctx context.Context, arg ListFXRateHistoryParams) ([]FxRate, error)
+	ListFXRates(ctx context.Context) ([]FxRate, error)
+	ListOrphanEntries(ctx context.Context, arg ListOrphanEntriesParams) ([]Entry, error)
+	// rows stay claimed until the transaction ends, other relays skip them
+	ListPendingOutboxMessages(ctx context.Context, limit int32) ([]OutboxMessage, error)
+	ListScheduledTransferRuns(ctx context.Context, arg ListScheduledTransferRunsParams) ([]ScheduledTransferRun, error)
+	ListScheduledTransfers(ctx context.Context, arg ListScheduledTransfersParams) ([]ScheduledTransfer, error)
+	ListStatementAccounts(ctx context.Context, arg ListStatementAccountsParams) ([]Account, error)
+	// ids follow posting order within an account, tx start times do not
+	ListStatementEntries(ctx context.Context, arg ListStatementEntriesParams) ([]ListStatementEntriesRow, error)
+	ListStatements(ctx context.Context, arg ListStatementsParams) ([]Statement, error)
+	// global, tier and account limits that apply to a transfer in the currency
+	ListTransferLimits(ctx context.Context, arg ListTransferLimitsParams) ([]TransferLimit, error)
+	ListTransferReversals(ctx context.Context, reversalOf sql.NullInt64) ([]Transfer, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	ListTransfersPage(ctx context.Context, arg ListTransfersPageParams) ([]Transfer, error)
+	MarkFXQuoteUsed(ctx context.Context, id uuid.UUID) (FxQuote, error)
+	MarkOutboxMessageFailed(ctx context.Context, arg MarkOutboxMessageFailedParams) (OutboxMessage, error)
+	MarkOutboxMessagePublished(ctx context.Context, id int64) error
+	MarkStatementEmailed(ctx context.Context, id int64) (Statement, error)
+	ReconcileAccounts(ctx context.Context, arg ReconcileAccountsParams) ([]ReconcileAccountsRow, error)
+	ReconcileTransfers(ctx context.Context, arg ReconcileTransfersParams) ([]ReconcileTransfersRow, error)
+	RotateSession(ctx context.Context, id uuid.UUID) (Session, error)
+	SearchTransfersByAmount(ctx context.Context, arg SearchTransfersByAmountParams) ([]Transfer, error)
+	SearchTransfersByAmountDesc(ctx context.Context, arg SearchTransfersByAmountDescParams) ([]Transfer, error)
+	// one query per sort order, outgoing and incoming are separate branches so each can walk
+	// its account index, incoming skips transfers already listed as outgoing and matches the
+	// currency it was credited in
+	SearchTransfersByCreatedAt(ctx context.Context, arg SearchTransfersByCreatedAtParams) ([]Transfer, error)
+	SearchTransfersByCreatedAtDesc(ctx context.Context, arg SearchTransfersByCreatedAtDescParams) ([]Transfer, error)
+	// unset limits keep their value, limits named in clear are removed
+	SetTransferLimit(ctx context.Context, arg SetTransferLimitParams) (TransferLimit, error)
 	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error)
+	UpdateCurrency(ctx context.Context, arg UpdateCurrencyParams) (Currency, error)
+	UpdateHoldStatus(ctx context.Context, arg UpdateHoldStatusParams) (Hold, error)
+	UpdateIdempotencyKeyResponse(ctx context.Context, arg UpdateIdempotencyKeyResponseParams) (IdempotencyKey, error)
+	UpdateScheduledTransfer(ctx context.Context, arg UpdateScheduledTransferParams) (ScheduledTransfer, error)
+	UpdateTransferBatch(ctx context.Context, arg UpdateTransferBatchParams) (TransferBatch, error)
+	UpdateTransferStatus(ctx context.Context, arg UpdateTransferStatusParams) (Transfer, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserTier(ctx context.Context, arg UpdateUserTierParams) (User, error)
+	UseVerifyEmail(ctx context.Context, id int64) (VerifyEmail, error)
+	VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,265 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: reconciliation.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+const createReconciliationRun = `-- name: CreateReconciliationRun :one
+INSERT INTO reconciliation_runs (
+  source
+) VALUES (
+  $1
+)
+RETURNING id, source, status, accounts_checked, transfers_checked, critical_findings, findings, error, started_at, finished_at
+`
+
+func (q *Queries) CreateReconciliationRun(ctx context.Context, source string) (ReconciliationRun, error) {
+	row := q.db.QueryRowContext(ctx, createReconciliationRun, source)
+	var i ReconciliationRun
+	err := row.Scan(
+		&i.ID,
+		&i.Source,
+		&i.Status,
+		&i.AccountsChecked,
+		&i.TransfersChecked,
+		&i.CriticalFindings,
+		&i.Findings,
+		&i.Error,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const finishReconciliationRun = `-- name: FinishReconciliationRun :one
+UPDATE reconciliation_runs
+SET
+  status = $1,
+  accounts_checked = $2,
+  transfers_checked = $3,
+  critical_findings = $4,
+  findings = $5,
+  error = $6,
+  finished_at = now()
+WHERE id = $7
+RETURNING id, source, status, accounts_checked, transfers_checked, critical_findings, findings, error, started_at, finished_at
+`
+
+type FinishReconciliationRunParams struct {
+	Status           string          `json:"status"`
+	AccountsChecked  int64           `json:"accounts_checked"`
+	TransfersChecked int64           `json:"transfers_checked"`
+	CriticalFindings int32           `json:"critical_findings"`
+	Findings         json.RawMessage `json:"findings"`
+	Error            sql.NullString  `json:"error"`
+	ID               int64           `json:"id"`
+}
+
+func (q *Queries) FinishReconciliationRun(ctx context.Context, arg FinishReconciliationRunParams) (ReconciliationRun, error) {
+	row := q.db.QueryRowContext(ctx, finishReconciliationRun,
+		arg.Status,
+		arg.AccountsChecked,
+		arg.TransfersChecked,
+		arg.CriticalFindings,
+		arg.Findings,
+		arg.Error,
+		arg.ID,
+	)
+	var i ReconciliationRun
+	err := row.Scan(
+		&i.ID,
+		&i.Source,
+		&i.Status,
+		&i.AccountsChecked,
+		&i.TransfersChecked,
+		&i.CriticalFindings,
+		&i.Findings,
+		&i.Error,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const getReconciliationRun = `-- name: GetReconciliationRun :one
+SELECT id, source, status, accounts_checked, transfers_checked, critical_findings, findings, error, started_at, finished_at FROM reconciliation_runs
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetReconciliationRun(ctx context.Context, id int64) (ReconciliationRun, error) {
+	row := q.db.QueryRowContext(ctx, getReconciliationRun, id)
+	var i ReconciliationRun
+	err := row.Scan(
+		&i.ID,
+		&i.Source,
+		&i.Status,
+		&i.AccountsChecked,
+		&i.TransfersChecked,
+		&i.CriticalFindings,
+		&i.Findings,
+		&i.Error,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const listOrphanEntries = `-- name: ListOrphanEntries :many
+SELECT id, account_id, amount, created_at, transfer_id, balance_after FROM entries
+WHERE transfer_id IS NULL AND id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListOrphanEntriesParams struct {
+	AfterID    int64 `json:"after_id"`
+	LimitCount int32 `json:"limit_count"`
+}
+
+func (q *Queries) ListOrphanEntries(ctx context.Context, arg ListOrphanEntriesParams) ([]Entry, error) {
+	rows, err := q.db.QueryContext(ctx, listOrphanEntries, arg.AfterID, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+			&i.BalanceAfter,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reconcileAccounts = `-- name: ReconcileAccounts :many
+SELECT
+  a.id, a.balance, a.currency,
+  COALESCE(SUM(e.amount), 0)::bigint AS entries_sum
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+WHERE a.id > $1
+GROUP BY a.id
+ORDER BY a.id
+LIMIT $2
+`
+
+type ReconcileAccountsParams struct {
+	AfterID    int64 `json:"after_id"`
+	LimitCount int32 `json:"limit_count"`
+}
+
+type ReconcileAccountsRow struct {
+	ID         int64  `json:"id"`
+	Balance    int64  `json:"balance"`
+	Currency   string `json:"currency"`
+	EntriesSum int64  `json:"entries_sum"`
+}
+
+func (q *Queries) ReconcileAccounts(ctx context.Context, arg ReconcileAccountsParams) ([]ReconcileAccountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, reconcileAccounts, arg.AfterID, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ReconcileAccountsRow{}
+	for rows.Next() {
+		var i ReconcileAccountsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Balance,
+			&i.Currency,
+			&i.EntriesSum,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reconcileTransfers = `-- name: ReconcileTransfers :many
+SELECT
+  t.id, t.status, (t.to_amount IS NOT NULL)::bool AS converted,
+  COUNT(e.id)::bigint AS entry_count,
+  COALESCE(SUM(e.amount) FILTER (WHERE a.currency = t.currency), 0)::bigint AS currency_sum,
+  COALESCE(SUM(e.amount) FILTER (WHERE a.currency <> t.currency), 0)::bigint AS to_currency_sum
+FROM transfers t
+LEFT JOIN entries e ON e.transfer_id = t.id
+LEFT JOIN accounts a ON a.id = e.account_id
+WHERE t.id > $1
+GROUP BY t.id
+ORDER BY t.id
+LIMIT $2
+`
+
+type ReconcileTransfersParams struct {
+	AfterID    int64 `json:"after_id"`
+	LimitCount int32 `json:"limit_count"`
+}
+
+type ReconcileTransfersRow struct {
+	ID            int64  `json:"id"`
+	Status        string `json:"status"`
+	Converted     bool   `json:"converted"`
+	EntryCount    int64  `json:"entry_count"`
+	CurrencySum   int64  `json:"currency_sum"`
+	ToCurrencySum int64  `json:"to_currency_sum"`
+}
+
+func (q *Queries) ReconcileTransfers(ctx context.Context, arg ReconcileTransfersParams) ([]ReconcileTransfersRow, error) {
+	rows, err := q.db.QueryContext(ctx, reconcileTransfers, arg.AfterID, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ReconcileTransfersRow{}
+	for rows.Next() {
+		var i ReconcileTransfersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Status,
+			&i.Converted,
+			&i.EntryCount,
+			&i.CurrencySum,
+			&i.ToCurrencySum,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
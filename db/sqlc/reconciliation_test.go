@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// test posted transfer entries are linked and net to zero
+func TestReconcileTransfers(t *testing.T) {
+	store := NewStore(testDB)
+	amount := utils.NewMoney(10, utils.RandomCurrency())
+
+	account1 := createFundedAccount(t, utils.NewMoney(0, amount.Currency))
+	account2 := createFundedAccount(t, utils.NewMoney(0, amount.Currency))
+	_, err := store.DepositTx(context.Background(), CashTxParams{
+		AccountID: account1.ID,
+		Amount:    amount,
+	})
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+	require.Equal(t, result.Transfer.ID, result.FromEntry.TransferID.Int64)
+	require.Equal(t, result.Transfer.ID, result.ToEntry.TransferID.Int64)
+
+	transfers, err := testQueries.ReconcileTransfers(context.Background(), ReconcileTransfersParams{
+		AfterID:    result.Transfer.ID - 1,
+		LimitCount: 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, result.Transfer.ID, transfers[0].ID)
+	require.Equal(t, TransferPosted, transfers[0].Status)
+	require.False(t, transfers[0].Converted)
+	require.Equal(t, int64(2), transfers[0].EntryCount)
+	require.Zero(t, transfers[0].CurrencySum)
+	require.Zero(t, transfers[0].ToCurrencySum)
+
+	accounts, err := testQueries.ReconcileAccounts(context.Background(), ReconcileAccountsParams{
+		AfterID:    account2.ID - 1,
+		LimitCount: 1,
+	})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, account2.ID, accounts[0].ID)
+	require.Equal(t, amount.Amount, accounts[0].Balance)
+	require.Equal(t, amount.Amount, accounts[0].EntriesSum)
+}
+
+// test entries without a transfer are listed as orphans
+func TestListOrphanEntries(t *testing.T) {
+	account := createRandomAccount(t)
+	entry := createRandomEntry(t, account)
+
+	entries, err := testQueries.ListOrphanEntries(context.Background(), ListOrphanEntriesParams{
+		AfterID:    entry.ID - 1,
+		LimitCount: 5,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	require.Equal(t, entry.ID, entries[0].ID)
+	for _, entry := range entries {
+		require.False(t, entry.TransferID.Valid)
+	}
+}
+
+// test run is stored with its findings
+func TestReconciliationRun(t *testing.T) {
+	run, err := testQueries.CreateReconciliationRun(context.Background(), "cli")
+	require.NoError(t, err)
+	require.Equal(t, "running", run.Status)
+	require.JSONEq(t, "[]", string(run.Findings))
+	require.False(t, run.FinishedAt.Valid)
+
+	findings := json.RawMessage(`[{"kind":"orphan_entry","severity":"warning","entry_id":1}]`)
+	finished, err := testQueries.FinishReconciliationRun(context.Background(), FinishReconciliationRunParams{
+		ID:               run.ID,
+		Status:           "warning",
+		AccountsChecked:  3,
+		TransfersChecked: 2,
+		Findings:         findings,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "warning", finished.Status)
+	require.Equal(t, int64(3), finished.AccountsChecked)
+	require.JSONEq(t, string(findings), string(finished.Findings))
+	require.True(t, finished.FinishedAt.Valid)
+
+	got, err := testQueries.GetReconciliationRun(context.Background(), run.ID)
+	require.NoError(t, err)
+	require.Equal(t, finished.Status, got.Status)
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomScheduledTransfer(t *testing.T, account1 Account, account2 Account, nextRunAt time.Time) ScheduledTransfer {
+	arg := CreateScheduledTransferParams{
+		Owner:         account1.Owner,
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Currency:      account1.Currency,
+		Schedule:      "0 9 1 * *",
+		StartAt:       nextRunAt,
+		NextRunAt:     sql.NullTime{Time: nextRunAt, Valid: true},
+	}
+
+	schedule, err := testQueries.CreateScheduledTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, schedule.ID)
+	require.Equal(t, arg.Owner, schedule.Owner)
+	require.Equal(t, arg.Amount, schedule.Amount)
+	require.Equal(t, arg.Schedule, schedule.Schedule)
+	require.Equal(t, ScheduleActive, schedule.Status)
+	require.False(t, schedule.EndAt.Valid)
+	require.WithinDuration(t, nextRunAt, schedule.NextRunAt.Time, time.Second)
+
+	return schedule
+}
+
+// test create scheduled transfer
+func TestCreateScheduledTransfer(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createFundedAccount(t, account1.BalanceMoney())
+	createRandomScheduledTransfer(t, account1, account2, time.Now().Add(time.Hour))
+}
+
+// test only active due schedules are picked
+func TestListDueScheduledTransfers(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createFundedAccount(t, account1.BalanceMoney())
+
+	due := createRandomScheduledTransfer(t, account1, account2, time.Now().Add(-time.Minute))
+	notDue := createRandomScheduledTransfer(t, account1, account2, time.Now().Add(time.Hour))
+	paused := createRandomScheduledTransfer(t, account1, account2, time.Now().Add(-time.Minute))
+
+	_, err := testQueries.UpdateScheduledTransfer(context.Background(), UpdateScheduledTransferParams{
+		ID:        paused.ID,
+		Amount:    paused.Amount,
+		Schedule:  paused.Schedule,
+		NextRunAt: paused.NextRunAt,
+		Status:    SchedulePaused,
+	})
+	require.NoError(t, err)
+
+	schedules, err := testQueries.ListDueScheduledTransfers(context.Background(), ListDueScheduledTransfersParams{
+		Now:        time.Now(),
+		LimitCount: 1000,
+	})
+	require.NoError(t, err)
+
+	ids := make(map[int64]bool, len(schedules))
+	for _, schedule := range schedules {
+		ids[schedule.ID] = true
+	}
+	require.True(t, ids[due.ID])
+	require.False(t, ids[notDue.ID])
+	require.False(t, ids[paused.ID])
+}
+
+// test edits see the locked row and can't reopen a finished schedule
+func TestUpdateScheduledTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createFundedAccount(t, account1.BalanceMoney())
+	schedule := createRandomScheduledTransfer(t, account1, account2, time.Now().Add(-time.Minute))
+
+	updated, err := store.UpdateScheduledTransferTx(context.Background(), UpdateScheduledTransferTxParams{
+		ID: schedule.ID,
+		Update: func(locked ScheduledTransfer) (UpdateScheduledTransferParams, error) {
+			require.Equal(t, schedule.ID, locked.ID)
+			return UpdateScheduledTransferParams{
+				Amount:    20,
+				Schedule:  locked.Schedule,
+				NextRunAt: locked.NextRunAt,
+				Status:    ScheduleFinished,
+			}, nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(20), updated.Amount)
+	require.Equal(t, ScheduleFinished, updated.Status)
+
+	_, err = store.UpdateScheduledTransferTx(context.Background(), UpdateScheduledTransferTxParams{
+		ID: schedule.ID,
+		Update: func(locked ScheduledTransfer) (UpdateScheduledTransferParams, error) {
+			t.Fatal("closed schedule must not be updated")
+			return UpdateScheduledTransferParams{}, nil
+		},
+	})
+	require.ErrorIs(t, err, ErrScheduleClosed)
+}
@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSession(t *testing.T, username string, expiresAt time.Time) Session {
+	id := uuid.New()
+	arg := CreateSessionParams{
+		ID:               id,
+		FamilyID:         id,
+		Username:         username,
+		RefreshTokenHash: utils.HashToken(utils.RandomString(32)),
+		UserAgent:        "test",
+		ClientIp:         "127.0.0.1",
+		ExpiresAt:        expiresAt,
+	}
+	session, err := testQueries.CreateSession(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.Equal(t, arg.ID, session.ID)
+	require.Equal(t, arg.FamilyID, session.FamilyID)
+	require.Equal(t, arg.Username, session.Username)
+	require.Equal(t, arg.RefreshTokenHash, session.RefreshTokenHash)
+	require.False(t, session.IsBlocked)
+	require.False(t, session.RotatedAt.Valid)
+	require.WithinDuration(t, arg.ExpiresAt, session.ExpiresAt, time.Second)
+	return session
+}
+
+// test only unblocked unexpired sessions are listed
+func TestListActiveSessions(t *testing.T) {
+	user := createRandomUser(t)
+	active := createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+	blocked := createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+	createRandomSession(t, user.Username, time.Now().Add(-time.Hour))
+
+	blocked, err := testQueries.BlockSession(context.Background(), blocked.ID)
+	require.NoError(t, err)
+	require.True(t, blocked.IsBlocked)
+
+	sessions, err := testQueries.ListActiveSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, active.ID, sessions[0].ID)
+}
+
+// test logout everywhere blocks every active session of the user only
+func TestBlockUserSessions(t *testing.T) {
+	user := createRandomUser(t)
+	other := createRandomUser(t)
+	for i := 0; i < 3; i++ {
+		createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+	}
+	otherSession := createRandomSession(t, other.Username, time.Now().Add(time.Hour))
+
+	blocked, err := testQueries.BlockUserSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), blocked)
+
+	sessions, err := testQueries.ListActiveSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+
+	session, err := testQueries.GetSession(context.Background(), otherSession.ID)
+	require.NoError(t, err)
+	require.False(t, session.IsBlocked)
+}
+
+// test expired sessions are purged
+func TestDeleteExpiredSessions(t *testing.T) {
+	user := createRandomUser(t)
+	expired := createRandomSession(t, user.Username, time.Now().Add(-time.Hour))
+	active := createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+
+	deleted, err := testQueries.DeleteExpiredSessions(context.Background(), time.Now())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, deleted, int64(1))
+
+	_, err = testQueries.GetSession(context.Background(), expired.ID)
+	require.Error(t, err)
+
+	_, err = testQueries.GetSession(context.Background(), active.ID)
+	require.NoError(t, err)
+}
+
+func rotateSessionParams(session Session) RotateSessionTxParams {
+	return RotateSessionTxParams{
+		ID:               session.ID,
+		Username:         session.Username,
+		RefreshTokenHash: session.RefreshTokenHash,
+		NextSession: func(rotated Session) (CreateSessionParams, error) {
+			return CreateSessionParams{
+				ID:               uuid.New(),
+				RefreshTokenHash: utils.HashToken(utils.RandomString(32)),
+				ExpiresAt:        rotated.ExpiresAt,
+			}, nil
+		},
+	}
+}
+
+// test rotation replaces the session within its family
+func TestRotateSessionTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	session := createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+
+	result, err := store.RotateSessionTx(context.Background(), rotateSessionParams(session))
+	require.NoError(t, err)
+	require.Equal(t, session.ID, result.Rotated.ID)
+	require.True(t, result.Rotated.RotatedAt.Valid)
+
+	require.NotEqual(t, session.ID, result.Session.ID)
+	require.Equal(t, session.FamilyID, result.Session.FamilyID)
+	require.Equal(t, session.Username, result.Session.Username)
+	require.False(t, result.Session.RotatedAt.Valid)
+
+	sessions, err := testQueries.ListActiveSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, result.Session.ID, sessions[0].ID)
+
+	// wrong hash is rejected without touching the session
+	arg := rotateSessionParams(result.Session)
+	arg.RefreshTokenHash = utils.HashToken(utils.RandomString(32))
+	_, err = store.RotateSessionTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrSessionMismatch)
+
+	expired := createRandomSession(t, user.Username, time.Now().Add(-time.Minute))
+	_, err = store.RotateSessionTx(context.Background(), rotateSessionParams(expired))
+	require.ErrorIs(t, err, ErrSessionExpired)
+}
+
+// test presenting a rotated token revokes the family
+func TestRotateSessionTxReuse(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	session := createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+	other := createRandomSession(t, user.Username, time.Now().Add(time.Hour))
+
+	first, err := store.RotateSessionTx(context.Background(), rotateSessionParams(session))
+	require.NoError(t, err)
+	second, err := store.RotateSessionTx(context.Background(), rotateSessionParams(first.Session))
+	require.NoError(t, err)
+
+	_, err = store.RotateSessionTx(context.Background(), rotateSessionParams(session))
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	latest, err := testQueries.GetSession(context.Background(), second.Session.ID)
+	require.NoError(t, err)
+	require.True(t, latest.IsBlocked)
+
+	_, err = store.RotateSessionTx(context.Background(), rotateSessionParams(latest))
+	require.ErrorIs(t, err, ErrSessionBlocked)
+
+	// other logins of the user are left alone
+	sessions, err := testQueries.ListActiveSessions(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, other.ID, sessions[0].ID)
+}
@@ -0,0 +1,194 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: statement.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const createStatement = `-- name: CreateStatement :one
+INSERT INTO statements (
+  account_id, period_start, period_end, opening_balance, closing_balance, entry_count
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (account_id, period_start, period_end) DO UPDATE
+SET entry_count = EXCLUDED.entry_count
+RETURNING id, account_id, period_start, period_end, opening_balance, closing_balance, entry_count, emailed_at, created_at
+`
+
+type CreateStatementParams struct {
+	AccountID      int64     `json:"account_id"`
+	PeriodStart    time.Time `json:"period_start"`
+	PeriodEnd      time.Time `json:"period_end"`
+	OpeningBalance int64     `json:"opening_balance"`
+	ClosingBalance int64     `json:"closing_balance"`
+	EntryCount     int32     `json:"entry_count"`
+}
+
+// ledger is append only, so a rebuilt statement for the same period is identical
+func (q *Queries) CreateStatement(ctx context.Context, arg CreateStatementParams) (Statement, error) {
+	row := q.db.QueryRowContext(ctx, createStatement,
+		arg.AccountID,
+		arg.PeriodStart,
+		arg.PeriodEnd,
+		arg.OpeningBalance,
+		arg.ClosingBalance,
+		arg.EntryCount,
+	)
+	var i Statement
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.PeriodStart,
+		&i.PeriodEnd,
+		&i.OpeningBalance,
+		&i.ClosingBalance,
+		&i.EntryCount,
+		&i.EmailedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getStatement = `-- name: GetStatement :one
+SELECT id, account_id, period_start, period_end, opening_balance, closing_balance, entry_count, emailed_at, created_at FROM statements
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetStatement(ctx context.Context, id int64) (Statement, error) {
+	row := q.db.QueryRowContext(ctx, getStatement, id)
+	var i Statement
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.PeriodStart,
+		&i.PeriodEnd,
+		&i.OpeningBalance,
+		&i.ClosingBalance,
+		&i.EntryCount,
+		&i.EmailedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listStatementAccounts = `-- name: ListStatementAccounts :many
+SELECT id, owner, balance, currency, created_at, overdraft_limit FROM accounts
+WHERE id > $1
+  AND owner NOT IN ('system_cash', 'system_fx')
+  AND created_at < $2::timestamptz
+ORDER BY id
+LIMIT $3
+`
+
+type ListStatementAccountsParams struct {
+	AfterID       int64     `json:"after_id"`
+	CreatedBefore time.Time `json:"created_before"`
+	LimitCount    int32     `json:"limit_count"`
+}
+
+func (q *Queries) ListStatementAccounts(ctx context.Context, arg ListStatementAccountsParams) ([]Account, error) {
+	rows, err := q.db.QueryContext(ctx, listStatementAccounts, arg.AfterID, arg.CreatedBefore, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.OverdraftLimit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStatements = `-- name: ListStatements :many
+SELECT id, account_id, period_start, period_end, opening_balance, closing_balance, entry_count, emailed_at, created_at FROM statements
+WHERE account_id = $1
+ORDER BY period_start DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListStatementsParams struct {
+	AccountID int64 `json:"account_id"`
+	Limit     int32 `json:"limit"`
+	Offset    int32 `json:"offset"`
+}
+
+func (q *Queries) ListStatements(ctx context.Context, arg ListStatementsParams) ([]Statement, error) {
+	rows, err := q.db.QueryContext(ctx, listStatements, arg.AccountID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Statement{}
+	for rows.Next() {
+		var i Statement
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.PeriodStart,
+			&i.PeriodEnd,
+			&i.OpeningBalance,
+			&i.ClosingBalance,
+			&i.EntryCount,
+			&i.EmailedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markStatementEmailed = `-- name: MarkStatementEmailed :one
+UPDATE statements
+SET emailed_at = now()
+WHERE id = $1
+RETURNING id, account_id, period_start, period_end, opening_balance, closing_balance, entry_count, emailed_at, created_at
+`
+
+func (q *Queries) MarkStatementEmailed(ctx context.Context, id int64) (Statement, error) {
+	row := q.db.QueryRowContext(ctx, markStatementEmailed, id)
+	var i Statement
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.PeriodStart,
+		&i.PeriodEnd,
+		&i.OpeningBalance,
+		&i.ClosingBalance,
+		&i.EntryCount,
+		&i.EmailedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomStatement(t *testing.T, account Account, periodStart time.Time) Statement {
+	arg := CreateStatementParams{
+		AccountID:      account.ID,
+		PeriodStart:    periodStart,
+		PeriodEnd:      periodStart.AddDate(0, 1, 0),
+		OpeningBalance: account.Balance,
+		ClosingBalance: account.Balance,
+		EntryCount:     0,
+	}
+
+	statement, err := testQueries.CreateStatement(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, statement.ID)
+	require.Equal(t, arg.AccountID, statement.AccountID)
+	require.WithinDuration(t, arg.PeriodStart, statement.PeriodStart, time.Second)
+	require.WithinDuration(t, arg.PeriodEnd, statement.PeriodEnd, time.Second)
+	require.Equal(t, arg.OpeningBalance, statement.OpeningBalance)
+	require.Equal(t, arg.ClosingBalance, statement.ClosingBalance)
+	require.False(t, statement.EmailedAt.Valid)
+
+	return statement
+}
+
+// test same period is issued once
+func TestCreateStatement(t *testing.T) {
+	account := createRandomAccount(t)
+	periodStart := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	statement1 := createRandomStatement(t, account, periodStart)
+	statement2 := createRandomStatement(t, account, periodStart)
+	require.Equal(t, statement1.ID, statement2.ID)
+
+	emailed, err := testQueries.MarkStatementEmailed(context.Background(), statement1.ID)
+	require.NoError(t, err)
+	require.True(t, emailed.EmailedAt.Valid)
+
+	// rebuilding keeps the emailed mark
+	statement3 := createRandomStatement(t, account, periodStart)
+	require.Equal(t, statement1.ID, statement3.ID)
+
+	got, err := testQueries.GetStatement(context.Background(), statement1.ID)
+	require.NoError(t, err)
+	require.True(t, got.EmailedAt.Valid)
+}
+
+// test newest period comes first
+func TestListStatements(t *testing.T) {
+	account := createRandomAccount(t)
+	for month := time.January; month <= time.March; month++ {
+		createRandomStatement(t, account, time.Date(2026, month, 1, 0, 0, 0, 0, time.UTC))
+	}
+
+	statements, err := testQueries.ListStatements(context.Background(), ListStatementsParams{
+		AccountID: account.ID,
+		Limit:     5,
+		Offset:    0,
+	})
+	require.NoError(t, err)
+	require.Len(t, statements, 3)
+	require.Equal(t, time.March, statements[0].PeriodStart.UTC().Month())
+	require.Equal(t, time.January, statements[2].PeriodStart.UTC().Month())
+}
+
+// test system accounts and accounts opened later get no statement
+func TestListStatementAccounts(t *testing.T) {
+	account := createRandomAccount(t)
+
+	accounts, err := testQueries.ListStatementAccounts(context.Background(), ListStatementAccountsParams{
+		AfterID:       account.ID - 1,
+		CreatedBefore: time.Now().Add(time.Minute),
+		LimitCount:    1,
+	})
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, account.ID, accounts[0].ID)
+
+	accounts, err = testQueries.ListStatementAccounts(context.Background(), ListStatementAccountsParams{
+		AfterID:       0,
+		CreatedBefore: time.Now().Add(time.Minute),
+		LimitCount:    1000,
+	})
+	require.NoError(t, err)
+	for _, account := range accounts {
+		require.False(t, account.IsSystem())
+	}
+
+	accounts, err = testQueries.ListStatementAccounts(context.Background(), ListStatementAccountsParams{
+		AfterID:       account.ID - 1,
+		CreatedBefore: account.CreatedAt,
+		LimitCount:    1,
+	})
+	require.NoError(t, err)
+	require.Empty(t, accounts)
+}
@@ -8,8 +8,29 @@ import (
 
 type Store interface {
 	Querier
+	SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]Transfer, error)
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	TransferBatchTx(ctx context.Context, arg TransferBatchTxParams) (TransferBatchTxResult, error)
 	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
+	UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error)
+	VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error)
+	ResendVerifyEmailTx(ctx context.Context, arg ResendVerifyEmailTxParams) (User, error)
+	RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error)
+	CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (CreateAccountTxResult, error)
+	DepositTx(ctx context.Context, arg CashTxParams) (TransferTxResult, error)
+	WithdrawTx(ctx context.Context, arg CashTxParams) (TransferTxResult, error)
+	ImportFXRatesTx(ctx context.Context, rates []CreateFXRateParams) (int, error)
+	FXTransferTx(ctx context.Context, arg FXTransferTxParams) (FXTransferTxResult, error)
+	CreateHoldTx(ctx context.Context, arg CreateHoldTxParams) (CreateHoldTxResult, error)
+	CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error)
+	VoidHoldTx(ctx context.Context, holdID int64) (Hold, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	PostTransferTx(ctx context.Context, transferID int64) (TransferTxResult, error)
+	FailTransferTx(ctx context.Context, transferID int64, reason string) (Transfer, error)
+	CancelTransferTx(ctx context.Context, transferID int64) (Transfer, error)
+	RecordScheduledRunTx(ctx context.Context, arg RecordScheduledRunTxParams) (ScheduledTransferRun, error)
+	UpdateScheduledTransferTx(ctx context.Context, arg UpdateScheduledTransferTxParams) (ScheduledTransfer, error)
+	ClaimOutboxMessagesTx(ctx context.Context, arg ClaimOutboxMessagesTxParams) error
 }
 
 type SqlStore struct {
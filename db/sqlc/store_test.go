@@ -2,22 +2,26 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
 func TestTransferTx(t *testing.T) {
 	store := NewStore(testDB)
 
-	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
-	fmt.Println(">> before:", account1.Balance, account2.Balance)
-
 	// run n concurrent transactions
 	n := 5
-	amount := int64(10)
+	amount := utils.NewMoney(10, utils.RandomCurrency())
+
+	account1 := createFundedAccount(t, utils.NewMoney(int64(n)*amount.Amount, amount.Currency))
+	account2 := createFundedAccount(t, utils.NewMoney(0, amount.Currency))
+	fmt.Println(">> before:", account1.Balance, account2.Balance)
 
 	// channels to get info from goroutines
 	errors := make(chan error)
@@ -49,7 +53,7 @@ func TestTransferTx(t *testing.T) {
 		require.NotEmpty(t, transfer)
 		require.Equal(t, account1.ID, transfer.FromAccountID)
 		require.Equal(t, account2.ID, transfer.ToAccountID)
-		require.Equal(t, amount, transfer.Amount)
+		require.Equal(t, amount.Amount, transfer.Amount)
 		require.NotZero(t, transfer.ID)
 		require.NotZero(t, transfer.CreatedAt)
 
@@ -60,7 +64,7 @@ func TestTransferTx(t *testing.T) {
 		fromEntry := result.FromEntry
 		require.NotEmpty(t, fromEntry)
 		require.Equal(t, account1.ID, fromEntry.AccountID)
-		require.Equal(t, -amount, fromEntry.Amount)
+		require.Equal(t, -amount.Amount, fromEntry.Amount)
 		require.NotZero(t, fromEntry.ID)
 		require.NotZero(t, fromEntry.CreatedAt)
 
@@ -70,7 +74,7 @@ func TestTransferTx(t *testing.T) {
 		ToEntry := result.ToEntry
 		require.NotEmpty(t, ToEntry)
 		require.Equal(t, account2.ID, ToEntry.AccountID)
-		require.Equal(t, amount, ToEntry.Amount)
+		require.Equal(t, amount.Amount, ToEntry.Amount)
 		require.NotZero(t, ToEntry.ID)
 		require.NotZero(t, ToEntry.CreatedAt)
 
@@ -86,15 +90,19 @@ func TestTransferTx(t *testing.T) {
 		require.NotEmpty(t, toAccount)
 		require.Equal(t, account2.ID, toAccount.ID)
 
+		// entries keep the balance right after posting
+		require.Equal(t, fromAccount.Balance, fromEntry.BalanceAfter)
+		require.Equal(t, toAccount.Balance, ToEntry.BalanceAfter)
+
 		diff1 := account1.Balance - fromAccount.Balance
 		diff2 := toAccount.Balance - account2.Balance
 
 		fmt.Println(">> tx:", fromAccount.Balance, toAccount.Balance)
 		require.Equal(t, diff1, diff2)
 		require.True(t, diff1 > 0)
-		require.True(t, diff1%amount == 0)
+		require.True(t, diff1%amount.Amount == 0)
 
-		k := int(diff1 / amount)
+		k := int(diff1 / amount.Amount)
 		require.True(t, k >= 1 && k <= n)
 		require.NotContains(t, existed, k)
 		existed[k] = true
@@ -107,20 +115,21 @@ func TestTransferTx(t *testing.T) {
 	require.NoError(t, err)
 
 	fmt.Println(">> after:", updatedAccount1.Balance, updatedAccount2.Balance)
-	require.Equal(t, account1.Balance-int64(n)*amount, updatedAccount1.Balance)
-	require.Equal(t, account2.Balance+int64(n)*amount, updatedAccount2.Balance)
+	require.Equal(t, account1.Balance-int64(n)*amount.Amount, updatedAccount1.Balance)
+	require.Equal(t, account2.Balance+int64(n)*amount.Amount, updatedAccount2.Balance)
 }
 
 func TestTransferTxDeadlock(t *testing.T) {
 	store := NewStore(testDB)
 
-	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
-	fmt.Println(">> before:", account1.Balance, account2.Balance)
-
 	// run n concurrent transactions
 	n := 10
-	amount := int64(10)
+	amount := utils.NewMoney(10, utils.RandomCurrency())
+	balance := utils.NewMoney(int64(n)*amount.Amount, amount.Currency)
+
+	account1 := createFundedAccount(t, balance)
+	account2 := createFundedAccount(t, balance)
+	fmt.Println(">> before:", account1.Balance, account2.Balance)
 
 	// channels to get info from goroutines
 	errors := make(chan error)
@@ -160,3 +169,653 @@ func TestTransferTxDeadlock(t *testing.T) {
 	require.Equal(t, account1.Balance, updatedAccount1.Balance)
 	require.Equal(t, account2.Balance, updatedAccount2.Balance)
 }
+
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	balance := utils.NewMoney(10, utils.RandomCurrency())
+	amount := utils.NewMoney(30, balance.Currency)
+
+	testCases := []struct {
+		name           string
+		overdraftLimit sql.NullInt64
+		checkResult    func(t *testing.T, err error)
+	}{
+		{
+			name:           "NoOverdraft",
+			overdraftLimit: OverdraftLimit(OverdraftNone, 0),
+			checkResult: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInsufficientFunds)
+			},
+		},
+		{
+			name:           "WithinLimit",
+			overdraftLimit: OverdraftLimit(OverdraftLimited, amount.Amount-balance.Amount),
+			checkResult: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:           "OverLimit",
+			overdraftLimit: OverdraftLimit(OverdraftLimited, amount.Amount-balance.Amount-1),
+			checkResult: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, ErrInsufficientFunds)
+			},
+		},
+		{
+			name:           "Unlimited",
+			overdraftLimit: OverdraftLimit(OverdraftUnlimited, 0),
+			checkResult: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			account1 := createFundedAccount(t, balance)
+			account2 := createFundedAccount(t, utils.NewMoney(0, balance.Currency))
+
+			account1, err := testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+				ID:             account1.ID,
+				OverdraftLimit: tc.overdraftLimit,
+			})
+			require.NoError(t, err)
+
+			_, err = store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amount,
+			})
+			tc.checkResult(t, err)
+
+			// balance must stay untouched on failure
+			expectedBalance := balance.Amount - amount.Amount
+			if err != nil {
+				expectedBalance = balance.Amount
+			}
+			updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+			require.NoError(t, err)
+			require.Equal(t, expectedBalance, updatedAccount1.Balance)
+		})
+	}
+}
+
+func TestTransferTxCurrencyMismatch(t *testing.T) {
+	store := NewStore(testDB)
+
+	amount := utils.NewMoney(10, utils.USD)
+	account1 := createFundedAccount(t, utils.NewMoney(amount.Amount, utils.USD))
+	account2 := createFundedAccount(t, utils.NewMoney(0, utils.EUR))
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        amount,
+	})
+	require.ErrorIs(t, err, utils.ErrCurrencyMismatch)
+}
+
+func TestTransferTxIdempotency(t *testing.T) {
+	store := NewStore(testDB)
+
+	amount := utils.NewMoney(10, utils.RandomCurrency())
+	account1 := createFundedAccount(t, utils.NewMoney(amount.Amount*2, amount.Currency))
+	account2 := createFundedAccount(t, utils.NewMoney(0, amount.Currency))
+
+	arg := TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        amount,
+		Idempotency: &IdempotencyParams{
+			Username:    account1.Owner,
+			Key:         utils.RandomString(16),
+			RequestHash: utils.RandomString(64),
+		},
+	}
+
+	result1, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// retry replays the first result without moving money again
+	result2, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, result1.Transfer.ID, result2.Transfer.ID)
+	require.Equal(t, result1.FromAccount.Balance, result2.FromAccount.Balance)
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-amount.Amount, updatedAccount1.Balance)
+
+	// same key with different request is rejected
+	arg.Amount.Amount = amount.Amount * 2
+	arg.Idempotency.RequestHash = utils.RandomString(64)
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrIdempotencyKeyReused)
+}
+
+func TestDepositWithdrawTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	amount := utils.NewMoney(10, account.Currency)
+
+	result, err := store.DepositTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    amount,
+	})
+	require.NoError(t, err)
+
+	cash := result.FromAccount
+	require.Equal(t, SystemCashOwner, cash.Owner)
+	require.Equal(t, account.Currency, cash.Currency)
+	require.Equal(t, OverdraftUnlimited, cash.OverdraftPolicy())
+	require.Equal(t, account.ID, result.ToAccount.ID)
+	require.Equal(t, account.Balance+amount.Amount, result.ToAccount.Balance)
+
+	// entries stay balanced against the cash account
+	require.Equal(t, -amount.Amount, result.FromEntry.Amount)
+	require.Equal(t, amount.Amount, result.ToEntry.Amount)
+
+	result, err = store.WithdrawTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    amount,
+	})
+	require.NoError(t, err)
+	require.Equal(t, cash.ID, result.ToAccount.ID)
+	require.Equal(t, account.Balance, result.FromAccount.Balance)
+
+	// can't withdraw more than the balance
+	_, err = store.WithdrawTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    utils.NewMoney(account.Balance+1, account.Currency),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// cash account itself can't be used as customer account
+	_, err = store.DepositTx(context.Background(), CashTxParams{
+		AccountID: cash.ID,
+		Amount:    amount,
+	})
+	require.ErrorIs(t, err, ErrSystemAccount)
+
+	// amount must be in the account currency
+	_, err = store.DepositTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    utils.NewMoney(amount.Amount, otherCurrency(account.Currency)),
+	})
+	require.ErrorIs(t, err, utils.ErrCurrencyMismatch)
+}
+
+func otherCurrency(currency string) string {
+	if currency == utils.USD {
+		return utils.EUR
+	}
+	return utils.USD
+}
+
+func TestFXTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	amount := utils.NewMoney(1000, utils.USD)
+	converted := utils.NewMoney(915, utils.EUR)
+
+	account1 := createFundedAccount(t, amount)
+	account2 := createFundedAccount(t, utils.NewMoney(0, utils.EUR))
+	quote := createRandomFXQuote(t, account1.Owner, amount, converted)
+
+	arg := FXTransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        amount,
+		QuoteID:       quote.ID,
+		Username:      account1.Owner,
+	}
+
+	result, err := store.FXTransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	transfer := result.Transfer
+	require.Equal(t, amount.Amount, transfer.Amount)
+	require.Equal(t, amount.Currency, transfer.Currency)
+	require.Equal(t, converted.Amount, transfer.ToAmount.Int64)
+	require.Equal(t, converted.Currency, transfer.ToCurrency.String)
+	require.Equal(t, quote.Rate, transfer.FxRate.String)
+	require.Equal(t, quote.SpreadBps, transfer.FxSpreadBps.Int32)
+	require.Equal(t, quote.ID, transfer.FxQuoteID.UUID)
+
+	require.Equal(t, int64(0), result.FromAccount.Balance)
+	require.Equal(t, converted.Amount, result.ToAccount.Balance)
+
+	// each currency stays balanced through fx house accounts
+	require.Equal(t, -amount.Amount, result.FromEntry.Amount)
+	require.Equal(t, amount.Amount, result.FXFromEntry.Amount)
+	require.Equal(t, -converted.Amount, result.FXToEntry.Amount)
+	require.Equal(t, converted.Amount, result.ToEntry.Amount)
+
+	houseFrom, err := store.GetAccount(context.Background(), result.FXFromEntry.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, SystemFXOwner, houseFrom.Owner)
+	require.Equal(t, amount.Currency, houseFrom.Currency)
+
+	houseTo, err := store.GetAccount(context.Background(), result.FXToEntry.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, SystemFXOwner, houseTo.Owner)
+	require.Equal(t, converted.Currency, houseTo.Currency)
+
+	// quote can only be used once
+	_, err = store.FXTransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrFXQuoteUsed)
+
+	// quote belongs to the user who requested it
+	arg.QuoteID = createRandomFXQuote(t, account2.Owner, amount, converted).ID
+	_, err = store.FXTransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrFXQuoteNotFound)
+
+	arg.QuoteID = uuid.New()
+	_, err = store.FXTransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrFXQuoteNotFound)
+}
+
+func TestHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	amount := utils.NewMoney(10, utils.RandomCurrency())
+	account1 := createFundedAccount(t, utils.NewMoney(amount.Amount*2, amount.Currency))
+	account2 := createFundedAccount(t, utils.NewMoney(0, amount.Currency))
+
+	arg := CreateHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      amount,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	result1, err := store.CreateHoldTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, HoldActive, result1.Hold.Status)
+
+	// holds reserve funds but don't move them
+	account, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, account.Balance)
+
+	result2, err := store.CreateHoldTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// both holds reserve the whole balance
+	_, err = store.CreateHoldTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        utils.NewMoney(1, amount.Currency),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	// partial capture posts the captured amount and releases the rest
+	captured := utils.NewMoney(amount.Amount-3, amount.Currency)
+	capture, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: result1.Hold.ID,
+		Amount: captured,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldCaptured, capture.Hold.Status)
+	require.Equal(t, captured.Amount, capture.Hold.CapturedAmount)
+	require.Equal(t, capture.Transfer.ID, capture.Hold.TransferID.Int64)
+	require.Equal(t, captured.Amount, capture.Transfer.Amount)
+	require.Equal(t, -captured.Amount, capture.FromEntry.Amount)
+	require.Equal(t, captured.Amount, capture.ToEntry.Amount)
+	require.Equal(t, account1.Balance-captured.Amount, capture.FromAccount.Balance)
+	require.Equal(t, captured.Amount, capture.ToAccount.Balance)
+
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: result1.Hold.ID})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+
+	// void releases the second hold without moving money
+	hold, err := store.VoidHoldTx(context.Background(), result2.Hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldVoided, hold.Status)
+
+	held, err := testQueries.GetHeldAmount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Zero(t, held)
+
+	_, err = store.VoidHoldTx(context.Background(), result2.Hold.ID)
+	require.ErrorIs(t, err, ErrHoldNotActive)
+}
+
+func TestCaptureHoldTxExceeded(t *testing.T) {
+	store := NewStore(testDB)
+
+	amount := utils.NewMoney(10, utils.RandomCurrency())
+	account1 := createFundedAccount(t, amount)
+	account2 := createFundedAccount(t, utils.NewMoney(0, amount.Currency))
+
+	result, err := store.CreateHoldTx(context.Background(), CreateHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      amount,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: result.Hold.ID,
+		Amount: utils.NewMoney(amount.Amount+1, amount.Currency),
+	})
+	require.ErrorIs(t, err, ErrHoldAmountExceeded)
+
+	// failed capture leaves hold active
+	hold, err := testQueries.GetHold(context.Background(), result.Hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldActive, hold.Status)
+}
+
+func TestReverseTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	amount := utils.NewMoney(10, utils.RandomCurrency())
+	account1 := createFundedAccount(t, amount)
+	account2 := createFundedAccount(t, utils.NewMoney(0, amount.Currency))
+
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+
+	// partial reversal moves money back and links to the original
+	partial := utils.NewMoney(4, amount.Currency)
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     partial,
+	})
+	require.NoError(t, err)
+	require.Equal(t, transfer.Transfer.ID, result.Transfer.ReversalOf.Int64)
+	require.Equal(t, account2.ID, result.Transfer.FromAccountID)
+	require.Equal(t, account1.ID, result.Transfer.ToAccountID)
+	require.Equal(t, partial.Amount, result.Transfer.Amount)
+	require.Equal(t, -partial.Amount, result.FromEntry.Amount)
+	require.Equal(t, partial.Amount, result.ToEntry.Amount)
+	require.Equal(t, partial.Amount, result.Original.ReversedAmount)
+	require.Equal(t, ReversalPartial, result.Original.ReversalStatus())
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     amount,
+	})
+	require.ErrorIs(t, err, ErrReversalAmountExceeded)
+
+	// reversal of a reversal is refused
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: result.Transfer.ID})
+	require.ErrorIs(t, err, ErrTransferNotReversible)
+
+	// zero amount reverses the rest
+	result, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: transfer.Transfer.ID})
+	require.NoError(t, err)
+	require.Equal(t, amount.Amount-partial.Amount, result.Transfer.Amount)
+	require.Equal(t, ReversalFull, result.Original.ReversalStatus())
+	require.Equal(t, TransferReversed, result.Original.Status)
+	require.True(t, result.Original.ReversedAt.Valid)
+	require.Equal(t, account1.Balance, result.ToAccount.Balance)
+	require.Zero(t, result.FromAccount.Balance)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: transfer.Transfer.ID})
+	require.ErrorIs(t, err, ErrTransferReversed)
+
+	reversals, err := testQueries.ListTransferReversals(context.Background(), sql.NullInt64{Int64: transfer.Transfer.ID, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, reversals, 2)
+}
+
+func TestReverseTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(testDB)
+
+	amount := utils.NewMoney(10, utils.RandomCurrency())
+	account1 := createFundedAccount(t, amount)
+	account2 := createFundedAccount(t, utils.NewMoney(0, amount.Currency))
+	account3 := createFundedAccount(t, utils.NewMoney(0, amount.Currency))
+
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+
+	// receiver already spent the money and has no overdraft
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account2.ID,
+		ToAccountId:   account3.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: transfer.Transfer.ID})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	original, err := testQueries.GetTransfer(context.Background(), transfer.Transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, ReversalNone, original.ReversalStatus())
+}
+
+// test reversals of deposits lock the cash account first, like deposits do
+func TestReverseDepositConcurrent(t *testing.T) {
+	store := NewStore(testDB)
+
+	// cash account of a new currency is opened after the customer's, so it sorts last by id
+	currency := createRandomCurrency(t)
+	account := createFundedAccount(t, utils.NewMoney(0, currency.Code))
+	deposit, err := store.DepositTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    utils.NewMoney(10, currency.Code),
+	})
+	require.NoError(t, err)
+	require.Greater(t, deposit.FromAccount.ID, account.ID)
+
+	n := 10
+	one := utils.NewMoney(1, currency.Code)
+	errs := make(chan error, 2*n)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.DepositTx(context.Background(), CashTxParams{AccountID: account.ID, Amount: one})
+			errs <- err
+		}()
+		go func() {
+			_, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: deposit.Transfer.ID, Amount: one})
+			errs <- err
+		}()
+	}
+	for i := 0; i < 2*n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	account, err = testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), account.Balance)
+}
+
+func TestPostTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	amount := utils.NewMoney(10, utils.RandomCurrency())
+	account1 := createFundedAccount(t, amount)
+	account2 := createFundedAccount(t, utils.NewMoney(0, amount.Currency))
+
+	// pending transfer doesn't move money
+	transfer := createRandomPendingTransfer(t, account1, account2, amount)
+	account, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, account.Balance)
+
+	result, err := store.PostTransferTx(context.Background(), transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, TransferPosted, result.Transfer.Status)
+	require.True(t, result.Transfer.PostedAt.Valid)
+	require.Equal(t, -amount.Amount, result.FromEntry.Amount)
+	require.Equal(t, amount.Amount, result.ToEntry.Amount)
+	require.Zero(t, result.FromAccount.Balance)
+	require.Equal(t, amount.Amount, result.ToAccount.Balance)
+
+	// posted transfer can't post twice or be cancelled
+	_, err = store.PostTransferTx(context.Background(), transfer.ID)
+	require.ErrorIs(t, err, ErrInvalidTransferTransition)
+
+	_, err = store.CancelTransferTx(context.Background(), transfer.ID)
+	require.ErrorIs(t, err, ErrInvalidTransferTransition)
+
+	// second transfer has nothing left to spend
+	transfer = createRandomPendingTransfer(t, account1, account2, amount)
+	_, err = store.PostTransferTx(context.Background(), transfer.ID)
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	failed, err := store.FailTransferTx(context.Background(), transfer.ID, "insufficient funds")
+	require.NoError(t, err)
+	require.Equal(t, TransferFailed, failed.Status)
+	require.Equal(t, "insufficient funds", failed.FailureReason.String)
+	require.True(t, failed.FailedAt.Valid)
+
+	_, err = store.PostTransferTx(context.Background(), transfer.ID)
+	require.ErrorIs(t, err, ErrInvalidTransferTransition)
+}
+
+func TestCancelTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	amount := utils.NewMoney(10, utils.RandomCurrency())
+	account1 := createFundedAccount(t, amount)
+	account2 := createFundedAccount(t, utils.NewMoney(0, amount.Currency))
+
+	transfer := createRandomPendingTransfer(t, account1, account2, amount)
+	cancelled, err := store.CancelTransferTx(context.Background(), transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, TransferCancelled, cancelled.Status)
+	require.True(t, cancelled.CancelledAt.Valid)
+	require.False(t, cancelled.FailureReason.Valid)
+
+	// money that never moved can't be given back
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{TransferID: transfer.ID})
+	require.ErrorIs(t, err, ErrTransferNotReversible)
+
+	_, err = store.FailTransferTx(context.Background(), transfer.ID, "too late")
+	require.ErrorIs(t, err, ErrInvalidTransferTransition)
+}
+
+func TestRecordScheduledRunTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	amount := utils.NewMoney(10, utils.RandomCurrency())
+	account1 := createFundedAccount(t, amount)
+	account2 := createFundedAccount(t, utils.NewMoney(0, amount.Currency))
+
+	scheduledFor := time.Now().Add(-time.Minute).Truncate(time.Second)
+	schedule := createRandomScheduledTransfer(t, account1, account2, scheduledFor)
+
+	arg := TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        amount,
+		Idempotency:   ScheduledRunIdempotency(schedule, scheduledFor),
+	}
+	result, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// retried occurrence replays instead of paying twice
+	replay, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, result.Transfer.ID, replay.Transfer.ID)
+
+	run, err := store.RecordScheduledRunTx(context.Background(), RecordScheduledRunTxParams{
+		ScheduledTransferID: schedule.ID,
+		ScheduledFor:        scheduledFor,
+		TransferID:          result.Transfer.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledRunPosted, run.Status)
+	require.Equal(t, result.Transfer.ID, run.TransferID.Int64)
+
+	// schedule moves to its next occurrence
+	updated, err := testQueries.GetScheduledTransfer(context.Background(), schedule.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduleActive, updated.Status)
+	require.True(t, updated.NextRunAt.Time.After(time.Now()))
+
+	// same occurrence is recorded once
+	_, err = store.RecordScheduledRunTx(context.Background(), RecordScheduledRunTxParams{
+		ScheduledTransferID: schedule.ID,
+		ScheduledFor:        scheduledFor,
+		FailureReason:       "duplicate",
+	})
+	require.ErrorIs(t, err, ErrScheduledRunRecorded)
+
+	// failed occurrence past the end finishes the schedule
+	nextRunAt := updated.NextRunAt.Time
+	_, err = testQueries.UpdateScheduledTransfer(context.Background(), UpdateScheduledTransferParams{
+		ID:        updated.ID,
+		Amount:    updated.Amount,
+		Schedule:  updated.Schedule,
+		EndAt:     sql.NullTime{Time: nextRunAt, Valid: true},
+		NextRunAt: updated.NextRunAt,
+		Status:    ScheduleActive,
+	})
+	require.NoError(t, err)
+
+	run, err = store.RecordScheduledRunTx(context.Background(), RecordScheduledRunTxParams{
+		ScheduledTransferID: schedule.ID,
+		ScheduledFor:        nextRunAt,
+		FailureReason:       ErrInsufficientFunds.Error(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledRunFailed, run.Status)
+	require.Equal(t, ErrInsufficientFunds.Error(), run.FailureReason.String)
+
+	updated, err = testQueries.GetScheduledTransfer(context.Background(), schedule.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduleFinished, updated.Status)
+	require.False(t, updated.NextRunAt.Valid)
+
+	runs, err := testQueries.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+		ScheduledTransferID: schedule.ID,
+		Limit:               5,
+	})
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+}
+
+// test a failure that repeats on every run pauses the schedule
+func TestRecordScheduledRunTxPause(t *testing.T) {
+	store := NewStore(testDB)
+
+	amount := utils.NewMoney(10, utils.RandomCurrency())
+	account1 := createFundedAccount(t, amount)
+	account2 := createFundedAccount(t, utils.NewMoney(0, amount.Currency))
+
+	scheduledFor := time.Now().Add(-time.Minute).Truncate(time.Second)
+	schedule := createRandomScheduledTransfer(t, account1, account2, scheduledFor)
+
+	run, err := store.RecordScheduledRunTx(context.Background(), RecordScheduledRunTxParams{
+		ScheduledTransferID: schedule.ID,
+		ScheduledFor:        scheduledFor,
+		FailureReason:       sql.ErrNoRows.Error(),
+		Pause:               true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ScheduledRunFailed, run.Status)
+
+	updated, err := testQueries.GetScheduledTransfer(context.Background(), schedule.ID)
+	require.NoError(t, err)
+	require.Equal(t, SchedulePaused, updated.Status)
+
+	// paused schedules are not due
+	due, err := testQueries.ListDueScheduledTransfers(context.Background(), ListDueScheduledTransfersParams{
+		Now:        time.Now(),
+		LimitCount: 1000,
+	})
+	require.NoError(t, err)
+	for _, s := range due {
+		require.NotEqual(t, schedule.ID, s.ID)
+	}
+}
@@ -7,25 +7,195 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const addTransferReversedAmount = `-- name: AddTransferReversedAmount :one
+UPDATE transfers
+SET reversed_amount = reversed_amount + $1
+WHERE id = $2
+RETURNING id, from_account_id, to_account_id, amount, created_at, currency, to_amount, to_currency, fx_rate, fx_spread_bps, fx_quote_id, reversal_of, reversed_amount, status, failure_reason, posted_at, failed_at, cancelled_at, reversed_at, memo, batch_id
+`
+
+type AddTransferReversedAmountParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddTransferReversedAmount(ctx context.Context, arg AddTransferReversedAmountParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, addTransferReversedAmount, arg.Amount, arg.ID)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Currency,
+		&i.ToAmount,
+		&i.ToCurrency,
+		&i.FxRate,
+		&i.FxSpreadBps,
+		&i.FxQuoteID,
+		&i.ReversalOf,
+		&i.ReversedAmount,
+		&i.Status,
+		&i.FailureReason,
+		&i.PostedAt,
+		&i.FailedAt,
+		&i.CancelledAt,
+		&i.ReversedAt,
+		&i.Memo,
+		&i.BatchID,
+	)
+	return i, err
+}
+
+const createFXTransfer = `-- name: CreateFXTransfer :one
+INSERT INTO transfers (
+  from_account_id, to_account_id, amount, currency,
+  to_amount, to_currency, fx_rate, fx_spread_bps, fx_quote_id, memo, status, posted_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 'posted', now()
+)
+RETURNING id, from_account_id, to_account_id, amount, created_at, currency, to_amount, to_currency, fx_rate, fx_spread_bps, fx_quote_id, reversal_of, reversed_amount, status, failure_reason, posted_at, failed_at, cancelled_at, reversed_at, memo, batch_id
+`
+
+type CreateFXTransferParams struct {
+	FromAccountID int64          `json:"from_account_id"`
+	ToAccountID   int64          `json:"to_account_id"`
+	Amount        int64          `json:"amount"`
+	Currency      string         `json:"currency"`
+	ToAmount      sql.NullInt64  `json:"to_amount"`
+	ToCurrency    sql.NullString `json:"to_currency"`
+	FxRate        sql.NullString `json:"fx_rate"`
+	FxSpreadBps   sql.NullInt32  `json:"fx_spread_bps"`
+	FxQuoteID     uuid.NullUUID  `json:"fx_quote_id"`
+	Memo          string         `json:"memo"`
+}
+
+func (q *Queries) CreateFXTransfer(ctx context.Context, arg CreateFXTransferParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, createFXTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Currency,
+		arg.ToAmount,
+		arg.ToCurrency,
+		arg.FxRate,
+		arg.FxSpreadBps,
+		arg.FxQuoteID,
+		arg.Memo,
+	)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Currency,
+		&i.ToAmount,
+		&i.ToCurrency,
+		&i.FxRate,
+		&i.FxSpreadBps,
+		&i.FxQuoteID,
+		&i.ReversalOf,
+		&i.ReversedAmount,
+		&i.Status,
+		&i.FailureReason,
+		&i.PostedAt,
+		&i.FailedAt,
+		&i.CancelledAt,
+		&i.ReversedAt,
+		&i.Memo,
+		&i.BatchID,
+	)
+	return i, err
+}
+
+const createPendingTransfer = `-- name: CreatePendingTransfer :one
+INSERT INTO transfers (
+  from_account_id, to_account_id, amount, currency, status
+) VALUES (
+  $1, $2, $3, $4, 'pending'
 )
+RETURNING id, from_account_id, to_account_id, amount, created_at, currency, to_amount, to_currency, fx_rate, fx_spread_bps, fx_quote_id, reversal_of, reversed_amount, status, failure_reason, posted_at, failed_at, cancelled_at, reversed_at, memo, batch_id
+`
+
+type CreatePendingTransferParams struct {
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+}
+
+func (q *Queries) CreatePendingTransfer(ctx context.Context, arg CreatePendingTransferParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, createPendingTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Currency,
+	)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Currency,
+		&i.ToAmount,
+		&i.ToCurrency,
+		&i.FxRate,
+		&i.FxSpreadBps,
+		&i.FxQuoteID,
+		&i.ReversalOf,
+		&i.ReversedAmount,
+		&i.Status,
+		&i.FailureReason,
+		&i.PostedAt,
+		&i.FailedAt,
+		&i.CancelledAt,
+		&i.ReversedAt,
+		&i.Memo,
+		&i.BatchID,
+	)
+	return i, err
+}
 
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
-  from_account_id, to_account_id, amount
+  from_account_id, to_account_id, amount, currency, reversal_of, memo, batch_id, status, posted_at
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5, $6, $7, 'posted', now()
 )
-RETURNING id, from_account_id, to_account_id, amount, created_at
+RETURNING id, from_account_id, to_account_id, amount, created_at, currency, to_amount, to_currency, fx_rate, fx_spread_bps, fx_quote_id, reversal_of, reversed_amount, status, failure_reason, posted_at, failed_at, cancelled_at, reversed_at, memo, batch_id
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID int64         `json:"from_account_id"`
+	ToAccountID   int64         `json:"to_account_id"`
+	Amount        int64         `json:"amount"`
+	Currency      string        `json:"currency"`
+	ReversalOf    sql.NullInt64 `json:"reversal_of"`
+	Memo          string        `json:"memo"`
+	BatchID       sql.NullInt64 `json:"batch_id"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRowContext(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Currency,
+		arg.ReversalOf,
+		arg.Memo,
+		arg.BatchID,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -33,12 +203,28 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Currency,
+		&i.ToAmount,
+		&i.ToCurrency,
+		&i.FxRate,
+		&i.FxSpreadBps,
+		&i.FxQuoteID,
+		&i.ReversalOf,
+		&i.ReversedAmount,
+		&i.Status,
+		&i.FailureReason,
+		&i.PostedAt,
+		&i.FailedAt,
+		&i.CancelledAt,
+		&i.ReversedAt,
+		&i.Memo,
+		&i.BatchID,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, currency, to_amount, to_currency, fx_rate, fx_spread_bps, fx_quote_id, reversal_of, reversed_amount, status, failure_reason, posted_at, failed_at, cancelled_at, reversed_at, memo, batch_id FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -51,25 +237,128 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Currency,
+		&i.ToAmount,
+		&i.ToCurrency,
+		&i.FxRate,
+		&i.FxSpreadBps,
+		&i.FxQuoteID,
+		&i.ReversalOf,
+		&i.ReversedAmount,
+		&i.Status,
+		&i.FailureReason,
+		&i.PostedAt,
+		&i.FailedAt,
+		&i.CancelledAt,
+		&i.ReversedAt,
+		&i.Memo,
+		&i.BatchID,
 	)
 	return i, err
 }
 
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, currency, to_amount, to_currency, fx_rate, fx_spread_bps, fx_quote_id, reversal_of, reversed_amount, status, failure_reason, posted_at, failed_at, cancelled_at, reversed_at, memo, batch_id FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Currency,
+		&i.ToAmount,
+		&i.ToCurrency,
+		&i.FxRate,
+		&i.FxSpreadBps,
+		&i.FxQuoteID,
+		&i.ReversalOf,
+		&i.ReversedAmount,
+		&i.Status,
+		&i.FailureReason,
+		&i.PostedAt,
+		&i.FailedAt,
+		&i.CancelledAt,
+		&i.ReversedAt,
+		&i.Memo,
+		&i.BatchID,
+	)
+	return i, err
+}
+
+const listTransferReversals = `-- name: ListTransferReversals :many
+SELECT id, from_account_id, to_account_id, amount, created_at, currency, to_amount, to_currency, fx_rate, fx_spread_bps, fx_quote_id, reversal_of, reversed_amount, status, failure_reason, posted_at, failed_at, cancelled_at, reversed_at, memo, batch_id FROM transfers
+WHERE reversal_of = $1
+ORDER BY id
+`
+
+func (q *Queries) ListTransferReversals(ctx context.Context, reversalOf sql.NullInt64) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, listTransferReversals, reversalOf)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Currency,
+			&i.ToAmount,
+			&i.ToCurrency,
+			&i.FxRate,
+			&i.FxSpreadBps,
+			&i.FxQuoteID,
+			&i.ReversalOf,
+			&i.ReversedAmount,
+			&i.Status,
+			&i.FailureReason,
+			&i.PostedAt,
+			&i.FailedAt,
+			&i.CancelledAt,
+			&i.ReversedAt,
+			&i.Memo,
+			&i.BatchID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, currency, to_amount, to_currency, fx_rate, fx_spread_bps, fx_quote_id, reversal_of, reversed_amount, status, failure_reason, posted_at, failed_at, cancelled_at, reversed_at, memo, batch_id FROM transfers
 WHERE
-    from_account_id = $1 OR
-    to_account_id = $2
+    (from_account_id = $1 OR to_account_id = $2) AND
+    ($5::varchar IS NULL OR status = $5)
 ORDER BY id
 LIMIT $3
 OFFSET $4
 `
 
 type ListTransfersParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Limit         int32 `json:"limit"`
-	Offset        int32 `json:"offset"`
+	FromAccountID int64          `json:"from_account_id"`
+	ToAccountID   int64          `json:"to_account_id"`
+	Limit         int32          `json:"limit"`
+	Offset        int32          `json:"offset"`
+	Status        sql.NullString `json:"status"`
 }
 
 func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
@@ -78,6 +367,320 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 		arg.ToAccountID,
 		arg.Limit,
 		arg.Offset,
+		arg.Status,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Currency,
+			&i.ToAmount,
+			&i.ToCurrency,
+			&i.FxRate,
+			&i.FxSpreadBps,
+			&i.FxQuoteID,
+			&i.ReversalOf,
+			&i.ReversedAmount,
+			&i.Status,
+			&i.FailureReason,
+			&i.PostedAt,
+			&i.FailedAt,
+			&i.CancelledAt,
+			&i.ReversedAt,
+			&i.Memo,
+			&i.BatchID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransfersPage = `-- name: ListTransfersPage :many
+SELECT id, from_account_id, to_account_id, amount, created_at, currency, to_amount, to_currency, fx_rate, fx_spread_bps, fx_quote_id, reversal_of, reversed_amount, status, failure_reason, posted_at, failed_at, cancelled_at, reversed_at, memo, batch_id FROM transfers
+WHERE
+    (from_account_id = $1 OR to_account_id = $1) AND
+    ($2::varchar IS NULL OR status = $2) AND
+    (created_at, id) > ($3::timestamptz, $4::bigint)
+ORDER BY created_at, id
+LIMIT $5
+`
+
+type ListTransfersPageParams struct {
+	AccountID      int64          `json:"account_id"`
+	Status         sql.NullString `json:"status"`
+	AfterCreatedAt time.Time      `json:"after_created_at"`
+	AfterID        int64          `json:"after_id"`
+	LimitCount     int32          `json:"limit_count"`
+}
+
+func (q *Queries) ListTransfersPage(ctx context.Context, arg ListTransfersPageParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, listTransfersPage,
+		arg.AccountID,
+		arg.Status,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Currency,
+			&i.ToAmount,
+			&i.ToCurrency,
+			&i.FxRate,
+			&i.FxSpreadBps,
+			&i.FxQuoteID,
+			&i.ReversalOf,
+			&i.ReversedAmount,
+			&i.Status,
+			&i.FailureReason,
+			&i.PostedAt,
+			&i.FailedAt,
+			&i.CancelledAt,
+			&i.ReversedAt,
+			&i.Memo,
+			&i.BatchID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchTransfersByAmount = `-- name: SearchTransfersByAmount :many
+WITH owned AS (
+  SELECT a.id FROM accounts a
+  WHERE
+    a.owner = $2 AND
+    ($3::bigint IS NULL OR a.id = $3)
+)
+(SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, t.currency, t.to_amount, t.to_currency, t.fx_rate, t.fx_spread_bps, t.fx_quote_id, t.reversal_of, t.reversed_amount, t.status, t.failure_reason, t.posted_at, t.failed_at, t.cancelled_at, t.reversed_at, t.memo, t.batch_id FROM transfers t
+  WHERE
+    $4::bool AND
+    t.from_account_id IN (SELECT id FROM owned) AND
+    ($5::bigint IS NULL OR t.to_account_id = $5) AND
+    ($6::varchar IS NULL OR t.currency = $6) AND
+    ($7::bigint IS NULL OR t.amount >= $7) AND
+    ($8::bigint IS NULL OR t.amount <= $8) AND
+    ($9::timestamptz IS NULL OR t.created_at >= $9) AND
+    ($10::timestamptz IS NULL OR t.created_at < $10) AND
+    ($11::varchar IS NULL OR t.status = $11) AND
+    ($12::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', $12)) AND
+    ($13::bigint IS NULL OR (t.amount, t.id) > ($14::bigint, $13))
+  ORDER BY t.amount, t.id
+  LIMIT $1)
+UNION ALL
+(SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, t.currency, t.to_amount, t.to_currency, t.fx_rate, t.fx_spread_bps, t.fx_quote_id, t.reversal_of, t.reversed_amount, t.status, t.failure_reason, t.posted_at, t.failed_at, t.cancelled_at, t.reversed_at, t.memo, t.batch_id FROM transfers t
+  WHERE
+    $15::bool AND
+    t.to_account_id IN (SELECT id FROM owned) AND
+    NOT ($4::bool AND t.from_account_id IN (SELECT id FROM owned)) AND
+    ($5::bigint IS NULL OR t.from_account_id = $5) AND
+    ($6::varchar IS NULL OR COALESCE(t.to_currency, t.currency) = $6) AND
+    ($7::bigint IS NULL OR t.amount >= $7) AND
+    ($8::bigint IS NULL OR t.amount <= $8) AND
+    ($9::timestamptz IS NULL OR t.created_at >= $9) AND
+    ($10::timestamptz IS NULL OR t.created_at < $10) AND
+    ($11::varchar IS NULL OR t.status = $11) AND
+    ($12::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', $12)) AND
+    ($13::bigint IS NULL OR (t.amount, t.id) > ($14::bigint, $13))
+  ORDER BY t.amount, t.id
+  LIMIT $1)
+ORDER BY amount, id
+LIMIT $1
+`
+
+type SearchTransfersByAmountParams struct {
+	LimitCount     int32          `json:"limit_count"`
+	Owner          string         `json:"owner"`
+	AccountID      sql.NullInt64  `json:"account_id"`
+	Outgoing       bool           `json:"outgoing"`
+	CounterpartyID sql.NullInt64  `json:"counterparty_id"`
+	Currency       sql.NullString `json:"currency"`
+	MinAmount      sql.NullInt64  `json:"min_amount"`
+	MaxAmount      sql.NullInt64  `json:"max_amount"`
+	FromTime       sql.NullTime   `json:"from_time"`
+	ToTime         sql.NullTime   `json:"to_time"`
+	Status         sql.NullString `json:"status"`
+	Query          sql.NullString `json:"query"`
+	AfterID        sql.NullInt64  `json:"after_id"`
+	AfterAmount    int64          `json:"after_amount"`
+	Incoming       bool           `json:"incoming"`
+}
+
+func (q *Queries) SearchTransfersByAmount(ctx context.Context, arg SearchTransfersByAmountParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, searchTransfersByAmount,
+		arg.LimitCount,
+		arg.Owner,
+		arg.AccountID,
+		arg.Outgoing,
+		arg.CounterpartyID,
+		arg.Currency,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Status,
+		arg.Query,
+		arg.AfterID,
+		arg.AfterAmount,
+		arg.Incoming,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Currency,
+			&i.ToAmount,
+			&i.ToCurrency,
+			&i.FxRate,
+			&i.FxSpreadBps,
+			&i.FxQuoteID,
+			&i.ReversalOf,
+			&i.ReversedAmount,
+			&i.Status,
+			&i.FailureReason,
+			&i.PostedAt,
+			&i.FailedAt,
+			&i.CancelledAt,
+			&i.ReversedAt,
+			&i.Memo,
+			&i.BatchID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchTransfersByAmountDesc = `-- name: SearchTransfersByAmountDesc :many
+WITH owned AS (
+  SELECT a.id FROM accounts a
+  WHERE
+    a.owner = $2 AND
+    ($3::bigint IS NULL OR a.id = $3)
+)
+(SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, t.currency, t.to_amount, t.to_currency, t.fx_rate, t.fx_spread_bps, t.fx_quote_id, t.reversal_of, t.reversed_amount, t.status, t.failure_reason, t.posted_at, t.failed_at, t.cancelled_at, t.reversed_at, t.memo, t.batch_id FROM transfers t
+  WHERE
+    $4::bool AND
+    t.from_account_id IN (SELECT id FROM owned) AND
+    ($5::bigint IS NULL OR t.to_account_id = $5) AND
+    ($6::varchar IS NULL OR t.currency = $6) AND
+    ($7::bigint IS NULL OR t.amount >= $7) AND
+    ($8::bigint IS NULL OR t.amount <= $8) AND
+    ($9::timestamptz IS NULL OR t.created_at >= $9) AND
+    ($10::timestamptz IS NULL OR t.created_at < $10) AND
+    ($11::varchar IS NULL OR t.status = $11) AND
+    ($12::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', $12)) AND
+    ($13::bigint IS NULL OR (t.amount, t.id) < ($14::bigint, $13))
+  ORDER BY t.amount DESC, t.id DESC
+  LIMIT $1)
+UNION ALL
+(SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, t.currency, t.to_amount, t.to_currency, t.fx_rate, t.fx_spread_bps, t.fx_quote_id, t.reversal_of, t.reversed_amount, t.status, t.failure_reason, t.posted_at, t.failed_at, t.cancelled_at, t.reversed_at, t.memo, t.batch_id FROM transfers t
+  WHERE
+    $15::bool AND
+    t.to_account_id IN (SELECT id FROM owned) AND
+    NOT ($4::bool AND t.from_account_id IN (SELECT id FROM owned)) AND
+    ($5::bigint IS NULL OR t.from_account_id = $5) AND
+    ($6::varchar IS NULL OR COALESCE(t.to_currency, t.currency) = $6) AND
+    ($7::bigint IS NULL OR t.amount >= $7) AND
+    ($8::bigint IS NULL OR t.amount <= $8) AND
+    ($9::timestamptz IS NULL OR t.created_at >= $9) AND
+    ($10::timestamptz IS NULL OR t.created_at < $10) AND
+    ($11::varchar IS NULL OR t.status = $11) AND
+    ($12::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', $12)) AND
+    ($13::bigint IS NULL OR (t.amount, t.id) < ($14::bigint, $13))
+  ORDER BY t.amount DESC, t.id DESC
+  LIMIT $1)
+ORDER BY amount DESC, id DESC
+LIMIT $1
+`
+
+type SearchTransfersByAmountDescParams struct {
+	LimitCount     int32          `json:"limit_count"`
+	Owner          string         `json:"owner"`
+	AccountID      sql.NullInt64  `json:"account_id"`
+	Outgoing       bool           `json:"outgoing"`
+	CounterpartyID sql.NullInt64  `json:"counterparty_id"`
+	Currency       sql.NullString `json:"currency"`
+	MinAmount      sql.NullInt64  `json:"min_amount"`
+	MaxAmount      sql.NullInt64  `json:"max_amount"`
+	FromTime       sql.NullTime   `json:"from_time"`
+	ToTime         sql.NullTime   `json:"to_time"`
+	Status         sql.NullString `json:"status"`
+	Query          sql.NullString `json:"query"`
+	AfterID        sql.NullInt64  `json:"after_id"`
+	AfterAmount    int64          `json:"after_amount"`
+	Incoming       bool           `json:"incoming"`
+}
+
+func (q *Queries) SearchTransfersByAmountDesc(ctx context.Context, arg SearchTransfersByAmountDescParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, searchTransfersByAmountDesc,
+		arg.LimitCount,
+		arg.Owner,
+		arg.AccountID,
+		arg.Outgoing,
+		arg.CounterpartyID,
+		arg.Currency,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Status,
+		arg.Query,
+		arg.AfterID,
+		arg.AfterAmount,
+		arg.Incoming,
 	)
 	if err != nil {
 		return nil, err
@@ -92,6 +695,22 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Currency,
+			&i.ToAmount,
+			&i.ToCurrency,
+			&i.FxRate,
+			&i.FxSpreadBps,
+			&i.FxQuoteID,
+			&i.ReversalOf,
+			&i.ReversedAmount,
+			&i.Status,
+			&i.FailureReason,
+			&i.PostedAt,
+			&i.FailedAt,
+			&i.CancelledAt,
+			&i.ReversedAt,
+			&i.Memo,
+			&i.BatchID,
 		); err != nil {
 			return nil, err
 		}
@@ -105,3 +724,304 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 	}
 	return items, nil
 }
+
+const searchTransfersByCreatedAt = `-- name: SearchTransfersByCreatedAt :many
+WITH owned AS (
+  SELECT a.id FROM accounts a
+  WHERE
+    a.owner = $2 AND
+    ($3::bigint IS NULL OR a.id = $3)
+)
+(SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, t.currency, t.to_amount, t.to_currency, t.fx_rate, t.fx_spread_bps, t.fx_quote_id, t.reversal_of, t.reversed_amount, t.status, t.failure_reason, t.posted_at, t.failed_at, t.cancelled_at, t.reversed_at, t.memo, t.batch_id FROM transfers t
+  WHERE
+    $4::bool AND
+    t.from_account_id IN (SELECT id FROM owned) AND
+    ($5::bigint IS NULL OR t.to_account_id = $5) AND
+    ($6::varchar IS NULL OR t.currency = $6) AND
+    ($7::bigint IS NULL OR t.amount >= $7) AND
+    ($8::bigint IS NULL OR t.amount <= $8) AND
+    ($9::timestamptz IS NULL OR t.created_at >= $9) AND
+    ($10::timestamptz IS NULL OR t.created_at < $10) AND
+    ($11::varchar IS NULL OR t.status = $11) AND
+    ($12::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', $12)) AND
+    ($13::bigint IS NULL OR (t.created_at, t.id) > ($14::timestamptz, $13))
+  ORDER BY t.created_at, t.id
+  LIMIT $1)
+UNION ALL
+(SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, t.currency, t.to_amount, t.to_currency, t.fx_rate, t.fx_spread_bps, t.fx_quote_id, t.reversal_of, t.reversed_amount, t.status, t.failure_reason, t.posted_at, t.failed_at, t.cancelled_at, t.reversed_at, t.memo, t.batch_id FROM transfers t
+  WHERE
+    $15::bool AND
+    t.to_account_id IN (SELECT id FROM owned) AND
+    NOT ($4::bool AND t.from_account_id IN (SELECT id FROM owned)) AND
+    ($5::bigint IS NULL OR t.from_account_id = $5) AND
+    ($6::varchar IS NULL OR COALESCE(t.to_currency, t.currency) = $6) AND
+    ($7::bigint IS NULL OR t.amount >= $7) AND
+    ($8::bigint IS NULL OR t.amount <= $8) AND
+    ($9::timestamptz IS NULL OR t.created_at >= $9) AND
+    ($10::timestamptz IS NULL OR t.created_at < $10) AND
+    ($11::varchar IS NULL OR t.status = $11) AND
+    ($12::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', $12)) AND
+    ($13::bigint IS NULL OR (t.created_at, t.id) > ($14::timestamptz, $13))
+  ORDER BY t.created_at, t.id
+  LIMIT $1)
+ORDER BY created_at, id
+LIMIT $1
+`
+
+type SearchTransfersByCreatedAtParams struct {
+	LimitCount     int32          `json:"limit_count"`
+	Owner          string         `json:"owner"`
+	AccountID      sql.NullInt64  `json:"account_id"`
+	Outgoing       bool           `json:"outgoing"`
+	CounterpartyID sql.NullInt64  `json:"counterparty_id"`
+	Currency       sql.NullString `json:"currency"`
+	MinAmount      sql.NullInt64  `json:"min_amount"`
+	MaxAmount      sql.NullInt64  `json:"max_amount"`
+	FromTime       sql.NullTime   `json:"from_time"`
+	ToTime         sql.NullTime   `json:"to_time"`
+	Status         sql.NullString `json:"status"`
+	Query          sql.NullString `json:"query"`
+	AfterID        sql.NullInt64  `json:"after_id"`
+	AfterCreatedAt time.Time      `json:"after_created_at"`
+	Incoming       bool           `json:"incoming"`
+}
+
+// one query per sort order, outgoing and incoming are separate branches so each can walk
+// its account index, incoming skips transfers already listed as outgoing and matches the
+// currency it was credited in
+func (q *Queries) SearchTransfersByCreatedAt(ctx context.Context, arg SearchTransfersByCreatedAtParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, searchTransfersByCreatedAt,
+		arg.LimitCount,
+		arg.Owner,
+		arg.AccountID,
+		arg.Outgoing,
+		arg.CounterpartyID,
+		arg.Currency,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Status,
+		arg.Query,
+		arg.AfterID,
+		arg.AfterCreatedAt,
+		arg.Incoming,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Currency,
+			&i.ToAmount,
+			&i.ToCurrency,
+			&i.FxRate,
+			&i.FxSpreadBps,
+			&i.FxQuoteID,
+			&i.ReversalOf,
+			&i.ReversedAmount,
+			&i.Status,
+			&i.FailureReason,
+			&i.PostedAt,
+			&i.FailedAt,
+			&i.CancelledAt,
+			&i.ReversedAt,
+			&i.Memo,
+			&i.BatchID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchTransfersByCreatedAtDesc = `-- name: SearchTransfersByCreatedAtDesc :many
+WITH owned AS (
+  SELECT a.id FROM accounts a
+  WHERE
+    a.owner = $2 AND
+    ($3::bigint IS NULL OR a.id = $3)
+)
+(SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, t.currency, t.to_amount, t.to_currency, t.fx_rate, t.fx_spread_bps, t.fx_quote_id, t.reversal_of, t.reversed_amount, t.status, t.failure_reason, t.posted_at, t.failed_at, t.cancelled_at, t.reversed_at, t.memo, t.batch_id FROM transfers t
+  WHERE
+    $4::bool AND
+    t.from_account_id IN (SELECT id FROM owned) AND
+    ($5::bigint IS NULL OR t.to_account_id = $5) AND
+    ($6::varchar IS NULL OR t.currency = $6) AND
+    ($7::bigint IS NULL OR t.amount >= $7) AND
+    ($8::bigint IS NULL OR t.amount <= $8) AND
+    ($9::timestamptz IS NULL OR t.created_at >= $9) AND
+    ($10::timestamptz IS NULL OR t.created_at < $10) AND
+    ($11::varchar IS NULL OR t.status = $11) AND
+    ($12::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', $12)) AND
+    ($13::bigint IS NULL OR (t.created_at, t.id) < ($14::timestamptz, $13))
+  ORDER BY t.created_at DESC, t.id DESC
+  LIMIT $1)
+UNION ALL
+(SELECT t.id, t.from_account_id, t.to_account_id, t.amount, t.created_at, t.currency, t.to_amount, t.to_currency, t.fx_rate, t.fx_spread_bps, t.fx_quote_id, t.reversal_of, t.reversed_amount, t.status, t.failure_reason, t.posted_at, t.failed_at, t.cancelled_at, t.reversed_at, t.memo, t.batch_id FROM transfers t
+  WHERE
+    $15::bool AND
+    t.to_account_id IN (SELECT id FROM owned) AND
+    NOT ($4::bool AND t.from_account_id IN (SELECT id FROM owned)) AND
+    ($5::bigint IS NULL OR t.from_account_id = $5) AND
+    ($6::varchar IS NULL OR COALESCE(t.to_currency, t.currency) = $6) AND
+    ($7::bigint IS NULL OR t.amount >= $7) AND
+    ($8::bigint IS NULL OR t.amount <= $8) AND
+    ($9::timestamptz IS NULL OR t.created_at >= $9) AND
+    ($10::timestamptz IS NULL OR t.created_at < $10) AND
+    ($11::varchar IS NULL OR t.status = $11) AND
+    ($12::varchar IS NULL OR to_tsvector('simple', t.memo) @@ plainto_tsquery('simple', $12)) AND
+    ($13::bigint IS NULL OR (t.created_at, t.id) < ($14::timestamptz, $13))
+  ORDER BY t.created_at DESC, t.id DESC
+  LIMIT $1)
+ORDER BY created_at DESC, id DESC
+LIMIT $1
+`
+
+type SearchTransfersByCreatedAtDescParams struct {
+	LimitCount     int32          `json:"limit_count"`
+	Owner          string         `json:"owner"`
+	AccountID      sql.NullInt64  `json:"account_id"`
+	Outgoing       bool           `json:"outgoing"`
+	CounterpartyID sql.NullInt64  `json:"counterparty_id"`
+	Currency       sql.NullString `json:"currency"`
+	MinAmount      sql.NullInt64  `json:"min_amount"`
+	MaxAmount      sql.NullInt64  `json:"max_amount"`
+	FromTime       sql.NullTime   `json:"from_time"`
+	ToTime         sql.NullTime   `json:"to_time"`
+	Status         sql.NullString `json:"status"`
+	Query          sql.NullString `json:"query"`
+	AfterID        sql.NullInt64  `json:"after_id"`
+	AfterCreatedAt time.Time      `json:"after_created_at"`
+	Incoming       bool           `json:"incoming"`
+}
+
+func (q *Queries) SearchTransfersByCreatedAtDesc(ctx context.Context, arg SearchTransfersByCreatedAtDescParams) ([]Transfer, error) {
+	rows, err := q.db.QueryContext(ctx, searchTransfersByCreatedAtDesc,
+		arg.LimitCount,
+		arg.Owner,
+		arg.AccountID,
+		arg.Outgoing,
+		arg.CounterpartyID,
+		arg.Currency,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.FromTime,
+		arg.ToTime,
+		arg.Status,
+		arg.Query,
+		arg.AfterID,
+		arg.AfterCreatedAt,
+		arg.Incoming,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.Currency,
+			&i.ToAmount,
+			&i.ToCurrency,
+			&i.FxRate,
+			&i.FxSpreadBps,
+			&i.FxQuoteID,
+			&i.ReversalOf,
+			&i.ReversedAmount,
+			&i.Status,
+			&i.FailureReason,
+			&i.PostedAt,
+			&i.FailedAt,
+			&i.CancelledAt,
+			&i.ReversedAt,
+			&i.Memo,
+			&i.BatchID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateTransferStatus = `-- name: UpdateTransferStatus :one
+UPDATE transfers
+SET
+  status = $1,
+  failure_reason = $2,
+  posted_at = CASE WHEN $1 = 'posted' THEN now() ELSE posted_at END,
+  failed_at = CASE WHEN $1 = 'failed' THEN now() ELSE failed_at END,
+  cancelled_at = CASE WHEN $1 = 'cancelled' THEN now() ELSE cancelled_at END,
+  reversed_at = CASE WHEN $1 = 'reversed' THEN now() ELSE reversed_at END
+WHERE id = $3 AND status = $4
+RETURNING id, from_account_id, to_account_id, amount, created_at, currency, to_amount, to_currency, fx_rate, fx_spread_bps, fx_quote_id, reversal_of, reversed_amount, status, failure_reason, posted_at, failed_at, cancelled_at, reversed_at, memo, batch_id
+`
+
+type UpdateTransferStatusParams struct {
+	Status        string         `json:"status"`
+	FailureReason sql.NullString `json:"failure_reason"`
+	ID            int64          `json:"id"`
+	FromStatus    string         `json:"from_status"`
+}
+
+func (q *Queries) UpdateTransferStatus(ctx context.Context, arg UpdateTransferStatusParams) (Transfer, error) {
+	row := q.db.QueryRowContext(ctx, updateTransferStatus,
+		arg.Status,
+		arg.FailureReason,
+		arg.ID,
+		arg.FromStatus,
+	)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Currency,
+		&i.ToAmount,
+		&i.ToCurrency,
+		&i.FxRate,
+		&i.FxSpreadBps,
+		&i.FxQuoteID,
+		&i.ReversalOf,
+		&i.ReversedAmount,
+		&i.Status,
+		&i.FailureReason,
+		&i.PostedAt,
+		&i.FailedAt,
+		&i.CancelledAt,
+		&i.ReversedAt,
+		&i.Memo,
+		&i.BatchID,
+	)
+	return i, err
+}
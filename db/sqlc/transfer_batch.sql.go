@@ -0,0 +1,218 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: transfer_batch.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+const addTransferBatchProgress = `-- name: AddTransferBatchProgress :one
+UPDATE transfer_batches
+SET
+  posted_count = posted_count + $1,
+  failed_count = failed_count + $2
+WHERE id = $3
+RETURNING id, from_account_id, mode, status, leg_count, posted_count, failed_count, finished_at, created_at, owner, idempotency_key, request_hash
+`
+
+type AddTransferBatchProgressParams struct {
+	Posted int32 `json:"posted"`
+	Failed int32 `json:"failed"`
+	ID     int64 `json:"id"`
+}
+
+// counts move in the transaction that posts the legs
+func (q *Queries) AddTransferBatchProgress(ctx context.Context, arg AddTransferBatchProgressParams) (TransferBatch, error) {
+	row := q.db.QueryRowContext(ctx, addTransferBatchProgress, arg.Posted, arg.Failed, arg.ID)
+	var i TransferBatch
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.Mode,
+		&i.Status,
+		&i.LegCount,
+		&i.PostedCount,
+		&i.FailedCount,
+		&i.FinishedAt,
+		&i.CreatedAt,
+		&i.Owner,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+	)
+	return i, err
+}
+
+const createTransferBatch = `-- name: CreateTransferBatch :one
+INSERT INTO transfer_batches (
+  from_account_id, owner, mode, leg_count, idempotency_key, request_hash
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (owner, idempotency_key) DO NOTHING
+RETURNING id, from_account_id, mode, status, leg_count, posted_count, failed_count, finished_at, created_at, owner, idempotency_key, request_hash
+`
+
+type CreateTransferBatchParams struct {
+	FromAccountID  int64          `json:"from_account_id"`
+	Owner          string         `json:"owner"`
+	Mode           string         `json:"mode"`
+	LegCount       int32          `json:"leg_count"`
+	IdempotencyKey sql.NullString `json:"idempotency_key"`
+	RequestHash    sql.NullString `json:"request_hash"`
+}
+
+// no row when the owner already used the idempotency key
+func (q *Queries) CreateTransferBatch(ctx context.Context, arg CreateTransferBatchParams) (TransferBatch, error) {
+	row := q.db.QueryRowContext(ctx, createTransferBatch,
+		arg.FromAccountID,
+		arg.Owner,
+		arg.Mode,
+		arg.LegCount,
+		arg.IdempotencyKey,
+		arg.RequestHash,
+	)
+	var i TransferBatch
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.Mode,
+		&i.Status,
+		&i.LegCount,
+		&i.PostedCount,
+		&i.FailedCount,
+		&i.FinishedAt,
+		&i.CreatedAt,
+		&i.Owner,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+	)
+	return i, err
+}
+
+const getTransferBatch = `-- name: GetTransferBatch :one
+SELECT id, from_account_id, mode, status, leg_count, posted_count, failed_count, finished_at, created_at, owner, idempotency_key, request_hash FROM transfer_batches
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetTransferBatch(ctx context.Context, id int64) (TransferBatch, error) {
+	row := q.db.QueryRowContext(ctx, getTransferBatch, id)
+	var i TransferBatch
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.Mode,
+		&i.Status,
+		&i.LegCount,
+		&i.PostedCount,
+		&i.FailedCount,
+		&i.FinishedAt,
+		&i.CreatedAt,
+		&i.Owner,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+	)
+	return i, err
+}
+
+const getTransferBatchByIdempotencyKey = `-- name: GetTransferBatchByIdempotencyKey :one
+SELECT id, from_account_id, mode, status, leg_count, posted_count, failed_count, finished_at, created_at, owner, idempotency_key, request_hash FROM transfer_batches
+WHERE owner = $1 AND idempotency_key = $2 LIMIT 1
+`
+
+type GetTransferBatchByIdempotencyKeyParams struct {
+	Owner          string         `json:"owner"`
+	IdempotencyKey sql.NullString `json:"idempotency_key"`
+}
+
+func (q *Queries) GetTransferBatchByIdempotencyKey(ctx context.Context, arg GetTransferBatchByIdempotencyKeyParams) (TransferBatch, error) {
+	row := q.db.QueryRowContext(ctx, getTransferBatchByIdempotencyKey, arg.Owner, arg.IdempotencyKey)
+	var i TransferBatch
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.Mode,
+		&i.Status,
+		&i.LegCount,
+		&i.PostedCount,
+		&i.FailedCount,
+		&i.FinishedAt,
+		&i.CreatedAt,
+		&i.Owner,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+	)
+	return i, err
+}
+
+const getTransferBatchForUpdate = `-- name: GetTransferBatchForUpdate :one
+SELECT id, from_account_id, mode, status, leg_count, posted_count, failed_count, finished_at, created_at, owner, idempotency_key, request_hash FROM transfer_batches
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferBatchForUpdate(ctx context.Context, id int64) (TransferBatch, error) {
+	row := q.db.QueryRowContext(ctx, getTransferBatchForUpdate, id)
+	var i TransferBatch
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.Mode,
+		&i.Status,
+		&i.LegCount,
+		&i.PostedCount,
+		&i.FailedCount,
+		&i.FinishedAt,
+		&i.CreatedAt,
+		&i.Owner,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+	)
+	return i, err
+}
+
+const updateTransferBatch = `-- name: UpdateTransferBatch :one
+UPDATE transfer_batches
+SET
+  status = $1,
+  posted_count = $2,
+  failed_count = $3,
+  finished_at = CASE WHEN $1 = 'running' THEN NULL ELSE now() END
+WHERE id = $4
+RETURNING id, from_account_id, mode, status, leg_count, posted_count, failed_count, finished_at, created_at, owner, idempotency_key, request_hash
+`
+
+type UpdateTransferBatchParams struct {
+	Status      string `json:"status"`
+	PostedCount int32  `json:"posted_count"`
+	FailedCount int32  `json:"failed_count"`
+	ID          int64  `json:"id"`
+}
+
+func (q *Queries) UpdateTransferBatch(ctx context.Context, arg UpdateTransferBatchParams) (TransferBatch, error) {
+	row := q.db.QueryRowContext(ctx, updateTransferBatch,
+		arg.Status,
+		arg.PostedCount,
+		arg.FailedCount,
+		arg.ID,
+	)
+	var i TransferBatch
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.Mode,
+		&i.Status,
+		&i.LegCount,
+		&i.PostedCount,
+		&i.FailedCount,
+		&i.FinishedAt,
+		&i.CreatedAt,
+		&i.Owner,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+	)
+	return i, err
+}
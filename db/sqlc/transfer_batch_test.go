@@ -0,0 +1,219 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/dxtym/bankrupt/limits"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTransferBatch(t *testing.T, from Account, mode string, legs int) TransferBatch {
+	batch, err := testQueries.CreateTransferBatch(context.Background(), CreateTransferBatchParams{
+		FromAccountID: from.ID,
+		Owner:         from.Owner,
+		Mode:          mode,
+		LegCount:      int32(legs),
+	})
+	require.NoError(t, err)
+	require.Equal(t, BatchRunning, batch.Status)
+	require.False(t, batch.FinishedAt.Valid)
+	return batch
+}
+
+// test an idempotency key creates one batch per owner
+func TestCreateTransferBatchIdempotencyKey(t *testing.T) {
+	from := createRandomAccount(t)
+	arg := CreateTransferBatchParams{
+		FromAccountID:  from.ID,
+		Owner:          from.Owner,
+		Mode:           BatchAtomic,
+		LegCount:       2,
+		IdempotencyKey: sql.NullString{String: utils.RandomString(12), Valid: true},
+		RequestHash:    sql.NullString{String: "hash", Valid: true},
+	}
+	batch1, err := testQueries.CreateTransferBatch(context.Background(), arg)
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateTransferBatch(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	batch2, err := testQueries.GetTransferBatchByIdempotencyKey(context.Background(), GetTransferBatchByIdempotencyKeyParams{
+		Owner:          from.Owner,
+		IdempotencyKey: arg.IdempotencyKey,
+	})
+	require.NoError(t, err)
+	require.Equal(t, batch1.ID, batch2.ID)
+	require.Equal(t, arg.RequestHash, batch2.RequestHash)
+
+	// batches without a key never conflict
+	arg.IdempotencyKey = sql.NullString{}
+	arg.RequestHash = sql.NullString{}
+	_, err = testQueries.CreateTransferBatch(context.Background(), arg)
+	require.NoError(t, err)
+	_, err = testQueries.CreateTransferBatch(context.Background(), arg)
+	require.NoError(t, err)
+}
+
+// test atomic batch posts every leg or none
+func TestTransferBatchTxAtomic(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createFundedAccount(t, utils.NewMoney(1000, utils.USD))
+	to1 := createFundedAccount(t, utils.NewMoney(0, utils.USD))
+	to2 := createFundedAccount(t, utils.NewMoney(0, utils.USD))
+
+	batch := createRandomTransferBatch(t, from, BatchAtomic, 3)
+	arg := TransferBatchTxParams{
+		BatchID:       batch.ID,
+		FromAccountId: from.ID,
+		Atomic:        true,
+		Legs: []TransferBatchLeg{
+			{ToAccountId: to1.ID, Amount: utils.NewMoney(300, utils.USD), Memo: "salary"},
+			{ToAccountId: to2.ID, Amount: utils.NewMoney(200, utils.USD)},
+			{ToAccountId: to1.ID, Amount: utils.NewMoney(100, utils.USD)},
+		},
+	}
+
+	var legs int
+	arg.OnLeg = func(index int, err error) {
+		require.NoError(t, err)
+		legs++
+	}
+	result, err := store.TransferBatchTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, 3, legs)
+	require.Equal(t, int64(400), result.FromAccount.Balance)
+
+	for i, leg := range result.Legs {
+		require.NoError(t, leg.Err)
+		require.Equal(t, arg.Legs[i].ToAccountId, leg.Transfer.ToAccountID)
+		require.Equal(t, sql.NullInt64{Int64: batch.ID, Valid: true}, leg.Transfer.BatchID)
+	}
+	require.Equal(t, "salary", result.Legs[0].Transfer.Memo)
+	require.Equal(t, int32(3), result.Batch.PostedCount)
+
+	// the last leg overdraws, so nothing is posted
+	arg.BatchID = createRandomTransferBatch(t, from, BatchAtomic, 3).ID
+	arg.Legs[2].Amount = utils.NewMoney(1000, utils.USD)
+	arg.OnLeg = nil
+	_, err = store.TransferBatchTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	account, err := testQueries.GetAccount(context.Background(), from.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(400), account.Balance)
+
+	batch, err = testQueries.GetTransferBatch(context.Background(), arg.BatchID)
+	require.NoError(t, err)
+	require.Zero(t, batch.PostedCount)
+	require.Zero(t, batch.FailedCount)
+}
+
+// test chunked batch skips failed legs and posts the rest
+func TestTransferBatchTxChunked(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createFundedAccount(t, utils.NewMoney(1000, utils.USD))
+	to := createFundedAccount(t, utils.NewMoney(0, utils.USD))
+	other := createFundedAccount(t, utils.NewMoney(0, utils.EUR))
+
+	batch := createRandomTransferBatch(t, from, BatchChunked, 4)
+	result, err := store.TransferBatchTx(context.Background(), TransferBatchTxParams{
+		BatchID:       batch.ID,
+		FromAccountId: from.ID,
+		Legs: []TransferBatchLeg{
+			{ToAccountId: to.ID, Amount: utils.NewMoney(100, utils.USD)},
+			{ToAccountId: other.ID, Amount: utils.NewMoney(100, utils.USD)},
+			{ToAccountId: to.ID, Amount: utils.NewMoney(5000, utils.USD)},
+			{ToAccountId: to.ID, Amount: utils.NewMoney(200, utils.USD)},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Legs, 4)
+
+	require.NoError(t, result.Legs[0].Err)
+	require.ErrorIs(t, result.Legs[1].Err, utils.ErrCurrencyMismatch)
+	require.ErrorIs(t, result.Legs[2].Err, ErrInsufficientFunds)
+	require.NoError(t, result.Legs[3].Err)
+	require.Equal(t, int64(700), result.FromAccount.Balance)
+	require.Equal(t, int32(2), result.Batch.PostedCount)
+	require.Equal(t, int32(2), result.Batch.FailedCount)
+
+	batch, err = testQueries.UpdateTransferBatch(context.Background(), UpdateTransferBatchParams{
+		ID:          batch.ID,
+		Status:      BatchPartial,
+		PostedCount: 2,
+		FailedCount: 2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, BatchPartial, batch.Status)
+	require.True(t, batch.FinishedAt.Valid)
+}
+
+// test limits count the legs already posted in the same batch
+func TestTransferBatchTxLimits(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createFundedAccount(t, utils.NewMoney(10000, utils.USD))
+	to := createFundedAccount(t, utils.NewMoney(0, utils.USD))
+
+	_, err := testQueries.SetTransferLimit(context.Background(), SetTransferLimitParams{
+		AccountID: sql.NullInt64{Int64: from.ID, Valid: true},
+		Currency:  sql.NullString{String: utils.USD, Valid: true},
+		MaxDaily:  sql.NullInt64{Int64: 500, Valid: true},
+	})
+	require.NoError(t, err)
+
+	batch := createRandomTransferBatch(t, from, BatchChunked, 3)
+	result, err := store.TransferBatchTx(context.Background(), TransferBatchTxParams{
+		BatchID:       batch.ID,
+		FromAccountId: from.ID,
+		Legs: []TransferBatchLeg{
+			{ToAccountId: to.ID, Amount: utils.NewMoney(300, utils.USD)},
+			{ToAccountId: to.ID, Amount: utils.NewMoney(300, utils.USD)},
+			{ToAccountId: to.ID, Amount: utils.NewMoney(200, utils.USD)},
+		},
+	})
+	require.NoError(t, err)
+
+	// the failed leg doesn't count, so the last one still fits
+	require.NoError(t, result.Legs[0].Err)
+	require.ErrorIs(t, result.Legs[1].Err, limits.ErrExceeded)
+	require.NoError(t, result.Legs[2].Err)
+	require.Equal(t, int64(9500), result.FromAccount.Balance)
+}
+
+// test a chunk only runs after the legs the caller knows were processed
+func TestTransferBatchTxOffset(t *testing.T) {
+	store := NewStore(testDB)
+
+	from := createFundedAccount(t, utils.NewMoney(1000, utils.USD))
+	to := createFundedAccount(t, utils.NewMoney(0, utils.USD))
+
+	batch := createRandomTransferBatch(t, from, BatchChunked, 3)
+	arg := TransferBatchTxParams{
+		BatchID:       batch.ID,
+		FromAccountId: from.ID,
+		Legs: []TransferBatchLeg{
+			{ToAccountId: to.ID, Amount: utils.NewMoney(100, utils.USD)},
+			{ToAccountId: to.ID, Amount: utils.NewMoney(100, utils.USD)},
+		},
+	}
+	result, err := store.TransferBatchTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), result.Batch.PostedCount)
+
+	// a second runner that didn't see the first chunk posts nothing
+	_, err = store.TransferBatchTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrBatchConflict)
+
+	arg.Offset = 2
+	arg.Legs = arg.Legs[:1]
+	result, err = store.TransferBatchTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), result.Batch.PostedCount)
+	require.Equal(t, int64(700), result.FromAccount.Balance)
+}
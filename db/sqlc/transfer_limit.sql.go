@@ -0,0 +1,178 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: transfer_limit.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+const getTransferUsage = `-- name: GetTransferUsage :one
+SELECT
+  COALESCE(SUM(t.amount) FILTER (WHERE t.currency = $1 AND t.created_at > now() - interval '1 day'), 0)::bigint AS daily,
+  COALESCE(SUM(t.amount) FILTER (WHERE t.currency = $1 AND t.created_at > now() - interval '7 days'), 0)::bigint AS weekly,
+  COALESCE(SUM(t.amount) FILTER (WHERE t.currency = $1), 0)::bigint AS monthly,
+  COUNT(*) FILTER (WHERE t.created_at > now() - interval '1 hour') AS hourly_count
+FROM transfers t
+JOIN accounts a ON a.id = t.from_account_id
+WHERE
+    a.owner = $2 AND
+    ($3::bigint IS NULL OR t.from_account_id = $3) AND
+    t.status IN ('pending', 'posted') AND
+    t.reversal_of IS NULL AND
+    ($4::bigint IS NULL OR t.id <> $4) AND
+    t.created_at > now() - interval '30 days'
+`
+
+type GetTransferUsageParams struct {
+	Currency  string        `json:"currency"`
+	Owner     string        `json:"owner"`
+	AccountID sql.NullInt64 `json:"account_id"`
+	ExcludeID sql.NullInt64 `json:"exclude_id"`
+}
+
+type GetTransferUsageRow struct {
+	Daily       int64 `json:"daily"`
+	Weekly      int64 `json:"weekly"`
+	Monthly     int64 `json:"monthly"`
+	HourlyCount int64 `json:"hourly_count"`
+}
+
+// rolling windows ending now, reversals and transfers that never moved money don't count,
+// a pending transfer being posted is left out with exclude_id
+func (q *Queries) GetTransferUsage(ctx context.Context, arg GetTransferUsageParams) (GetTransferUsageRow, error) {
+	row := q.db.QueryRowContext(ctx, getTransferUsage,
+		arg.Currency,
+		arg.Owner,
+		arg.AccountID,
+		arg.ExcludeID,
+	)
+	var i GetTransferUsageRow
+	err := row.Scan(
+		&i.Daily,
+		&i.Weekly,
+		&i.Monthly,
+		&i.HourlyCount,
+	)
+	return i, err
+}
+
+const listTransferLimits = `-- name: ListTransferLimits :many
+SELECT id, tier, account_id, currency, max_per_transfer, max_daily, max_weekly, max_monthly, max_hourly_count, updated_at, created_at FROM transfer_limits
+WHERE
+    (currency IS NULL OR currency = $1::varchar) AND
+    ((tier IS NULL AND account_id IS NULL) OR tier = $2::varchar OR account_id = $3::bigint)
+ORDER BY id
+`
+
+type ListTransferLimitsParams struct {
+	Currency  string `json:"currency"`
+	Tier      string `json:"tier"`
+	AccountID int64  `json:"account_id"`
+}
+
+// global, tier and account limits that apply to a transfer in the currency
+func (q *Queries) ListTransferLimits(ctx context.Context, arg ListTransferLimitsParams) ([]TransferLimit, error) {
+	rows, err := q.db.QueryContext(ctx, listTransferLimits, arg.Currency, arg.Tier, arg.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TransferLimit{}
+	for rows.Next() {
+		var i TransferLimit
+		if err := rows.Scan(
+			&i.ID,
+			&i.Tier,
+			&i.AccountID,
+			&i.Currency,
+			&i.MaxPerTransfer,
+			&i.MaxDaily,
+			&i.MaxWeekly,
+			&i.MaxMonthly,
+			&i.MaxHourlyCount,
+			&i.UpdatedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setTransferLimit = `-- name: SetTransferLimit :one
+INSERT INTO transfer_limits (
+  tier, account_id, currency, max_per_transfer, max_daily, max_weekly, max_monthly, max_hourly_count
+) VALUES (
+  $1, $2, $3, $4,
+  $5, $6, $7, $8
+)
+ON CONFLICT (tier, account_id, currency) DO UPDATE
+SET
+  max_per_transfer = CASE WHEN 'per_transfer' = ANY($9::varchar[]) THEN NULL
+    ELSE COALESCE(EXCLUDED.max_per_transfer, transfer_limits.max_per_transfer) END,
+  max_daily = CASE WHEN 'daily' = ANY($9::varchar[]) THEN NULL
+    ELSE COALESCE(EXCLUDED.max_daily, transfer_limits.max_daily) END,
+  max_weekly = CASE WHEN 'weekly' = ANY($9::varchar[]) THEN NULL
+    ELSE COALESCE(EXCLUDED.max_weekly, transfer_limits.max_weekly) END,
+  max_monthly = CASE WHEN 'monthly' = ANY($9::varchar[]) THEN NULL
+    ELSE COALESCE(EXCLUDED.max_monthly, transfer_limits.max_monthly) END,
+  max_hourly_count = CASE WHEN 'hourly_count' = ANY($9::varchar[]) THEN NULL
+    ELSE COALESCE(EXCLUDED.max_hourly_count, transfer_limits.max_hourly_count) END,
+  updated_at = now()
+RETURNING id, tier, account_id, currency, max_per_transfer, max_daily, max_weekly, max_monthly, max_hourly_count, updated_at, created_at
+`
+
+type SetTransferLimitParams struct {
+	Tier           sql.NullString `json:"tier"`
+	AccountID      sql.NullInt64  `json:"account_id"`
+	Currency       sql.NullString `json:"currency"`
+	MaxPerTransfer sql.NullInt64  `json:"max_per_transfer"`
+	MaxDaily       sql.NullInt64  `json:"max_daily"`
+	MaxWeekly      sql.NullInt64  `json:"max_weekly"`
+	MaxMonthly     sql.NullInt64  `json:"max_monthly"`
+	MaxHourlyCount sql.NullInt64  `json:"max_hourly_count"`
+	Clear          []string       `json:"clear"`
+}
+
+// unset limits keep their value, limits named in clear are removed
+func (q *Queries) SetTransferLimit(ctx context.Context, arg SetTransferLimitParams) (TransferLimit, error) {
+	row := q.db.QueryRowContext(ctx, setTransferLimit,
+		arg.Tier,
+		arg.AccountID,
+		arg.Currency,
+		arg.MaxPerTransfer,
+		arg.MaxDaily,
+		arg.MaxWeekly,
+		arg.MaxMonthly,
+		arg.MaxHourlyCount,
+		pq.Array(arg.Clear),
+	)
+	var i TransferLimit
+	err := row.Scan(
+		&i.ID,
+		&i.Tier,
+		&i.AccountID,
+		&i.Currency,
+		&i.MaxPerTransfer,
+		&i.MaxDaily,
+		&i.MaxWeekly,
+		&i.MaxMonthly,
+		&i.MaxHourlyCount,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,196 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dxtym/bankrupt/limits"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// test set transfer limit updates the limits it names and keeps the rest
+func TestSetTransferLimit(t *testing.T) {
+	tier := utils.RandomString(12)
+	arg := SetTransferLimitParams{
+		Tier:     sql.NullString{String: tier, Valid: true},
+		Currency: sql.NullString{String: utils.USD, Valid: true},
+		MaxDaily: sql.NullInt64{Int64: 1000, Valid: true},
+	}
+	limit1, err := testQueries.SetTransferLimit(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Tier, limit1.Tier)
+	require.Equal(t, arg.MaxDaily, limit1.MaxDaily)
+	require.False(t, limit1.MaxWeekly.Valid)
+
+	arg.MaxDaily = sql.NullInt64{}
+	arg.MaxWeekly = sql.NullInt64{Int64: 5000, Valid: true}
+	limit2, err := testQueries.SetTransferLimit(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, limit1.ID, limit2.ID)
+	require.Equal(t, limit1.MaxDaily, limit2.MaxDaily)
+	require.Equal(t, arg.MaxWeekly, limit2.MaxWeekly)
+
+	// only named limits are removed
+	arg.MaxWeekly = sql.NullInt64{}
+	arg.Clear = []string{limits.Daily}
+	limit2, err = testQueries.SetTransferLimit(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, limit2.MaxDaily.Valid)
+	require.Equal(t, int64(5000), limit2.MaxWeekly.Int64)
+
+	// count limits have no currency
+	_, err = testQueries.SetTransferLimit(context.Background(), SetTransferLimitParams{
+		Tier:           sql.NullString{String: tier, Valid: true},
+		Currency:       sql.NullString{String: utils.USD, Valid: true},
+		MaxHourlyCount: sql.NullInt64{Int64: 5, Valid: true},
+	})
+	require.Error(t, err)
+
+	rows, err := testQueries.ListTransferLimits(context.Background(), ListTransferLimitsParams{
+		Currency: utils.USD,
+		Tier:     tier,
+	})
+	require.NoError(t, err)
+	require.Contains(t, rows, limit2)
+}
+
+// test transfers stop at tier and account limits with the allowance left
+func TestTransferTxLimits(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createFundedAccount(t, utils.NewMoney(10000, utils.USD))
+	account2 := createFundedAccount(t, utils.NewMoney(0, utils.USD))
+
+	tier := utils.RandomString(12)
+	_, err := testQueries.UpdateUserTier(context.Background(), UpdateUserTierParams{
+		Username: account1.Owner,
+		Tier:     tier,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.SetTransferLimit(context.Background(), SetTransferLimitParams{
+		Tier:           sql.NullString{String: tier, Valid: true},
+		Currency:       sql.NullString{String: utils.USD, Valid: true},
+		MaxPerTransfer: sql.NullInt64{Int64: 500, Valid: true},
+		MaxDaily:       sql.NullInt64{Int64: 800, Valid: true},
+	})
+	require.NoError(t, err)
+
+	transfer := func(amount int64) error {
+		_, err := store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountId: account1.ID,
+			ToAccountId:   account2.ID,
+			Amount:        utils.NewMoney(amount, utils.USD),
+		})
+		return err
+	}
+
+	var exceeded *limits.ExceededError
+	err = transfer(600)
+	require.True(t, errors.As(err, &exceeded))
+	require.Equal(t, limits.PerTransfer, exceeded.Limit)
+
+	require.NoError(t, transfer(500))
+
+	err = transfer(400)
+	require.ErrorIs(t, err, limits.ErrExceeded)
+	require.True(t, errors.As(err, &exceeded))
+	require.Equal(t, limits.ScopeUser, exceeded.Scope)
+	require.Equal(t, limits.Daily, exceeded.Limit)
+	require.Equal(t, int64(300), exceeded.Remaining)
+
+	// account limits apply on top of tier limits
+	_, err = testQueries.SetTransferLimit(context.Background(), SetTransferLimitParams{
+		AccountID:      sql.NullInt64{Int64: account1.ID, Valid: true},
+		MaxHourlyCount: sql.NullInt64{Int64: 2, Valid: true},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, transfer(100))
+
+	err = transfer(100)
+	require.True(t, errors.As(err, &exceeded))
+	require.Equal(t, limits.ScopeAccount, exceeded.Scope)
+	require.Equal(t, limits.HourlyCount, exceeded.Limit)
+}
+
+// test capturing a hold counts against the payer's limits
+func TestCaptureHoldTxLimits(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createFundedAccount(t, utils.NewMoney(10000, utils.USD))
+	account2 := createFundedAccount(t, utils.NewMoney(0, utils.USD))
+
+	_, err := testQueries.SetTransferLimit(context.Background(), SetTransferLimitParams{
+		AccountID:      sql.NullInt64{Int64: account1.ID, Valid: true},
+		Currency:       sql.NullString{String: utils.USD, Valid: true},
+		MaxPerTransfer: sql.NullInt64{Int64: 500, Valid: true},
+	})
+	require.NoError(t, err)
+
+	result, err := store.CreateHoldTx(context.Background(), CreateHoldTxParams{
+		AccountID:   account1.ID,
+		ToAccountID: account2.ID,
+		Amount:      utils.NewMoney(600, utils.USD),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	var exceeded *limits.ExceededError
+	_, err = store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: result.Hold.ID})
+	require.True(t, errors.As(err, &exceeded))
+	require.Equal(t, limits.ScopeAccount, exceeded.Scope)
+	require.Equal(t, limits.PerTransfer, exceeded.Limit)
+
+	// rejected capture leaves the hold active and the money in place
+	hold, err := testQueries.GetHold(context.Background(), result.Hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldActive, hold.Status)
+
+	account, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, account.Balance)
+
+	// a capture within the limit goes through
+	capture, err := store.CaptureHoldTx(context.Background(), CaptureHoldTxParams{
+		HoldID: result.Hold.ID,
+		Amount: utils.NewMoney(500, utils.USD),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(500), capture.Transfer.Amount)
+}
+
+// test posting a pending transfer checks limits without counting the transfer twice
+func TestPostTransferTxLimits(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createFundedAccount(t, utils.NewMoney(10000, utils.USD))
+	account2 := createFundedAccount(t, utils.NewMoney(0, utils.USD))
+
+	_, err := testQueries.SetTransferLimit(context.Background(), SetTransferLimitParams{
+		AccountID: sql.NullInt64{Int64: account1.ID, Valid: true},
+		Currency:  sql.NullString{String: utils.USD, Valid: true},
+		MaxDaily:  sql.NullInt64{Int64: 500, Valid: true},
+	})
+	require.NoError(t, err)
+
+	// fits on its own, so it posts
+	transfer := createRandomPendingTransfer(t, account1, account2, utils.NewMoney(500, utils.USD))
+	_, err = store.PostTransferTx(context.Background(), transfer.ID)
+	require.NoError(t, err)
+
+	var exceeded *limits.ExceededError
+	transfer = createRandomPendingTransfer(t, account1, account2, utils.NewMoney(100, utils.USD))
+	_, err = store.PostTransferTx(context.Background(), transfer.ID)
+	require.True(t, errors.As(err, &exceeded))
+	require.Equal(t, limits.Daily, exceeded.Limit)
+	require.Zero(t, exceeded.Remaining)
+
+	transfer, err = testQueries.GetTransfer(context.Background(), transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, TransferPending, transfer.Status)
+}
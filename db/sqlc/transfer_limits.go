@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dxtym/bankrupt/limits"
+	"github.com/dxtym/bankrupt/utils"
+)
+
+func (limit TransferLimit) limits() limits.Limits {
+	return limits.Limits{
+		PerTransfer: limit.MaxPerTransfer,
+		Daily:       limit.MaxDaily,
+		Weekly:      limit.MaxWeekly,
+		Monthly:     limit.MaxMonthly,
+		HourlyCount: limit.MaxHourlyCount,
+	}
+}
+
+func (usage GetTransferUsageRow) usage() limits.Usage {
+	return limits.Usage{
+		Daily:       usage.Daily,
+		Weekly:      usage.Weekly,
+		Monthly:     usage.Monthly,
+		HourlyCount: usage.HourlyCount,
+	}
+}
+
+// limits of one account and currency with what was already sent against them
+type transferLimits struct {
+	user      limits.Limits
+	own       limits.Limits
+	userUsage limits.Usage
+	ownUsage  limits.Usage
+}
+
+// check the transfer against user and account limits, accounts must be locked,
+// pendingID is a pending transfer being posted, it is already in the usage and must not count twice
+func checkTransferLimits(ctx context.Context, q *Queries, accountID int64, amount utils.Money, pendingID int64) error {
+	account, err := q.GetAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	transferLimits, err := loadTransferLimits(ctx, q, account, amount.Currency, pendingID)
+	if err != nil {
+		return err
+	}
+	return transferLimits.check(amount)
+}
+
+// load limits and usage once, the owner stays locked so usage can be tracked in memory until commit
+func loadTransferLimits(ctx context.Context, q *Queries, account Account, currency string, pendingID int64) (result transferLimits, err error) {
+	// the owner lock serializes transfers from all of their accounts, so totals can't race
+	user, err := q.GetUserForUpdate(ctx, account.Owner)
+	if err != nil {
+		return
+	}
+
+	rows, err := q.ListTransferLimits(ctx, ListTransferLimitsParams{
+		Currency:  currency,
+		Tier:      user.Tier,
+		AccountID: account.ID,
+	})
+	if err != nil {
+		return
+	}
+
+	// tier limits override global ones, account limits are counted separately
+	var global, tier limits.Limits
+	for _, row := range rows {
+		switch {
+		case row.AccountID.Valid:
+			result.own = result.own.Override(row.limits())
+		case row.Tier.Valid:
+			tier = tier.Override(row.limits())
+		default:
+			global = global.Override(row.limits())
+		}
+	}
+	result.user = global.Override(tier)
+
+	if result.user.IsSet() {
+		usage, err := q.GetTransferUsage(ctx, GetTransferUsageParams{
+			Owner:     account.Owner,
+			Currency:  currency,
+			ExcludeID: sql.NullInt64{Int64: pendingID, Valid: pendingID != 0},
+		})
+		if err != nil {
+			return result, err
+		}
+		result.userUsage = usage.usage()
+	}
+
+	if result.own.IsSet() {
+		usage, err := q.GetTransferUsage(ctx, GetTransferUsageParams{
+			Owner:     account.Owner,
+			AccountID: sql.NullInt64{Int64: account.ID, Valid: true},
+			Currency:  currency,
+			ExcludeID: sql.NullInt64{Int64: pendingID, Valid: pendingID != 0},
+		})
+		if err != nil {
+			return result, err
+		}
+		result.ownUsage = usage.usage()
+	}
+	return
+}
+
+func (l transferLimits) check(amount utils.Money) error {
+	if l.user.IsSet() {
+		if err := limits.Check(limits.ScopeUser, l.user, l.userUsage, amount); err != nil {
+			return err
+		}
+	}
+	if l.own.IsSet() {
+		return limits.Check(limits.ScopeAccount, l.own, l.ownUsage, amount)
+	}
+	return nil
+}
+
+// count a posted transfer towards every window
+func (l *transferLimits) add(amount utils.Money) {
+	l.userUsage = l.userUsage.Add(amount.Amount)
+	l.ownUsage = l.ownUsage.Add(amount.Amount)
+}
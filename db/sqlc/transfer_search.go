@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type SearchTransfersParams struct {
+	Owner          string         `json:"owner"`
+	AccountID      sql.NullInt64  `json:"account_id"`
+	Outgoing       bool           `json:"outgoing"`
+	Incoming       bool           `json:"incoming"`
+	CounterpartyID sql.NullInt64  `json:"counterparty_id"`
+	Currency       sql.NullString `json:"currency"`
+	MinAmount      sql.NullInt64  `json:"min_amount"`
+	MaxAmount      sql.NullInt64  `json:"max_amount"`
+	FromTime       sql.NullTime   `json:"from_time"`
+	ToTime         sql.NullTime   `json:"to_time"`
+	Status         sql.NullString `json:"status"`
+	Query          sql.NullString `json:"query"`
+	SortBy         string         `json:"sort_by"`
+	Descending     bool           `json:"descending"`
+	AfterCreatedAt time.Time      `json:"after_created_at"`
+	AfterAmount    int64          `json:"after_amount"`
+	AfterID        sql.NullInt64  `json:"after_id"`
+	LimitCount     int32          `json:"limit_count"`
+}
+
+// search transfers touching accounts of the owner, each sort order has its own query so it can use its index
+func (q *Queries) SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]Transfer, error) {
+	if arg.SortBy == "amount" {
+		byAmount := SearchTransfersByAmountParams{
+			LimitCount:     arg.LimitCount,
+			Owner:          arg.Owner,
+			AccountID:      arg.AccountID,
+			Outgoing:       arg.Outgoing,
+			CounterpartyID: arg.CounterpartyID,
+			Currency:       arg.Currency,
+			MinAmount:      arg.MinAmount,
+			MaxAmount:      arg.MaxAmount,
+			FromTime:       arg.FromTime,
+			ToTime:         arg.ToTime,
+			Status:         arg.Status,
+			Query:          arg.Query,
+			AfterID:        arg.AfterID,
+			AfterAmount:    arg.AfterAmount,
+			Incoming:       arg.Incoming,
+		}
+		if arg.Descending {
+			return q.SearchTransfersByAmountDesc(ctx, SearchTransfersByAmountDescParams(byAmount))
+		}
+		return q.SearchTransfersByAmount(ctx, byAmount)
+	}
+
+	byCreatedAt := SearchTransfersByCreatedAtParams{
+		LimitCount:     arg.LimitCount,
+		Owner:          arg.Owner,
+		AccountID:      arg.AccountID,
+		Outgoing:       arg.Outgoing,
+		CounterpartyID: arg.CounterpartyID,
+		Currency:       arg.Currency,
+		MinAmount:      arg.MinAmount,
+		MaxAmount:      arg.MaxAmount,
+		FromTime:       arg.FromTime,
+		ToTime:         arg.ToTime,
+		Status:         arg.Status,
+		Query:          arg.Query,
+		AfterID:        arg.AfterID,
+		AfterCreatedAt: arg.AfterCreatedAt,
+		Incoming:       arg.Incoming,
+	}
+	if arg.Descending {
+		return q.SearchTransfersByCreatedAtDesc(ctx, SearchTransfersByCreatedAtDescParams(byCreatedAt))
+	}
+	return q.SearchTransfersByCreatedAt(ctx, byCreatedAt)
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	TransferPending   = "pending"   // created, no money moved yet
+	TransferPosted    = "posted"    // entries recorded, balances updated
+	TransferFailed    = "failed"    // never posted, see failure reason
+	TransferReversed  = "reversed"  // posted, then fully given back
+	TransferCancelled = "cancelled" // withdrawn before posting
+)
+
+var ErrInvalidTransferTransition = errors.New("invalid transfer status transition")
+
+// allowed moves between transfer statuses, the rest are terminal
+var transferTransitions = map[string][]string{
+	TransferPending: {TransferPosted, TransferFailed, TransferCancelled},
+	TransferPosted:  {TransferReversed},
+}
+
+func IsTransferStatus(status string) bool {
+	switch status {
+	case TransferPending, TransferPosted, TransferFailed, TransferReversed, TransferCancelled:
+		return true
+	}
+	return false
+}
+
+func CanTransitionTransfer(from, to string) bool {
+	for _, status := range transferTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+func checkTransferTransition(transfer Transfer, to string) error {
+	if !CanTransitionTransfer(transfer.Status, to) {
+		return fmt.Errorf("transfer [%d] %s -> %s: %w", transfer.ID, transfer.Status, to, ErrInvalidTransferTransition)
+	}
+	return nil
+}
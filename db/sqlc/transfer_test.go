@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -13,7 +14,9 @@ func createRandomTransfer(t *testing.T, account1 Account, account2 Account) Tran
 	arg := CreateTransferParams{
 		FromAccountID: account1.ID,
 		ToAccountID:   account2.ID,
-		Amount:        utils.RandomMoney(),
+		Amount:        utils.RandomMoney().Amount,
+		Currency:      account1.Currency,
+		Memo:          utils.RandomString(12),
 	}
 	transfer, err := testQueries.CreateTransfer(context.Background(), arg)
 	require.NoError(t, err)
@@ -22,6 +25,8 @@ func createRandomTransfer(t *testing.T, account1 Account, account2 Account) Tran
 	require.Equal(t, arg.FromAccountID, transfer.FromAccountID)
 	require.Equal(t, arg.ToAccountID, transfer.ToAccountID)
 	require.Equal(t, arg.Amount, transfer.Amount)
+	require.Equal(t, arg.Currency, transfer.Currency)
+	require.Equal(t, arg.Memo, transfer.Memo)
 
 	require.NotZero(t, transfer.ID)
 	require.NotZero(t, transfer.CreatedAt)
@@ -29,6 +34,20 @@ func createRandomTransfer(t *testing.T, account1 Account, account2 Account) Tran
 	return transfer
 }
 
+func createRandomPendingTransfer(t *testing.T, account1 Account, account2 Account, amount utils.Money) Transfer {
+	transfer, err := testQueries.CreatePendingTransfer(context.Background(), CreatePendingTransferParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount.Amount,
+		Currency:      amount.Currency,
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferPending, transfer.Status)
+	require.False(t, transfer.PostedAt.Valid)
+
+	return transfer
+}
+
 // test create transfer
 func TestCreateTransfer(t *testing.T) {
 	account1 := createRandomAccount(t)
@@ -78,3 +97,200 @@ func TestListTransfer(t *testing.T) {
 		require.True(t, transfer.FromAccountID == account1.ID || transfer.ToAccountID == account1.ID)
 	}
 }
+
+// test list transfers by cursor
+func TestListTransferPage(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	for i := 0; i < 3; i++ {
+		createRandomTransfer(t, account1, account2)
+		createRandomTransfer(t, account2, account1)
+	}
+	arg := ListTransfersPageParams{
+		AccountID:  account1.ID,
+		LimitCount: 4,
+	}
+
+	page1, err := testQueries.ListTransfersPage(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page1, 4)
+
+	last := page1[len(page1)-1]
+	arg.AfterCreatedAt = last.CreatedAt
+	arg.AfterID = last.ID
+	page2, err := testQueries.ListTransfersPage(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page2, 2)
+
+	seen := make(map[int64]bool)
+	for _, transfer := range append(page1, page2...) {
+		require.False(t, seen[transfer.ID])
+		seen[transfer.ID] = true
+		require.True(t, transfer.FromAccountID == account1.ID || transfer.ToAccountID == account1.ID)
+	}
+}
+
+// test list transfers by status
+func TestListTransferByStatus(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	createRandomTransfer(t, account1, account2)
+	pending := createRandomPendingTransfer(t, account1, account2, utils.NewMoney(10, account1.Currency))
+
+	arg := ListTransfersParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account1.ID,
+		Limit:         5,
+		Offset:        0,
+		Status:        sql.NullString{String: TransferPending, Valid: true},
+	}
+
+	transfers, err := testQueries.ListTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, pending.ID, transfers[0].ID)
+
+	arg.Status = sql.NullString{}
+	transfers, err = testQueries.ListTransfers(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, transfers, 2)
+}
+
+// test search transfers by filters, memo and sort order
+func TestSearchTransfers(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	account3 := createRandomAccount(t)
+
+	create := func(from, to Account, amount int64, memo string) Transfer {
+		transfer, err := testQueries.CreateTransfer(context.Background(), CreateTransferParams{
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        amount,
+			Currency:      from.Currency,
+			Memo:          memo,
+		})
+		require.NoError(t, err)
+		return transfer
+	}
+	rent := create(account1, account2, 300, "rent for march")
+	small := create(account2, account1, 100, "coffee")
+	large := create(account1, account3, 500, "march rent deposit")
+	create(account2, account3, 700, "rent")
+
+	search := func(arg SearchTransfersParams) []int64 {
+		arg.Owner = account1.Owner
+		arg.LimitCount = 10
+		if arg.SortBy == "" {
+			arg.SortBy = "created_at"
+		}
+		transfers, err := testQueries.SearchTransfers(context.Background(), arg)
+		require.NoError(t, err)
+
+		ids := make([]int64, len(transfers))
+		for i, transfer := range transfers {
+			ids[i] = transfer.ID
+		}
+		return ids
+	}
+
+	// transfers between other accounts never show up
+	require.Equal(t, []int64{rent.ID, small.ID, large.ID}, search(SearchTransfersParams{Outgoing: true, Incoming: true}))
+	require.Equal(t, []int64{small.ID}, search(SearchTransfersParams{Incoming: true}))
+	require.Equal(t, []int64{large.ID}, search(SearchTransfersParams{
+		Outgoing:       true,
+		Incoming:       true,
+		CounterpartyID: sql.NullInt64{Int64: account3.ID, Valid: true},
+	}))
+	require.Equal(t, []int64{rent.ID, large.ID}, search(SearchTransfersParams{
+		Outgoing: true,
+		Incoming: true,
+		Query:    sql.NullString{String: "rent march", Valid: true},
+	}))
+	require.Equal(t, []int64{rent.ID}, search(SearchTransfersParams{
+		Outgoing:  true,
+		Incoming:  true,
+		MinAmount: sql.NullInt64{Int64: 200, Valid: true},
+		MaxAmount: sql.NullInt64{Int64: 400, Valid: true},
+	}))
+	require.Equal(t, []int64{large.ID, rent.ID, small.ID}, search(SearchTransfersParams{
+		Outgoing:   true,
+		Incoming:   true,
+		SortBy:     "amount",
+		Descending: true,
+	}))
+	require.Equal(t, []int64{small.ID}, search(SearchTransfersParams{
+		Outgoing:    true,
+		Incoming:    true,
+		SortBy:      "amount",
+		Descending:  true,
+		AfterAmount: rent.Amount,
+		AfterID:     sql.NullInt64{Int64: rent.ID, Valid: true},
+	}))
+	require.Empty(t, search(SearchTransfersParams{
+		Outgoing:  true,
+		Incoming:  true,
+		AccountID: sql.NullInt64{Int64: account2.ID, Valid: true},
+	}))
+
+	// a transfer between two own accounts is listed once
+	currency := utils.USD
+	if account1.Currency == currency {
+		currency = utils.EUR
+	}
+	savings, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    account1.Owner,
+		Currency: currency,
+	})
+	require.NoError(t, err)
+	internal := create(account1, savings, 50, "savings")
+	require.Equal(t, []int64{internal.ID, small.ID, rent.ID, large.ID}, search(SearchTransfersParams{
+		Outgoing: true,
+		Incoming: true,
+		SortBy:   "amount",
+	}))
+}
+
+// test incoming fx transfers match the currency they were credited in
+func TestSearchTransfersFX(t *testing.T) {
+	store := NewStore(testDB)
+
+	amount := utils.NewMoney(1000, utils.USD)
+	converted := utils.NewMoney(915, utils.EUR)
+	account1 := createFundedAccount(t, amount)
+	account2 := createFundedAccount(t, utils.NewMoney(0, utils.EUR))
+	quote := createRandomFXQuote(t, account1.Owner, amount, converted)
+
+	result, err := store.FXTransferTx(context.Background(), FXTransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        amount,
+		QuoteID:       quote.ID,
+		Username:      account1.Owner,
+	})
+	require.NoError(t, err)
+
+	search := func(owner string, currency string) []Transfer {
+		transfers, err := testQueries.SearchTransfers(context.Background(), SearchTransfersParams{
+			Owner:      owner,
+			Outgoing:   true,
+			Incoming:   true,
+			Currency:   sql.NullString{String: currency, Valid: true},
+			SortBy:     "created_at",
+			LimitCount: 10,
+		})
+		require.NoError(t, err)
+		return transfers
+	}
+
+	// the payer sent dollars, the payee received euros
+	require.Len(t, search(account1.Owner, utils.USD), 1)
+	require.Empty(t, search(account1.Owner, utils.EUR))
+
+	received := search(account2.Owner, utils.EUR)
+	require.Len(t, received, 1)
+	require.Equal(t, result.Transfer.ID, received[0].ID)
+	require.Empty(t, search(account2.Owner, utils.USD))
+}
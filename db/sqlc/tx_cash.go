@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dxtym/bankrupt/utils"
+)
+
+// owner of per-currency cash accounts that balance deposits and withdrawals
+const SystemCashOwner = "system_cash"
+
+var ErrSystemAccount = errors.New("operation is not allowed on system account")
+
+type CashTxParams struct {
+	AccountID   int64              `json:"account_id"`
+	Amount      utils.Money        `json:"amount"`
+	Idempotency *IdempotencyParams `json:"-"` // optional, replays the first result for retried requests
+}
+
+// system accounts are house accounts, not owned by customers
+func (account Account) IsSystem() bool {
+	return account.Owner == SystemCashOwner || account.Owner == SystemFXOwner
+}
+
+// move money from cash account into customer account
+func (store *SqlStore) DepositTx(ctx context.Context, arg CashTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	err := store.execIdempotentTx(ctx, arg.Idempotency, &result, func(q *Queries) error {
+		account, cash, err := getCashAccounts(ctx, q, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		result, err = transferMoney(ctx, q, TransferTxParams{
+			FromAccountId: cash.ID,
+			ToAccountId:   account.ID,
+			Amount:        arg.Amount,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// move money from customer account back into cash account
+func (store *SqlStore) WithdrawTx(ctx context.Context, arg CashTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+	err := store.execIdempotentTx(ctx, arg.Idempotency, &result, func(q *Queries) error {
+		account, cash, err := getCashAccounts(ctx, q, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		result, err = transferMoney(ctx, q, TransferTxParams{
+			FromAccountId: account.ID,
+			ToAccountId:   cash.ID,
+			Amount:        arg.Amount,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// get customer account and cash account of the same currency
+func getCashAccounts(ctx context.Context, q *Queries, accountID int64) (account Account, cash Account, err error) {
+	account, err = q.GetAccount(ctx, accountID)
+	if err != nil {
+		return
+	}
+
+	if account.IsSystem() {
+		err = ErrSystemAccount
+		return
+	}
+
+	// cash account is opened on first use of the currency
+	cash, err = q.CreateSystemAccount(ctx, CreateSystemAccountParams{
+		Owner:    SystemCashOwner,
+		Currency: account.Currency,
+	})
+	return
+}
@@ -0,0 +1,26 @@
+package db
+
+import (
+	"context"
+)
+
+type CreateAccountTxParams struct {
+	CreateAccountParams
+	Idempotency *IdempotencyParams // optional, replays the first result for retried requests
+}
+
+type CreateAccountTxResult struct {
+	Account Account `json:"account"`
+}
+
+func (store *SqlStore) CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (CreateAccountTxResult, error) {
+	var txResult CreateAccountTxResult
+	err := store.execIdempotentTx(ctx, arg.Idempotency, &txResult, func(q *Queries) error {
+		var err error
+
+		txResult.Account, err = q.CreateAccount(ctx, arg.CreateAccountParams)
+		return err
+	})
+
+	return txResult, err
+}
@@ -6,7 +6,7 @@ import (
 
 type CreateUserTxParams struct {
 	CreateUserParams
-	AfterCreate func(user User) error // callback function to run after creating the user
+	Outbox []OutboxTask // published after the user is committed
 }
 
 type CreateUserTxResult struct {
@@ -23,7 +23,7 @@ func (store *SqlStore) CreateUserTx(ctx context.Context, arg CreateUserTxParams)
 			return err
 		}
 
-		return arg.AfterCreate(txResult.User)
+		return enqueueOutbox(ctx, q, arg.Outbox...)
 	})
 
 	return txResult, err
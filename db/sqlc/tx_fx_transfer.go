@@ -0,0 +1,182 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/google/uuid"
+)
+
+// owner of per-currency fx house accounts that balance conversions
+const SystemFXOwner = "system_fx"
+
+var (
+	ErrFXQuoteNotFound = errors.New("fx quote not found")
+	ErrFXQuoteExpired  = errors.New("fx quote expired")
+	ErrFXQuoteUsed     = errors.New("fx quote already used")
+	ErrFXQuoteMismatch = errors.New("fx quote doesn't match transfer")
+)
+
+type FXTransferTxParams struct {
+	FromAccountId int64              `json:"from_account_id"`
+	ToAccountId   int64              `json:"to_account_id"`
+	Amount        utils.Money        `json:"amount"` // in from account currency, must match quote
+	QuoteID       uuid.UUID          `json:"quote_id"`
+	Username      string             `json:"username"` // quote owner
+	Memo          string             `json:"memo"`
+	Idempotency   *IdempotencyParams `json:"-"` // optional, replays the first result for retried requests
+}
+
+type FXTransferTxResult struct {
+	TransferTxResult
+	FXFromEntry Entry `json:"fx_from_entry"` // fx house account receives from currency
+	FXToEntry   Entry `json:"fx_to_entry"`   // fx house account pays out to currency
+}
+
+// convert money at quoted rate through fx house accounts
+func (store *SqlStore) FXTransferTx(ctx context.Context, arg FXTransferTxParams) (FXTransferTxResult, error) {
+	var result FXTransferTxResult
+	err := store.execIdempotentTx(ctx, arg.Idempotency, &result, func(q *Queries) error {
+		var err error
+		result, err = exchangeMoney(ctx, q, arg)
+		return checkFundsError(err)
+	})
+
+	return result, err
+}
+
+func exchangeMoney(ctx context.Context, q *Queries, arg FXTransferTxParams) (result FXTransferTxResult, err error) {
+	quote, err := useFXQuote(ctx, q, arg)
+	if err != nil {
+		return
+	}
+
+	// system accounts are always locked before customer accounts
+	houseFrom, houseTo, err := getFXHouseAccounts(ctx, q, quote.FromCurrency, quote.ToCurrency)
+	if err != nil {
+		return
+	}
+
+	var fromAccount, toAccount Account
+	if arg.FromAccountId < arg.ToAccountId {
+		fromAccount, toAccount, err = lockAccounts(ctx, q, arg.FromAccountId, arg.ToAccountId)
+	} else {
+		toAccount, fromAccount, err = lockAccounts(ctx, q, arg.ToAccountId, arg.FromAccountId)
+	}
+	if err != nil {
+		return
+	}
+
+	if fromAccount.Currency != quote.FromCurrency || toAccount.Currency != quote.ToCurrency {
+		err = fmt.Errorf("accounts [%d] -> [%d]: %w", fromAccount.ID, toAccount.ID, utils.ErrCurrencyMismatch)
+		return
+	}
+
+	if err = checkAvailable(ctx, q, fromAccount, quote.FromAmount); err != nil {
+		return
+	}
+
+	if err = checkTransferLimits(ctx, q, fromAccount.ID, utils.NewMoney(quote.FromAmount, quote.FromCurrency), 0); err != nil {
+		return
+	}
+
+	result.Transfer, err = q.CreateFXTransfer(ctx, CreateFXTransferParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        quote.FromAmount,
+		Currency:      quote.FromCurrency,
+		ToAmount:      sql.NullInt64{Int64: quote.ToAmount, Valid: true},
+		ToCurrency:    sql.NullString{String: quote.ToCurrency, Valid: true},
+		FxRate:        sql.NullString{String: quote.Rate, Valid: true},
+		FxSpreadBps:   sql.NullInt32{Int32: quote.SpreadBps, Valid: true},
+		FxQuoteID:     uuid.NullUUID{UUID: quote.ID, Valid: true},
+		Memo:          arg.Memo,
+	})
+	if err != nil {
+		return
+	}
+
+	// four entries keep each currency balanced
+	postings := []struct {
+		entry   *Entry
+		account *Account
+		amount  int64
+	}{
+		{&result.FromEntry, &result.FromAccount, -quote.FromAmount},
+		{&result.FXFromEntry, &houseFrom, quote.FromAmount},
+		{&result.FXToEntry, &houseTo, -quote.ToAmount},
+		{&result.ToEntry, &result.ToAccount, quote.ToAmount},
+	}
+	result.FromAccount, result.ToAccount = fromAccount, toAccount
+
+	// accounts are already locked, so update order doesn't matter
+	for _, posting := range postings {
+		*posting.account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:      posting.account.ID,
+			Balance: posting.amount,
+		})
+		if err != nil {
+			return
+		}
+
+		*posting.entry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID:    posting.account.ID,
+			Amount:       posting.amount,
+			TransferID:   sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+			BalanceAfter: posting.account.Balance,
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// lock quote and mark it used so the rate can't be applied twice
+func useFXQuote(ctx context.Context, q *Queries, arg FXTransferTxParams) (FxQuote, error) {
+	quote, err := q.GetFXQuoteForUpdate(ctx, arg.QuoteID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return quote, ErrFXQuoteNotFound
+		}
+		return quote, err
+	}
+
+	switch {
+	case quote.Username != arg.Username:
+		return quote, ErrFXQuoteNotFound
+	case quote.UsedAt.Valid:
+		return quote, ErrFXQuoteUsed
+	case time.Now().After(quote.ExpiresAt):
+		return quote, ErrFXQuoteExpired
+	case quote.FromCurrency != arg.Amount.Currency || quote.FromAmount != arg.Amount.Amount:
+		return quote, ErrFXQuoteMismatch
+	}
+
+	return q.MarkFXQuoteUsed(ctx, quote.ID)
+}
+
+// get house accounts in currency order so concurrent conversions don't deadlock
+func getFXHouseAccounts(ctx context.Context, q *Queries, fromCurrency, toCurrency string) (houseFrom Account, houseTo Account, err error) {
+	currencies := []string{fromCurrency, toCurrency}
+	sort.Strings(currencies)
+
+	houses := make(map[string]Account, len(currencies))
+	for _, currency := range currencies {
+		houses[currency], err = q.CreateSystemAccount(ctx, CreateSystemAccountParams{
+			Owner:    SystemFXOwner,
+			Currency: currency,
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	return houses[fromCurrency], houses[toCurrency], nil
+}
@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dxtym/bankrupt/utils"
+)
+
+const (
+	HoldActive   = "active"   // funds are reserved
+	HoldCaptured = "captured" // settled by a transfer, remainder released
+	HoldVoided   = "voided"   // released before capture
+	HoldExpired  = "expired"  // released by the expiry task
+)
+
+var (
+	ErrHoldNotActive      = errors.New("hold is no longer active")
+	ErrHoldExpired        = errors.New("hold expired")
+	ErrHoldAmountExceeded = errors.New("capture amount exceeds hold amount")
+)
+
+type CreateHoldTxParams struct {
+	AccountID   int64              `json:"account_id"`
+	ToAccountID int64              `json:"to_account_id"`
+	Amount      utils.Money        `json:"amount"`
+	ExpiresAt   time.Time          `json:"expires_at"`
+	Idempotency *IdempotencyParams `json:"-"` // optional, replays the first result for retried requests
+}
+
+type CreateHoldTxResult struct {
+	Hold    Hold    `json:"hold"`
+	Account Account `json:"account"`
+}
+
+type CaptureHoldTxParams struct {
+	HoldID      int64              `json:"hold_id"`
+	Amount      utils.Money        `json:"amount"` // zero captures the full hold
+	Idempotency *IdempotencyParams `json:"-"`      // optional, replays the first result for retried requests
+}
+
+type CaptureHoldTxResult struct {
+	TransferTxResult
+	Hold Hold `json:"hold"`
+}
+
+// reserve funds in account without moving them
+func (store *SqlStore) CreateHoldTx(ctx context.Context, arg CreateHoldTxParams) (CreateHoldTxResult, error) {
+	var result CreateHoldTxResult
+	err := store.execIdempotentTx(ctx, arg.Idempotency, &result, func(q *Queries) error {
+		account, err := q.GetAccountUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		toAccount, err := q.GetAccount(ctx, arg.ToAccountID)
+		if err != nil {
+			return err
+		}
+
+		if account.IsSystem() || toAccount.IsSystem() {
+			return ErrSystemAccount
+		}
+
+		for _, account := range []Account{account, toAccount} {
+			if account.Currency != arg.Amount.Currency {
+				return fmt.Errorf("account [%d]: %w", account.ID, utils.ErrCurrencyMismatch)
+			}
+		}
+
+		if err := checkAvailable(ctx, q, account, arg.Amount.Amount); err != nil {
+			return err
+		}
+
+		result.Account = account
+		result.Hold, err = q.CreateHold(ctx, CreateHoldParams{
+			AccountID:   arg.AccountID,
+			ToAccountID: arg.ToAccountID,
+			Amount:      arg.Amount.Amount,
+			Currency:    arg.Amount.Currency,
+			ExpiresAt:   arg.ExpiresAt,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// settle hold with a transfer to the payee, releasing any remainder
+func (store *SqlStore) CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error) {
+	var result CaptureHoldTxResult
+	err := store.execIdempotentTx(ctx, arg.Idempotency, &result, func(q *Queries) error {
+		hold, err := getActiveHold(ctx, q, arg.HoldID)
+		if err != nil {
+			return err
+		}
+
+		amount := arg.Amount
+		if amount.IsZero() {
+			amount = utils.NewMoney(hold.Amount, hold.Currency)
+		}
+		if amount.Currency != hold.Currency {
+			return fmt.Errorf("hold [%d]: %w", hold.ID, utils.ErrCurrencyMismatch)
+		}
+		if amount.Amount > hold.Amount {
+			return fmt.Errorf("hold [%d]: %w", hold.ID, ErrHoldAmountExceeded)
+		}
+
+		// release the reservation first so the transfer can spend it
+		_, err = q.UpdateHoldStatus(ctx, UpdateHoldStatusParams{
+			ID:             hold.ID,
+			Status:         HoldCaptured,
+			CapturedAmount: sql.NullInt64{Int64: amount.Amount, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		// limits apply when the money moves, a hold alone doesn't count as usage
+		result.TransferTxResult, err = transferMoney(ctx, q, TransferTxParams{
+			FromAccountId: hold.AccountID,
+			ToAccountId:   hold.ToAccountID,
+			Amount:        amount,
+			checkLimits:   true,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.UpdateHoldStatus(ctx, UpdateHoldStatusParams{
+			ID:         hold.ID,
+			Status:     HoldCaptured,
+			TransferID: sql.NullInt64{Int64: result.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// release hold without moving money
+func (store *SqlStore) VoidHoldTx(ctx context.Context, holdID int64) (Hold, error) {
+	var result Hold
+	err := store.execTx(ctx, func(q *Queries) error {
+		hold, err := getActiveHold(ctx, q, holdID)
+		if err != nil {
+			return err
+		}
+
+		result, err = q.UpdateHoldStatus(ctx, UpdateHoldStatusParams{
+			ID:     hold.ID,
+			Status: HoldVoided,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// lock hold and check it still reserves funds
+func getActiveHold(ctx context.Context, q *Queries, holdID int64) (Hold, error) {
+	hold, err := q.GetHoldForUpdate(ctx, holdID)
+	if err != nil {
+		return hold, err
+	}
+
+	switch {
+	case hold.Status != HoldActive:
+		return hold, fmt.Errorf("hold [%d] is %s: %w", hold.ID, hold.Status, ErrHoldNotActive)
+	case !time.Now().Before(hold.ExpiresAt):
+		return hold, fmt.Errorf("hold [%d]: %w", hold.ID, ErrHoldExpired)
+	}
+	return hold, nil
+}
+
+// check funds not reserved by active holds cover the debit, account must be locked
+func checkAvailable(ctx context.Context, q *Queries, account Account, amount int64) error {
+	if account.OverdraftPolicy() == OverdraftUnlimited {
+		return nil
+	}
+
+	held, err := q.GetHeldAmount(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+
+	if !account.CanDebit(amount + held) {
+		return fmt.Errorf("account [%d]: %w", account.ID, ErrInsufficientFunds)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrFXRateConflict = errors.New("a different rate is already recorded for that time")
+
+// append rates to history all or nothing, a rate equal to the one already
+// in effect at its time adds nothing and is skipped
+func (store *SqlStore) ImportFXRatesTx(ctx context.Context, rates []CreateFXRateParams) (int, error) {
+	var imported int
+	err := store.execTx(ctx, func(q *Queries) error {
+		for _, arg := range rates {
+			current, err := q.GetFXRateAt(ctx, GetFXRateAtParams{
+				FromCurrency: arg.FromCurrency,
+				ToCurrency:   arg.ToCurrency,
+				At:           arg.EffectiveFrom,
+			})
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+			case err != nil:
+				return err
+			case current.Rate == arg.Rate && current.SpreadBps == arg.SpreadBps:
+				continue
+			case current.EffectiveFrom.Equal(arg.EffectiveFrom):
+				return fmt.Errorf("rate %s/%s at %s: %w", arg.FromCurrency, arg.ToCurrency, arg.EffectiveFrom, ErrFXRateConflict)
+			}
+
+			if _, err := q.CreateFXRate(ctx, arg); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					err = ErrFXRateConflict
+				}
+				return fmt.Errorf("cannot store rate %s/%s: %w", arg.FromCurrency, arg.ToCurrency, err)
+			}
+			imported++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return imported, nil
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dxtym/bankrupt/utils"
+)
+
+const (
+	ReversalNone    = "none"
+	ReversalPartial = "partial"
+	ReversalFull    = "full"
+)
+
+var (
+	ErrTransferReversed       = errors.New("transfer is already fully reversed")
+	ErrReversalAmountExceeded = errors.New("reversal amount exceeds unreversed amount")
+	ErrTransferNotReversible  = errors.New("transfer cannot be reversed")
+)
+
+type ReverseTransferTxParams struct {
+	TransferID  int64              `json:"transfer_id"`
+	Amount      utils.Money        `json:"amount"` // zero reverses whatever is left
+	Idempotency *IdempotencyParams `json:"-"`      // optional, replays the first result for retried requests
+}
+
+type ReverseTransferTxResult struct {
+	TransferTxResult
+	Original Transfer `json:"original"`
+}
+
+// move money back from receiver to sender, linked to the original transfer
+func (store *SqlStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+	err := store.execIdempotentTx(ctx, arg.Idempotency, &result, func(q *Queries) error {
+		// concurrent reversals of one transfer queue up here
+		original, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		// reversals and conversions have no single amount to give back
+		if original.ReversalOf.Valid || original.ToAmount.Valid {
+			return fmt.Errorf("transfer [%d]: %w", original.ID, ErrTransferNotReversible)
+		}
+
+		remaining := original.Amount - original.ReversedAmount
+		if remaining == 0 {
+			return fmt.Errorf("transfer [%d]: %w", original.ID, ErrTransferReversed)
+		}
+
+		// only money that actually moved can be given back
+		if original.Status != TransferPosted {
+			return fmt.Errorf("transfer [%d] is %s: %w", original.ID, original.Status, ErrTransferNotReversible)
+		}
+
+		amount := arg.Amount
+		if amount.IsZero() {
+			amount = utils.NewMoney(remaining, original.Currency)
+		}
+		if amount.Currency != original.Currency {
+			return fmt.Errorf("transfer [%d]: %w", original.ID, utils.ErrCurrencyMismatch)
+		}
+		if amount.Amount > remaining {
+			return fmt.Errorf("transfer [%d]: %w", original.ID, ErrReversalAmountExceeded)
+		}
+
+		if err = lockSystemAccount(ctx, q, original.FromAccountID, original.ToAccountID); err != nil {
+			return err
+		}
+
+		// receiver is debited under its own overdraft rules
+		result.TransferTxResult, err = transferMoney(ctx, q, TransferTxParams{
+			FromAccountId: original.ToAccountID,
+			ToAccountId:   original.FromAccountID,
+			Amount:        amount,
+			reversalOf:    original.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Original, err = q.AddTransferReversedAmount(ctx, AddTransferReversedAmountParams{
+			ID:     original.ID,
+			Amount: amount.Amount,
+		})
+		if err != nil || result.Original.ReversedAmount < result.Original.Amount {
+			return err
+		}
+
+		result.Original, err = moveTransfer(ctx, q, result.Original, TransferReversed, "")
+		return err
+	})
+
+	return result, err
+}
+
+// deposits and withdrawals lock the cash account before the customer's, so does a reversal of one
+func lockSystemAccount(ctx context.Context, q *Queries, accountIDs ...int64) error {
+	for _, id := range accountIDs {
+		account, err := q.GetAccount(ctx, id)
+		if err != nil {
+			return err
+		}
+		if account.IsSystem() {
+			_, err = q.GetAccountUpdate(ctx, id)
+			return err
+		}
+	}
+	return nil
+}
+
+func (transfer Transfer) ReversalStatus() string {
+	switch {
+	case transfer.ReversedAmount == 0:
+		return ReversalNone
+	case transfer.ReversedAmount < transfer.Amount:
+		return ReversalPartial
+	}
+	return ReversalFull
+}
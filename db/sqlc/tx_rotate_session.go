@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSessionMismatch    = errors.New("refresh token doesn't match the session")
+	ErrSessionBlocked     = errors.New("session is blocked")
+	ErrSessionExpired     = errors.New("session is expired")
+	ErrRefreshTokenReused = errors.New("refresh token was already used, session revoked")
+)
+
+type RotateSessionTxParams struct {
+	ID               uuid.UUID `json:"id"`
+	Username         string    `json:"username"`
+	RefreshTokenHash string    `json:"refresh_token_hash"`
+	// builds the replacement session, family and username are taken from the rotated one
+	NextSession func(rotated Session) (CreateSessionParams, error) `json:"-"`
+}
+
+type RotateSessionTxResult struct {
+	Rotated Session `json:"rotated"`
+	Session Session `json:"session"`
+}
+
+// swap the presented refresh token for a new one, presenting a rotated
+// token again revokes the whole family it belongs to
+func (store *SqlStore) RotateSessionTx(ctx context.Context, arg RotateSessionTxParams) (RotateSessionTxResult, error) {
+	var result RotateSessionTxResult
+	var reused bool
+	err := store.execTx(ctx, func(q *Queries) error {
+		session, err := q.GetSessionForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if session.Username != arg.Username ||
+			subtle.ConstantTimeCompare([]byte(session.RefreshTokenHash), []byte(arg.RefreshTokenHash)) != 1 {
+			return ErrSessionMismatch
+		}
+		if session.IsBlocked {
+			return ErrSessionBlocked
+		}
+
+		// the block has to commit, so the error is returned after the tx
+		if session.RotatedAt.Valid {
+			reused = true
+			_, err := q.BlockSessionFamily(ctx, session.FamilyID)
+			return err
+		}
+
+		if !time.Now().Before(session.ExpiresAt) {
+			return ErrSessionExpired
+		}
+
+		result.Rotated, err = q.RotateSession(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+
+		next, err := arg.NextSession(result.Rotated)
+		if err != nil {
+			return err
+		}
+		next.FamilyID = session.FamilyID
+		next.Username = session.Username
+
+		result.Session, err = q.CreateSession(ctx, next)
+		return err
+	})
+
+	if err == nil && reused {
+		return result, ErrRefreshTokenReused
+	}
+	return result, err
+}
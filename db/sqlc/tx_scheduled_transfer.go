@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/lib/pq"
+)
+
+const (
+	ScheduleActive    = "active"    // runs at next_run_at
+	SchedulePaused    = "paused"    // kept, but skipped by the worker
+	ScheduleFinished  = "finished"  // no runs left before end_at
+	ScheduleCancelled = "cancelled" // stopped by the owner
+)
+
+const (
+	ScheduledRunPosted = "posted"
+	ScheduledRunFailed = "failed"
+)
+
+var (
+	ErrScheduleClosed       = errors.New("scheduled transfer is closed")
+	ErrScheduledRunRecorded = errors.New("scheduled run is already recorded")
+)
+
+type RecordScheduledRunTxParams struct {
+	ScheduledTransferID int64     `json:"scheduled_transfer_id"`
+	ScheduledFor        time.Time `json:"scheduled_for"`
+	TransferID          int64     `json:"transfer_id"`    // set when the run posted
+	FailureReason       string    `json:"failure_reason"` // set when the run failed
+	Pause               bool      `json:"pause"`          // the failure repeats on every run, so the schedule stops
+
+	// task published once a failed run is committed
+	OnFailed func(run ScheduledTransferRun) OutboxTask `json:"-"`
+}
+
+// per occurrence key, so a retried run replays instead of paying twice
+func ScheduledRunIdempotency(schedule ScheduledTransfer, scheduledFor time.Time) *IdempotencyParams {
+	key := fmt.Sprintf("scheduled_transfer:%d:%d", schedule.ID, scheduledFor.Unix())
+	hash := sha256.Sum256([]byte(key))
+
+	return &IdempotencyParams{
+		Username:    schedule.Owner,
+		Key:         key,
+		RequestHash: hex.EncodeToString(hash[:]),
+	}
+}
+
+// record outcome of one occurrence and move schedule to its next run
+func (store *SqlStore) RecordScheduledRunTx(ctx context.Context, arg RecordScheduledRunTxParams) (ScheduledTransferRun, error) {
+	var result ScheduledTransferRun
+	err := store.execTx(ctx, func(q *Queries) error {
+		schedule, err := q.GetScheduledTransferForUpdate(ctx, arg.ScheduledTransferID)
+		if err != nil {
+			return err
+		}
+
+		runArg := CreateScheduledTransferRunParams{
+			ScheduledTransferID: schedule.ID,
+			ScheduledFor:        arg.ScheduledFor,
+			Status:              ScheduledRunPosted,
+			TransferID:          sql.NullInt64{Int64: arg.TransferID, Valid: arg.TransferID != 0},
+		}
+		if arg.TransferID == 0 {
+			runArg.Status = ScheduledRunFailed
+			runArg.FailureReason = sql.NullString{String: arg.FailureReason, Valid: true}
+		}
+
+		result, err = q.CreateScheduledTransferRun(ctx, runArg)
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			// an overlapping run got here first
+			return fmt.Errorf("run [%d] at %s: %w", schedule.ID, arg.ScheduledFor, ErrScheduledRunRecorded)
+		}
+		if err != nil {
+			return err
+		}
+
+		if result.Status == ScheduledRunFailed && arg.OnFailed != nil {
+			if err := enqueueOutbox(ctx, q, arg.OnFailed(result)); err != nil {
+				return err
+			}
+		}
+
+		// owner may have edited or paused the schedule meanwhile
+		if schedule.Status != ScheduleActive || !schedule.NextRunAt.Time.Equal(arg.ScheduledFor) {
+			return nil
+		}
+
+		// paused until the owner fixes the accounts, so it doesn't fail and notify every time
+		if arg.Pause {
+			_, err = q.UpdateScheduledTransfer(ctx, UpdateScheduledTransferParams{
+				ID:        schedule.ID,
+				Amount:    schedule.Amount,
+				Schedule:  schedule.Schedule,
+				EndAt:     schedule.EndAt,
+				NextRunAt: schedule.NextRunAt,
+				Status:    SchedulePaused,
+			})
+			return err
+		}
+
+		// missed occurrences are skipped rather than paid in a burst
+		after := arg.ScheduledFor
+		if now := time.Now(); now.After(after) {
+			after = now
+		}
+		next, err := utils.NextRun(schedule.Schedule, after, schedule.EndAt.Time)
+		if err != nil {
+			return err
+		}
+
+		updateArg := UpdateScheduledTransferParams{
+			ID:        schedule.ID,
+			Amount:    schedule.Amount,
+			Schedule:  schedule.Schedule,
+			EndAt:     schedule.EndAt,
+			NextRunAt: sql.NullTime{Time: next, Valid: !next.IsZero()},
+			Status:    ScheduleActive,
+		}
+		if next.IsZero() {
+			updateArg.Status = ScheduleFinished
+		}
+
+		_, err = q.UpdateScheduledTransfer(ctx, updateArg)
+		return err
+	})
+
+	return result, err
+}
+
+type UpdateScheduledTransferTxParams struct {
+	ID int64 `json:"id"`
+	// builds the new row from the locked schedule, may reject the change
+	Update func(schedule ScheduledTransfer) (UpdateScheduledTransferParams, error) `json:"-"`
+}
+
+// edit a schedule under its row lock, so it can't race the worker recording a run
+func (store *SqlStore) UpdateScheduledTransferTx(ctx context.Context, arg UpdateScheduledTransferTxParams) (ScheduledTransfer, error) {
+	var result ScheduledTransfer
+	err := store.execTx(ctx, func(q *Queries) error {
+		schedule, err := q.GetScheduledTransferForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if schedule.Status == ScheduleFinished || schedule.Status == ScheduleCancelled {
+			return fmt.Errorf("already %s: %w", schedule.Status, ErrScheduleClosed)
+		}
+
+		updateArg, err := arg.Update(schedule)
+		if err != nil {
+			return err
+		}
+		updateArg.ID = schedule.ID
+
+		result, err = q.UpdateScheduledTransfer(ctx, updateArg)
+		return err
+	})
+
+	return result, err
+}
@@ -2,13 +2,21 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/lib/pq"
 )
 
 type TransferTxParams struct {
-	FromAccountId int64 `json:"from_account_id"`
-	ToAccountId   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountId int64              `json:"from_account_id"`
+	ToAccountId   int64              `json:"to_account_id"`
+	Amount        utils.Money        `json:"amount"`
+	Memo          string             `json:"memo"`
+	Idempotency   *IdempotencyParams `json:"-"` // optional, replays the first result for retried requests
+	reversalOf    int64              // set by ReverseTransferTx only
+	checkLimits   bool               // set by TransferTx and CaptureHoldTx, internal movements are not limited
 }
 
 type TransferTxResult struct {
@@ -24,56 +32,131 @@ var ctxKey = struct{}{}
 // perform transaction, record entries, update accounts
 func (store *SqlStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
-	err := store.execTx(ctx, func(q *Queries) error {
+	err := store.execIdempotentTx(ctx, arg.Idempotency, &result, func(q *Queries) error {
 		var err error
+		arg.checkLimits = true
+		result, err = transferMoney(ctx, q, arg)
+		return err
+	})
 
-		txName := ctx.Value(ctxKey)
-
-		// create transaction
-		fmt.Println(txName, "create transfer")
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountId,
-			ToAccountID:   arg.ToAccountId,
-			Amount:        arg.Amount,
-		})
-		if err != nil {
-			return err
-		}
+	return result, err
+}
 
-		// record two entries
-		fmt.Println(txName, "create entry 1")
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountId,
-			Amount:    -arg.Amount,
-		})
-		if err != nil {
-			return err
-		}
+// move money between two accounts inside running transaction
+func transferMoney(ctx context.Context, q *Queries, arg TransferTxParams) (result TransferTxResult, err error) {
+	txName := ctx.Value(ctxKey)
 
-		fmt.Println(txName, "create entry 2")
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountId,
-			Amount:    arg.Amount,
-		})
-		if err != nil {
-			return err
-		}
+	if err = lockFunds(ctx, q, arg.FromAccountId, arg.ToAccountId, arg.Amount); err != nil {
+		return
+	}
 
-		// update two account balance
-		if arg.FromAccountId < arg.ToAccountId {
-			result.FromAccount, result.ToAccount, err = AddMoney(ctx, q, txName, arg.FromAccountId, -arg.Amount, arg.ToAccountId, arg.Amount)
-		} else {
-			result.FromAccount, result.ToAccount, err = AddMoney(ctx, q, txName, arg.ToAccountId, arg.Amount, arg.FromAccountId, -arg.Amount)
+	if arg.checkLimits {
+		if err = checkTransferLimits(ctx, q, arg.FromAccountId, arg.Amount, 0); err != nil {
+			return
 		}
+	}
 
-		if err != nil {
-			return err
+	// create transaction
+	fmt.Println(txName, "create transfer")
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: arg.FromAccountId,
+		ToAccountID:   arg.ToAccountId,
+		Amount:        arg.Amount.Amount,
+		Currency:      arg.Amount.Currency,
+		ReversalOf:    sql.NullInt64{Int64: arg.reversalOf, Valid: arg.reversalOf != 0},
+		Memo:          arg.Memo,
+	})
+	if err != nil {
+		return
+	}
+
+	err = postEntries(ctx, q, &result)
+	return
+}
+
+// lock both accounts and check sender can spend the amount
+func lockFunds(ctx context.Context, q *Queries, fromAccountID, toAccountID int64, amount utils.Money) (err error) {
+	// lock both accounts before checking funds
+	var fromAccount, toAccount Account
+	if fromAccountID < toAccountID {
+		fromAccount, toAccount, err = lockAccounts(ctx, q, fromAccountID, toAccountID)
+	} else {
+		toAccount, fromAccount, err = lockAccounts(ctx, q, toAccountID, fromAccountID)
+	}
+	if err != nil {
+		return
+	}
+
+	// amounts are in minor units, so they only add up within one currency
+	for _, account := range []Account{fromAccount, toAccount} {
+		if account.Currency != amount.Currency {
+			return fmt.Errorf("account [%d]: %w", account.ID, utils.ErrCurrencyMismatch)
 		}
+	}
 
-		return nil
+	return checkAvailable(ctx, q, fromAccount, amount.Amount)
+}
+
+// update balances and record two entries for the transfer, accounts must be locked
+func postEntries(ctx context.Context, q *Queries, result *TransferTxResult) (err error) {
+	txName := ctx.Value(ctxKey)
+	transfer := result.Transfer
+	amount := transfer.Amount
+
+	// update two account balance first, entries keep the balance they left behind
+	if transfer.FromAccountID < transfer.ToAccountID {
+		result.FromAccount, result.ToAccount, err = AddMoney(ctx, q, txName, transfer.FromAccountID, -amount, transfer.ToAccountID, amount)
+	} else {
+		result.ToAccount, result.FromAccount, err = AddMoney(ctx, q, txName, transfer.ToAccountID, amount, transfer.FromAccountID, -amount)
+	}
+	if err != nil {
+		return checkFundsError(err)
+	}
+
+	// record two entries
+	fmt.Println(txName, "create entry 1")
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:    transfer.FromAccountID,
+		Amount:       -amount,
+		TransferID:   sql.NullInt64{Int64: transfer.ID, Valid: true},
+		BalanceAfter: result.FromAccount.Balance,
 	})
+	if err != nil {
+		return
+	}
 
-	return result, err
+	fmt.Println(txName, "create entry 2")
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:    transfer.ToAccountID,
+		Amount:       amount,
+		TransferID:   sql.NullInt64{Int64: transfer.ID, Valid: true},
+		BalanceAfter: result.ToAccount.Balance,
+	})
+	return
+}
+
+// lock accounts in the same order as balance updates to avoid deadlock
+func lockAccounts(
+	ctx context.Context,
+	q *Queries,
+	account1Id,
+	account2Id int64,
+) (account1 Account, account2 Account, err error) {
+	account1, err = q.GetAccountUpdate(ctx, account1Id)
+	if err != nil {
+		return
+	}
+
+	account2, err = q.GetAccountUpdate(ctx, account2Id)
+	return
+}
+
+// db check constraint is the last line of defense against overdraft
+func checkFundsError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Constraint == "balance_overdraft_check" {
+		return fmt.Errorf("%s: %w", pqErr.Message, ErrInsufficientFunds)
+	}
+	return err
 }
 
 func AddMoney(
@@ -0,0 +1,224 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dxtym/bankrupt/utils"
+)
+
+const (
+	BatchAtomic  = "atomic"  // one failed leg rolls back the whole batch
+	BatchChunked = "chunked" // failed legs are skipped, the rest is posted
+)
+
+const (
+	BatchRunning = "running"
+	BatchPosted  = "posted"  // every leg posted
+	BatchPartial = "partial" // some legs failed
+	BatchFailed  = "failed"  // nothing posted
+)
+
+// another request processed legs of the batch since the caller last looked
+var ErrBatchConflict = errors.New("batch is being run by another request")
+
+type TransferBatchLeg struct {
+	ToAccountId int64       `json:"to_account_id"`
+	Amount      utils.Money `json:"amount"`
+	Memo        string      `json:"memo"`
+}
+
+type TransferBatchTxParams struct {
+	BatchID       int64              `json:"batch_id"`
+	Offset        int                `json:"offset"` // legs of the batch processed before these
+	FromAccountId int64              `json:"from_account_id"`
+	Legs          []TransferBatchLeg `json:"legs"`
+	Atomic        bool               `json:"atomic"`
+	// optional, called after each leg while the transaction is still open, must not block
+	OnLeg func(index int, err error) `json:"-"`
+}
+
+// transfer of a posted leg, error of a failed one
+type TransferBatchLegResult struct {
+	Transfer Transfer `json:"transfer"`
+	Err      error    `json:"-"`
+}
+
+type TransferBatchTxResult struct {
+	Batch       TransferBatch            `json:"batch"` // with the counts of these legs added
+	FromAccount Account                  `json:"from_account"`
+	Legs        []TransferBatchLegResult `json:"legs"`
+}
+
+// what every leg of a batch checks, loaded once and kept up to date in memory
+type batchState struct {
+	from     Account
+	held     int64 // reserved by active holds of the from account
+	accounts map[int64]Account
+	limits   transferLimits
+}
+
+// post legs from one account in a single transaction, accounts, limits and usage are loaded once up front,
+// the counts of the batch move with the legs so a retry knows where to carry on
+func (store *SqlStore) TransferBatchTx(ctx context.Context, arg TransferBatchTxParams) (TransferBatchTxResult, error) {
+	var result TransferBatchTxResult
+	err := store.execTx(ctx, func(q *Queries) error {
+		result = TransferBatchTxResult{Legs: make([]TransferBatchLegResult, len(arg.Legs))}
+
+		if arg.BatchID != 0 {
+			batch, err := q.GetTransferBatchForUpdate(ctx, arg.BatchID)
+			if err != nil {
+				return err
+			}
+			if int(batch.PostedCount+batch.FailedCount) != arg.Offset {
+				return fmt.Errorf("batch [%d]: %w", arg.BatchID, ErrBatchConflict)
+			}
+		}
+
+		state, err := loadBatchState(ctx, q, arg)
+		if err != nil {
+			return err
+		}
+		result.FromAccount = state.from
+
+		for i, leg := range arg.Legs {
+			legResult, err := postBatchLeg(ctx, q, arg, leg, state)
+			if err != nil && arg.Atomic {
+				return fmt.Errorf("leg [%d]: %w", i, err)
+			}
+
+			result.Legs[i].Err = err
+			if err == nil {
+				result.Legs[i].Transfer = legResult.Transfer
+				result.FromAccount = legResult.FromAccount
+				state.from = legResult.FromAccount
+				state.limits.add(leg.Amount)
+			}
+			if arg.OnLeg != nil {
+				arg.OnLeg(i, err)
+			}
+		}
+
+		if arg.BatchID == 0 {
+			return nil
+		}
+		var posted, failed int32
+		for _, leg := range result.Legs {
+			if leg.Err != nil {
+				failed++
+			} else {
+				posted++
+			}
+		}
+		result.Batch, err = q.AddTransferBatchProgress(ctx, AddTransferBatchProgressParams{
+			ID:     arg.BatchID,
+			Posted: posted,
+			Failed: failed,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// lock every account of the batch and load what the legs are checked against
+func loadBatchState(ctx context.Context, q *Queries, arg TransferBatchTxParams) (*batchState, error) {
+	ids := make([]int64, 0, len(arg.Legs)+1)
+	ids = append(ids, arg.FromAccountId)
+	for _, leg := range arg.Legs {
+		ids = append(ids, leg.ToAccountId)
+	}
+	accounts, err := q.GetAccountsForUpdate(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &batchState{accounts: make(map[int64]Account, len(accounts))}
+	for _, account := range accounts {
+		state.accounts[account.ID] = account
+	}
+
+	from, ok := state.accounts[arg.FromAccountId]
+	if !ok {
+		return nil, fmt.Errorf("account [%d]: %w", arg.FromAccountId, sql.ErrNoRows)
+	}
+	state.from = from
+
+	if from.OverdraftPolicy() != OverdraftUnlimited {
+		if state.held, err = q.GetHeldAmount(ctx, from.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// legs in another currency fail before limits are checked
+	state.limits, err = loadTransferLimits(ctx, q, from, from.Currency, 0)
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// chunked legs run inside a savepoint so one failure doesn't abort the transaction
+func postBatchLeg(ctx context.Context, q *Queries, arg TransferBatchTxParams, leg TransferBatchLeg, state *batchState) (result TransferTxResult, err error) {
+	to, ok := state.accounts[leg.ToAccountId]
+	if !ok {
+		return result, fmt.Errorf("account [%d]: %w", leg.ToAccountId, sql.ErrNoRows)
+	}
+	if to.IsSystem() {
+		return result, fmt.Errorf("account [%d]: %w", leg.ToAccountId, ErrSystemAccount)
+	}
+
+	transfer := func() error {
+		result, err = postBatchTransfer(ctx, q, arg, leg, state)
+		return checkFundsError(err)
+	}
+	if arg.Atomic {
+		return result, transfer()
+	}
+
+	if _, err = q.db.ExecContext(ctx, "SAVEPOINT batch_leg"); err != nil {
+		return
+	}
+	if legErr := transfer(); legErr != nil {
+		if _, err = q.db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT batch_leg"); err != nil {
+			return
+		}
+		return result, legErr
+	}
+	_, err = q.db.ExecContext(ctx, "RELEASE SAVEPOINT batch_leg")
+	return
+}
+
+// same checks as transferMoney, against the state instead of fresh queries
+func postBatchTransfer(ctx context.Context, q *Queries, arg TransferBatchTxParams, leg TransferBatchLeg, state *batchState) (result TransferTxResult, err error) {
+	for _, account := range []Account{state.from, state.accounts[leg.ToAccountId]} {
+		if account.Currency != leg.Amount.Currency {
+			return result, fmt.Errorf("account [%d]: %w", account.ID, utils.ErrCurrencyMismatch)
+		}
+	}
+
+	if !state.from.CanDebit(leg.Amount.Amount + state.held) {
+		return result, fmt.Errorf("account [%d]: %w", state.from.ID, ErrInsufficientFunds)
+	}
+
+	if err = state.limits.check(leg.Amount); err != nil {
+		return
+	}
+
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: arg.FromAccountId,
+		ToAccountID:   leg.ToAccountId,
+		Amount:        leg.Amount.Amount,
+		Currency:      leg.Amount.Currency,
+		Memo:          leg.Memo,
+		BatchID:       sql.NullInt64{Int64: arg.BatchID, Valid: arg.BatchID != 0},
+	})
+	if err != nil {
+		return
+	}
+
+	err = postEntries(ctx, q, &result)
+	return
+}
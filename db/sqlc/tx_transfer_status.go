@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dxtym/bankrupt/utils"
+)
+
+// record entries for a pending transfer, limits are checked when the money moves
+func (store *SqlStore) PostTransferTx(ctx context.Context, transferID int64) (TransferTxResult, error) {
+	var result TransferTxResult
+	err := store.execTx(ctx, func(q *Queries) error {
+		transfer, err := q.GetTransferForUpdate(ctx, transferID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkTransferTransition(transfer, TransferPosted); err != nil {
+			return err
+		}
+
+		amount := utils.NewMoney(transfer.Amount, transfer.Currency)
+		if err := lockFunds(ctx, q, transfer.FromAccountID, transfer.ToAccountID, amount); err != nil {
+			return err
+		}
+		if err := checkTransferLimits(ctx, q, transfer.FromAccountID, amount, transfer.ID); err != nil {
+			return err
+		}
+
+		result.Transfer, err = moveTransfer(ctx, q, transfer, TransferPosted, "")
+		if err != nil {
+			return err
+		}
+
+		return postEntries(ctx, q, &result)
+	})
+
+	return result, err
+}
+
+// give up on a pending transfer with a reason
+func (store *SqlStore) FailTransferTx(ctx context.Context, transferID int64, reason string) (Transfer, error) {
+	return store.transitionTransfer(ctx, transferID, TransferFailed, reason)
+}
+
+// withdraw a pending transfer before it posts
+func (store *SqlStore) CancelTransferTx(ctx context.Context, transferID int64) (Transfer, error) {
+	return store.transitionTransfer(ctx, transferID, TransferCancelled, "")
+}
+
+// move transfer into a status that doesn't touch balances
+func (store *SqlStore) transitionTransfer(ctx context.Context, transferID int64, to string, reason string) (Transfer, error) {
+	var result Transfer
+	err := store.execTx(ctx, func(q *Queries) error {
+		transfer, err := q.GetTransferForUpdate(ctx, transferID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkTransferTransition(transfer, to); err != nil {
+			return err
+		}
+
+		result, err = moveTransfer(ctx, q, transfer, to, reason)
+		return err
+	})
+
+	return result, err
+}
+
+// transfer must be locked and the transition checked
+func moveTransfer(ctx context.Context, q *Queries, transfer Transfer, to string, reason string) (Transfer, error) {
+	updated, err := q.UpdateTransferStatus(ctx, UpdateTransferStatusParams{
+		ID:            transfer.ID,
+		FromStatus:    transfer.Status,
+		Status:        to,
+		FailureReason: sql.NullString{String: reason, Valid: to == TransferFailed},
+	})
+	if err == sql.ErrNoRows {
+		return updated, fmt.Errorf("transfer [%d] %s -> %s: %w", transfer.ID, transfer.Status, to, ErrInvalidTransferTransition)
+	}
+	return updated, err
+}
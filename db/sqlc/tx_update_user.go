@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+)
+
+type UpdateUserTxParams struct {
+	UpdateUserParams
+	OnEmailChanged func(user User) []OutboxTask // published after a new address is committed
+}
+
+type UpdateUserTxResult struct {
+	User User
+}
+
+func (store *SqlStore) UpdateUserTx(ctx context.Context, arg UpdateUserTxParams) (UpdateUserTxResult, error) {
+	var txResult UpdateUserTxResult
+	err := store.execTx(ctx, func(q *Queries) error {
+		user, err := q.GetUserForUpdate(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		txResult.User, err = q.UpdateUser(ctx, arg.UpdateUserParams)
+		if err != nil {
+			return err
+		}
+
+		if txResult.User.Email == user.Email || arg.OnEmailChanged == nil {
+			return nil
+		}
+		return enqueueOutbox(ctx, q, arg.OnEmailChanged(txResult.User)...)
+	})
+
+	return txResult, err
+}
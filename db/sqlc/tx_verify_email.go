@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	ErrVerifyEmailInvalid   = errors.New("verify email not found")
+	ErrVerifyEmailUsed      = errors.New("verify email already used")
+	ErrVerifyEmailExpired   = errors.New("verify email expired")
+	ErrVerifyEmailChanged   = errors.New("email changed since the code was sent")
+	ErrEmailAlreadyVerified = errors.New("email already verified")
+)
+
+type VerifyEmailTxParams struct {
+	EmailId    int64  `json:"email_id"`
+	SecretCode string `json:"secret_code"`
+}
+
+type VerifyEmailTxResult struct {
+	User        User        `json:"user"`
+	VerifyEmail VerifyEmail `json:"verify_email"`
+}
+
+// use the code once and mark the address it was sent to verified
+func (store *SqlStore) VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error) {
+	var result VerifyEmailTxResult
+	err := store.execTx(ctx, func(q *Queries) error {
+		verifyEmail, err := q.GetVerifyEmailForUpdate(ctx, arg.EmailId)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrVerifyEmailInvalid
+			}
+			return err
+		}
+
+		// wrong code looks the same as a missing one
+		if subtle.ConstantTimeCompare([]byte(verifyEmail.SecretCode), []byte(arg.SecretCode)) != 1 {
+			return ErrVerifyEmailInvalid
+		}
+		if verifyEmail.IsUsed {
+			return ErrVerifyEmailUsed
+		}
+		if !time.Now().Before(verifyEmail.ExpiredAt) {
+			return ErrVerifyEmailExpired
+		}
+
+		result.VerifyEmail, err = q.UseVerifyEmail(ctx, verifyEmail.ID)
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.VerifyUserEmail(ctx, VerifyUserEmailParams{
+			Username: verifyEmail.Username,
+			Email:    verifyEmail.Email,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrVerifyEmailChanged
+		}
+		return err
+	})
+
+	return result, err
+}
+
+type ResendVerifyEmailTxParams struct {
+	Username string       `json:"username"`
+	Outbox   []OutboxTask // published only while the address is unverified
+}
+
+// queue a new link unless the current address is already verified
+func (store *SqlStore) ResendVerifyEmailTx(ctx context.Context, arg ResendVerifyEmailTxParams) (User, error) {
+	var user User
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		user, err = q.GetUserForUpdate(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+		if user.IsEmailVerified {
+			return ErrEmailAlreadyVerified
+		}
+		return enqueueOutbox(ctx, q, arg.Outbox...)
+	})
+
+	return user, err
+}
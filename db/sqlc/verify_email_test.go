@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomVerifyEmail(t *testing.T, user User) VerifyEmail {
+	arg := CreateVerifyEmailParams{
+		Username:   user.Username,
+		Email:      user.Email,
+		SecretCode: utils.RandomString(32),
+	}
+	verifyEmail, err := testQueries.CreateVerifyEmail(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.Equal(t, arg.Username, verifyEmail.Username)
+	require.Equal(t, arg.Email, verifyEmail.Email)
+	require.Equal(t, arg.SecretCode, verifyEmail.SecretCode)
+	require.False(t, verifyEmail.IsUsed)
+	require.WithinDuration(t, verifyEmail.CreatedAt.Add(15*time.Minute), verifyEmail.ExpiredAt, time.Second)
+	return verifyEmail
+}
+
+// test verify email once with the right code
+func TestVerifyEmailTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	require.False(t, user.IsEmailVerified)
+	verifyEmail := createRandomVerifyEmail(t, user)
+
+	_, err := store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailId:    verifyEmail.ID,
+		SecretCode: utils.RandomString(32),
+	})
+	require.ErrorIs(t, err, ErrVerifyEmailInvalid)
+
+	arg := VerifyEmailTxParams{
+		EmailId:    verifyEmail.ID,
+		SecretCode: verifyEmail.SecretCode,
+	}
+	result, err := store.VerifyEmailTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.VerifyEmail.IsUsed)
+	require.True(t, result.User.IsEmailVerified)
+
+	_, err = store.VerifyEmailTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrVerifyEmailUsed)
+}
+
+// test code sent to an old address can't verify the new one
+func TestVerifyEmailTxChanged(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	verifyEmail := createRandomVerifyEmail(t, user)
+
+	_, err := testQueries.UpdateUser(context.Background(), UpdateUserParams{
+		Username: user.Username,
+		Email:    sql.NullString{String: utils.RandomEmail(), Valid: true},
+	})
+	require.NoError(t, err)
+
+	_, err = store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{
+		EmailId:    verifyEmail.ID,
+		SecretCode: verifyEmail.SecretCode,
+	})
+	require.ErrorIs(t, err, ErrVerifyEmailChanged)
+
+	// not used, rolled back with the failed verification
+	verifyEmail, err = testQueries.GetVerifyEmailForUpdate(context.Background(), verifyEmail.ID)
+	require.NoError(t, err)
+	require.False(t, verifyEmail.IsUsed)
+}
+
+// test new email resets verification and queues a new link
+func TestUpdateUserTxEmailChanged(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	user, err := testQueries.VerifyUserEmail(context.Background(), VerifyUserEmailParams{
+		Username: user.Username,
+		Email:    user.Email,
+	})
+	require.NoError(t, err)
+	require.True(t, user.IsEmailVerified)
+
+	onEmailChanged := func(user User) []OutboxTask {
+		return []OutboxTask{{Type: "task:send_email", Payload: map[string]string{"username": user.Username}, Queue: "critical"}}
+	}
+
+	// same address keeps verification and queues nothing
+	last := createRandomOutboxMessage(t)
+	result, err := store.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: user.Username,
+			Email:    sql.NullString{String: user.Email, Valid: true},
+		},
+		OnEmailChanged: onEmailChanged,
+	})
+	require.NoError(t, err)
+	require.True(t, result.User.IsEmailVerified)
+	next := createRandomOutboxMessage(t)
+	require.Equal(t, last.ID+1, next.ID)
+
+	newEmail := utils.RandomEmail()
+	result, err = store.UpdateUserTx(context.Background(), UpdateUserTxParams{
+		UpdateUserParams: UpdateUserParams{
+			Username: user.Username,
+			Email:    sql.NullString{String: newEmail, Valid: true},
+		},
+		OnEmailChanged: onEmailChanged,
+	})
+	require.NoError(t, err)
+	require.Equal(t, newEmail, result.User.Email)
+	require.False(t, result.User.IsEmailVerified)
+
+	message, err := testQueries.GetOutboxMessage(context.Background(), next.ID+1)
+	require.NoError(t, err)
+	require.Equal(t, "task:send_email", message.TaskType)
+	require.JSONEq(t, `{"username":"`+user.Username+`"}`, string(message.Payload))
+}
+
+// test resend queues a link only while the address is unverified
+func TestResendVerifyEmailTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	arg := ResendVerifyEmailTxParams{
+		Username: user.Username,
+		Outbox:   []OutboxTask{{Type: "task:send_email", Payload: map[string]string{"username": user.Username}, Queue: "critical"}},
+	}
+	last := createRandomOutboxMessage(t)
+	result, err := store.ResendVerifyEmailTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, user.Username, result.Username)
+	require.False(t, result.IsEmailVerified)
+
+	message, err := testQueries.GetOutboxMessage(context.Background(), last.ID+1)
+	require.NoError(t, err)
+	require.Equal(t, "task:send_email", message.TaskType)
+	require.JSONEq(t, `{"username":"`+user.Username+`"}`, string(message.Payload))
+
+	_, err = testQueries.VerifyUserEmail(context.Background(), VerifyUserEmailParams{
+		Username: user.Username,
+		Email:    user.Email,
+	})
+	require.NoError(t, err)
+
+	last = createRandomOutboxMessage(t)
+	_, err = store.ResendVerifyEmailTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrEmailAlreadyVerified)
+	next := createRandomOutboxMessage(t)
+	require.Equal(t, last.ID+1, next.ID)
+
+	_, err = store.ResendVerifyEmailTx(context.Background(), ResendVerifyEmailTxParams{Username: utils.RandomOwner()})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,113 @@
+package fx
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reads rates from csv or json file for local and offline use
+type FileRateProvider struct {
+	path string
+}
+
+func NewFileRateProvider(path string) RateProvider {
+	return &FileRateProvider{
+		path: path,
+	}
+}
+
+func (p *FileRateProvider) Name() string {
+	return "file"
+}
+
+func (p *FileRateProvider) FetchRates(ctx context.Context) ([]Rate, error) {
+	file, err := os.Open(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open rates file: %w", err)
+	}
+	defer file.Close()
+
+	// rows without effective_from are stamped at import, unchanged ones are skipped then
+	return ParseRates(file, filepath.Ext(p.path))
+}
+
+// parse rates by file extension
+func ParseRates(r io.Reader, ext string) ([]Rate, error) {
+	switch strings.ToLower(ext) {
+	case ".csv":
+		return ParseCSV(r)
+	case ".json":
+		return ParseJSON(r)
+	}
+	return nil, fmt.Errorf("unsupported rates file format %q", ext)
+}
+
+// expects header with from_currency, to_currency, rate
+// and optional spread_bps, effective_from (RFC 3339) columns
+func ParseCSV(r io.Reader) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("csv header is missing")
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"from_currency", "to_currency", "rate"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv column %q is missing", name)
+		}
+	}
+
+	rates := make([]Rate, 0, len(records)-1)
+	for line, record := range records[1:] {
+		rate := Rate{
+			FromCurrency: record[columns["from_currency"]],
+			ToCurrency:   record[columns["to_currency"]],
+			Rate:         record[columns["rate"]],
+		}
+
+		if i, ok := columns["spread_bps"]; ok && record[i] != "" {
+			spread, err := strconv.ParseInt(record[i], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid spread_bps: %w", line+2, err)
+			}
+			spreadBps := int32(spread)
+			rate.SpreadBps = &spreadBps
+		}
+
+		if i, ok := columns["effective_from"]; ok && record[i] != "" {
+			rate.EffectiveFrom, err = time.Parse(time.RFC3339, record[i])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid effective_from: %w", line+2, err)
+			}
+		}
+
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+// expects array of rates
+func ParseJSON(r io.Reader) ([]Rate, error) {
+	var rates []Rate
+	if err := json.NewDecoder(r).Decode(&rates); err != nil {
+		return nil, fmt.Errorf("cannot decode json: %w", err)
+	}
+	return rates, nil
+}
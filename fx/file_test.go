@@ -0,0 +1,69 @@
+package fx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCSV(t *testing.T) {
+	data := `from_currency,to_currency,rate,spread_bps,effective_from
+USD,EUR,0.92,25,2024-05-01T00:00:00Z
+EUR,USD,1.087,,
+`
+	rates, err := ParseCSV(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+
+	require.Equal(t, utils.USD, rates[0].FromCurrency)
+	require.Equal(t, utils.EUR, rates[0].ToCurrency)
+	require.Equal(t, "0.92", rates[0].Rate)
+	require.Equal(t, int32(25), *rates[0].SpreadBps)
+	require.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), rates[0].EffectiveFrom)
+
+	require.Nil(t, rates[1].SpreadBps)
+	require.True(t, rates[1].EffectiveFrom.IsZero())
+
+	_, err = ParseCSV(strings.NewReader("from_currency,rate\nUSD,1"))
+	require.ErrorContains(t, err, "to_currency")
+
+	_, err = ParseCSV(strings.NewReader("from_currency,to_currency,rate,spread_bps\nUSD,EUR,0.92,wide"))
+	require.ErrorContains(t, err, "line 2")
+}
+
+func TestParseJSON(t *testing.T) {
+	data := `[{"from_currency": "USD", "to_currency": "CAD", "rate": "1.37", "effective_from": "2024-05-01T00:00:00Z"}]`
+
+	rates, err := ParseJSON(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	require.Equal(t, utils.CAD, rates[0].ToCurrency)
+	require.Nil(t, rates[0].SpreadBps)
+
+	_, err = ParseRates(strings.NewReader(data), ".xml")
+	require.Error(t, err)
+}
+
+func TestFileRateProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.csv")
+	require.NoError(t, os.WriteFile(path, []byte("from_currency,to_currency,rate\nUSD,EUR,0.92\n"), 0o600))
+
+	provider := NewFileRateProvider(path)
+	require.Equal(t, "file", provider.Name())
+
+	rates1, err := provider.FetchRates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rates1, 1)
+
+	// file time must not backdate rates, the importer stamps them
+	require.True(t, rates1[0].EffectiveFrom.IsZero())
+
+	_, err = NewFileRateProvider(filepath.Join(t.TempDir(), "missing.csv")).FetchRates(context.Background())
+	require.Error(t, err)
+}
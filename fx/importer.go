@@ -0,0 +1,62 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/utils"
+)
+
+// spread taken when rate doesn't set one
+const DefaultSpreadBps = 50
+
+// validate rates and append them to rate history, rates without a time take
+// effect when they are imported
+func ImportRates(ctx context.Context, store db.Store, source string, rates []Rate) (int, error) {
+	now := time.Now()
+	args := make([]db.CreateFXRateParams, 0, len(rates))
+	for i, rate := range rates {
+		arg, err := rateParams(rate, source, now)
+		if err != nil {
+			return 0, fmt.Errorf("rate %d (%s/%s): %w", i+1, rate.FromCurrency, rate.ToCurrency, err)
+		}
+		args = append(args, arg)
+	}
+
+	return store.ImportFXRatesTx(ctx, args)
+}
+
+func rateParams(rate Rate, source string, now time.Time) (db.CreateFXRateParams, error) {
+	if rate.FromCurrency == rate.ToCurrency {
+		return db.CreateFXRateParams{}, fmt.Errorf("currencies must differ")
+	}
+
+	value, err := utils.NormalizeRate(rate.Rate)
+	if err != nil {
+		return db.CreateFXRateParams{}, err
+	}
+
+	spreadBps := int32(DefaultSpreadBps)
+	if rate.SpreadBps != nil {
+		spreadBps = *rate.SpreadBps
+	}
+	if spreadBps < 0 || spreadBps >= 10000 {
+		return db.CreateFXRateParams{}, fmt.Errorf("spread must be between 0 and 9999 bps")
+	}
+
+	effectiveFrom := rate.EffectiveFrom
+	if effectiveFrom.IsZero() {
+		effectiveFrom = now
+	}
+
+	return db.CreateFXRateParams{
+		FromCurrency:  rate.FromCurrency,
+		ToCurrency:    rate.ToCurrency,
+		Rate:          value,
+		SpreadBps:     spreadBps,
+		EffectiveFrom: effectiveFrom,
+		Source:        source,
+	}, nil
+}
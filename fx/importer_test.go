@@ -0,0 +1,125 @@
+package fx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/dxtym/bankrupt/db/mock"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestImportRates(t *testing.T) {
+	effectiveFrom := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	spreadBps := int32(25)
+
+	testCases := []struct {
+		name       string
+		rates      []Rate
+		buildStubs func(store *mockdb.MockStore)
+		check      func(t *testing.T, imported int, err error)
+	}{
+		{
+			name: "OK",
+			rates: []Rate{
+				{FromCurrency: utils.USD, ToCurrency: utils.EUR, Rate: "0.92", SpreadBps: &spreadBps, EffectiveFrom: effectiveFrom},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateFXRateParams{
+					FromCurrency:  utils.USD,
+					ToCurrency:    utils.EUR,
+					Rate:          "0.920000000000",
+					SpreadBps:     spreadBps,
+					EffectiveFrom: effectiveFrom,
+					Source:        "file",
+				}
+				store.EXPECT().ImportFXRatesTx(gomock.Any(), gomock.Eq([]db.CreateFXRateParams{arg})).
+					Times(1).Return(1, nil)
+			},
+			check: func(t *testing.T, imported int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, imported)
+			},
+		},
+		{
+			name: "DefaultSpread",
+			rates: []Rate{
+				{FromCurrency: utils.USD, ToCurrency: utils.CAD, Rate: "1.37", EffectiveFrom: effectiveFrom},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ImportFXRatesTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, args []db.CreateFXRateParams) (int, error) {
+						require.Equal(t, int32(DefaultSpreadBps), args[0].SpreadBps)
+						return len(args), nil
+					})
+			},
+			check: func(t *testing.T, imported int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, imported)
+			},
+		},
+		{
+			name: "StampedAtImport",
+			rates: []Rate{
+				{FromCurrency: utils.USD, ToCurrency: utils.EUR, Rate: "0.92"},
+				{FromCurrency: utils.USD, ToCurrency: utils.CAD, Rate: "1.37"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ImportFXRatesTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, args []db.CreateFXRateParams) (int, error) {
+						require.WithinDuration(t, time.Now(), args[0].EffectiveFrom, time.Second)
+						require.Equal(t, args[0].EffectiveFrom, args[1].EffectiveFrom)
+						return len(args), nil
+					})
+			},
+			check: func(t *testing.T, imported int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 2, imported)
+			},
+		},
+		{
+			name: "InvalidRate",
+			rates: []Rate{
+				{FromCurrency: utils.USD, ToCurrency: utils.EUR, Rate: "0.92"},
+				{FromCurrency: utils.EUR, ToCurrency: utils.USD, Rate: "-1"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				// nothing is stored when any rate is invalid
+				store.EXPECT().ImportFXRatesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, imported int, err error) {
+				require.ErrorIs(t, err, utils.ErrInvalidRate)
+				require.Zero(t, imported)
+			},
+		},
+		{
+			name: "SameCurrency",
+			rates: []Rate{
+				{FromCurrency: utils.USD, ToCurrency: utils.USD, Rate: "1"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ImportFXRatesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, imported int, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			imported, err := ImportRates(context.Background(), store, "file", tc.rates)
+			tc.check(t, imported, err)
+		})
+	}
+}
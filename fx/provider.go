@@ -0,0 +1,21 @@
+package fx
+
+import (
+	"context"
+	"time"
+)
+
+// rate of one currency pair effective from the given instant
+type Rate struct {
+	FromCurrency  string    `json:"from_currency"`
+	ToCurrency    string    `json:"to_currency"`
+	Rate          string    `json:"rate"`
+	SpreadBps     *int32    `json:"spread_bps,omitempty"` // optional, DefaultSpreadBps if not set
+	EffectiveFrom time.Time `json:"effective_from"`       // optional, provider decides if not set
+}
+
+// source of fx rates, e.g. file on disk or market data feed
+type RateProvider interface {
+	Name() string
+	FetchRates(ctx context.Context) ([]Rate, error)
+}
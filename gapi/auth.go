@@ -2,11 +2,16 @@ package gapi
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 
+	"github.com/dxtym/bankrupt/authz"
+	db "github.com/dxtym/bankrupt/db/sqlc"
 	"github.com/dxtym/bankrupt/token"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -14,7 +19,8 @@ const (
 	authType   = "bearer"
 )
 
-func (s *Server) authorizeUser(ctx context.Context) (*token.Payload, error) {
+// authenticate the caller and check their role may call the operation
+func (s *Server) authorizeUser(ctx context.Context, op authz.Operation) (*token.Payload, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, fmt.Errorf("metadata is not provided")
@@ -35,10 +41,59 @@ func (s *Server) authorizeUser(ctx context.Context) (*token.Payload, error) {
 	}
 
 	accessToken := authFields[1]
-	payload, err := s.token.VerifyToken(accessToken)
+	payload, err := s.token.VerifyToken(accessToken, token.AccessToken)
 	if err != nil {
 		return nil, fmt.Errorf("invalid access token")
 	}
 
+	if err := s.policy.Authorize(payload, op); err != nil {
+		return nil, err
+	}
+
 	return payload, nil
 }
+
+// check the user may call the operation on a resource of owner
+func (s *Server) authorizeOwner(payload *token.Payload, op authz.Operation, owner string) error {
+	if err := s.policy.AuthorizeOwner(payload, op, owner); err != nil {
+		return status.Errorf(codes.PermissionDenied, "%v", err)
+	}
+	return nil
+}
+
+// get account and check the user may call the operation on it
+func (s *Server) authorizeAccount(ctx context.Context, payload *token.Payload, op authz.Operation, accountID int64) (db.Account, error) {
+	account, err := s.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return account, status.Errorf(codes.NotFound, "account not found: %v", err)
+		}
+		return account, status.Errorf(codes.Internal, "cannot get account: %v", err)
+	}
+
+	if err := s.authorizeOwner(payload, op, account.Owner); err != nil {
+		return account, err
+	}
+
+	return account, nil
+}
+
+// money movement can be limited to users who verified their email
+func (s *Server) requireVerifiedEmail(ctx context.Context, payload *token.Payload) error {
+	if !s.config.RequireVerifiedEmail {
+		return nil
+	}
+
+	user, err := s.store.GetUser(ctx, payload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return status.Errorf(codes.PermissionDenied, "user not found: %v", err)
+		}
+		return status.Errorf(codes.Internal, "cannot get user: %v", err)
+	}
+
+	if !user.IsEmailVerified {
+		return status.Errorf(codes.PermissionDenied, "email address is not verified")
+	}
+	return nil
+}
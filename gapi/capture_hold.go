@@ -0,0 +1,116 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/currency"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/limits"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/token"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) CaptureHold(ctx context.Context, req *pb.CaptureHoldRequest) (*pb.CaptureHoldResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.MoveMoney)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateCaptureHoldRequest(req, s.currencies)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// only the payee settles a hold
+	hold, err := s.getHold(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	toAccount, err := s.authorizeAccount(ctx, authPayload, authz.MoveMoney, hold.ToAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	idempotency, err := s.getIdempotency(ctx, authPayload.Username, req)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.CaptureHoldTxParams{
+		HoldID:      hold.ID,
+		Idempotency: idempotency,
+	}
+	if req.GetAmount() != nil {
+		arg.Amount = parseMoney(req.GetAmount())
+	}
+
+	result, err := s.store.CaptureHoldTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, limits.ErrExceeded) {
+			return nil, limitExceededError(err)
+		}
+		if errors.Is(err, db.ErrHoldNotActive) || errors.Is(err, db.ErrHoldExpired) || errors.Is(err, db.ErrHoldAmountExceeded) ||
+			errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrIdempotencyKeyReused) || errors.Is(err, utils.ErrCurrencyMismatch) {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot capture hold: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot capture hold: %v", err)
+	}
+
+	res := &pb.CaptureHoldResponse{
+		Hold:        convertHold(result.Hold),
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry, hold.Currency),
+		ToEntry:     convertEntry(result.ToEntry, toAccount.Currency),
+	}
+	return res, nil
+}
+
+func (s *Server) getHold(ctx context.Context, holdID int64) (db.Hold, error) {
+	hold, err := s.store.GetHold(ctx, holdID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return hold, status.Errorf(codes.NotFound, "hold not found: %v", err)
+		}
+		return hold, status.Errorf(codes.Internal, "cannot get hold: %v", err)
+	}
+	return hold, nil
+}
+
+// user must own either side of the hold
+func (s *Server) getOwnedHold(ctx context.Context, payload *token.Payload, holdID int64) (db.Hold, error) {
+	hold, err := s.getHold(ctx, holdID)
+	if err != nil {
+		return hold, err
+	}
+
+	if _, err := s.authorizeAccount(ctx, payload, authz.MoveMoney, hold.AccountID); err != nil {
+		if status.Code(err) != codes.PermissionDenied {
+			return hold, err
+		}
+		if _, err := s.authorizeAccount(ctx, payload, authz.MoveMoney, hold.ToAccountID); err != nil {
+			return hold, err
+		}
+	}
+	return hold, nil
+}
+
+func validateCaptureHoldRequest(req *pb.CaptureHoldRequest, currencies *currency.Registry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	if req.GetAmount() != nil {
+		violations = append(violations, validateMoney("amount", req.GetAmount(), currencies)...)
+	}
+	return
+}
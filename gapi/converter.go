@@ -1,8 +1,11 @@
 package gapi
 
 import (
+	"database/sql"
+
 	db "github.com/dxtym/bankrupt/db/sqlc"
 	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/utils"
 	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -14,6 +17,20 @@ func convertUser(user db.User) *pb.User {
 		Email:             user.Email,
 		PasswordChangedAt: timestamppb.New(user.PasswordChangedAt),
 		CreatedAt:         timestamppb.New(user.CreatedAt),
+		Tier:              user.Tier,
+		IsEmailVerified:   user.IsEmailVerified,
+	}
+}
+
+func convertSession(session db.Session) *pb.Session {
+	return &pb.Session{
+		Id:        convertUUID(session.ID),
+		Username:  session.Username,
+		UserAgent: session.UserAgent,
+		ClientIp:  session.ClientIp,
+		IsBlocked: session.IsBlocked,
+		ExpiresAt: timestamppb.New(session.ExpiresAt),
+		CreatedAt: timestamppb.New(session.CreatedAt),
 	}
 }
 
@@ -22,3 +39,213 @@ func convertUUID(uuid uuid.UUID) *pb.UUID {
 		Value: uuid.String(),
 	}
 }
+
+func convertAccount(account db.Account) *pb.Account {
+	return &pb.Account{
+		Id:        account.ID,
+		Owner:     account.Owner,
+		Balance:   convertMoney(account.BalanceMoney()),
+		Currency:  account.Currency,
+		CreatedAt: timestamppb.New(account.CreatedAt),
+	}
+}
+
+func convertTransfer(transfer db.Transfer) *pb.Transfer {
+	res := &pb.Transfer{
+		Id:             transfer.ID,
+		FromAccountId:  transfer.FromAccountID,
+		ToAccountId:    transfer.ToAccountID,
+		Amount:         convertMoney(utils.NewMoney(transfer.Amount, transfer.Currency)),
+		CreatedAt:      timestamppb.New(transfer.CreatedAt),
+		ReversedAmount: convertMoney(utils.NewMoney(transfer.ReversedAmount, transfer.Currency)),
+		ReversalStatus: transfer.ReversalStatus(),
+		Status:         transfer.Status,
+		FailureReason:  transfer.FailureReason.String,
+		Memo:           transfer.Memo,
+	}
+	if transfer.PostedAt.Valid {
+		res.PostedAt = timestamppb.New(transfer.PostedAt.Time)
+	}
+	if transfer.FailedAt.Valid {
+		res.FailedAt = timestamppb.New(transfer.FailedAt.Time)
+	}
+	if transfer.CancelledAt.Valid {
+		res.CancelledAt = timestamppb.New(transfer.CancelledAt.Time)
+	}
+	if transfer.ReversedAt.Valid {
+		res.ReversedAt = timestamppb.New(transfer.ReversedAt.Time)
+	}
+	if transfer.ReversalOf.Valid {
+		res.ReversalOf = transfer.ReversalOf.Int64
+	}
+	if transfer.ToAmount.Valid {
+		res.ConvertedAmount = convertMoney(utils.NewMoney(transfer.ToAmount.Int64, transfer.ToCurrency.String))
+		res.FxRate = transfer.FxRate.String
+		res.FxSpreadBps = transfer.FxSpreadBps.Int32
+	}
+	return res
+}
+
+func convertTransferLimit(limit db.TransferLimit) *pb.TransferLimit {
+	res := &pb.TransferLimit{
+		Id:        limit.ID,
+		Tier:      limit.Tier.String,
+		AccountId: limit.AccountID.Int64,
+		Currency:  limit.Currency.String,
+		UpdatedAt: timestamppb.New(limit.UpdatedAt),
+	}
+	amount := func(max sql.NullInt64) *string {
+		if !max.Valid {
+			return nil
+		}
+		decimal := utils.NewMoney(max.Int64, limit.Currency.String).Decimal()
+		return &decimal
+	}
+	res.MaxPerTransfer = amount(limit.MaxPerTransfer)
+	res.MaxDaily = amount(limit.MaxDaily)
+	res.MaxWeekly = amount(limit.MaxWeekly)
+	res.MaxMonthly = amount(limit.MaxMonthly)
+	if limit.MaxHourlyCount.Valid {
+		res.MaxHourlyCount = &limit.MaxHourlyCount.Int64
+	}
+	return res
+}
+
+func convertHold(hold db.Hold) *pb.Hold {
+	res := &pb.Hold{
+		Id:             hold.ID,
+		AccountId:      hold.AccountID,
+		ToAccountId:    hold.ToAccountID,
+		Amount:         convertMoney(utils.NewMoney(hold.Amount, hold.Currency)),
+		Status:         hold.Status,
+		CapturedAmount: convertMoney(utils.NewMoney(hold.CapturedAmount, hold.Currency)),
+		ExpiresAt:      timestamppb.New(hold.ExpiresAt),
+		CreatedAt:      timestamppb.New(hold.CreatedAt),
+	}
+	if hold.TransferID.Valid {
+		res.TransferId = hold.TransferID.Int64
+	}
+	return res
+}
+
+func convertScheduledTransfer(schedule db.ScheduledTransfer) *pb.ScheduledTransfer {
+	res := &pb.ScheduledTransfer{
+		Id:            schedule.ID,
+		FromAccountId: schedule.FromAccountID,
+		ToAccountId:   schedule.ToAccountID,
+		Amount:        convertMoney(utils.NewMoney(schedule.Amount, schedule.Currency)),
+		Schedule:      schedule.Schedule,
+		StartAt:       timestamppb.New(schedule.StartAt),
+		Status:        schedule.Status,
+		UpdatedAt:     timestamppb.New(schedule.UpdatedAt),
+		CreatedAt:     timestamppb.New(schedule.CreatedAt),
+	}
+	if schedule.EndAt.Valid {
+		res.EndAt = timestamppb.New(schedule.EndAt.Time)
+	}
+	if schedule.NextRunAt.Valid {
+		res.NextRunAt = timestamppb.New(schedule.NextRunAt.Time)
+	}
+	return res
+}
+
+func convertScheduledTransferRun(run db.ScheduledTransferRun) *pb.ScheduledTransferRun {
+	return &pb.ScheduledTransferRun{
+		Id:            run.ID,
+		ScheduledFor:  timestamppb.New(run.ScheduledFor),
+		Status:        run.Status,
+		TransferId:    run.TransferID.Int64,
+		FailureReason: run.FailureReason.String,
+		CreatedAt:     timestamppb.New(run.CreatedAt),
+	}
+}
+
+func convertEntry(entry db.Entry, currency string) *pb.Entry {
+	return &pb.Entry{
+		Id:           entry.ID,
+		AccountId:    entry.AccountID,
+		Amount:       convertMoney(utils.NewMoney(entry.Amount, currency)),
+		CreatedAt:    timestamppb.New(entry.CreatedAt),
+		BalanceAfter: convertMoney(utils.NewMoney(entry.BalanceAfter, currency)),
+		TransferId:   entry.TransferID.Int64,
+	}
+}
+
+func convertStatementEntry(entry db.ListStatementEntriesRow, currency string) *pb.StatementEntry {
+	return &pb.StatementEntry{
+		Id:                    entry.ID,
+		Amount:                convertMoney(utils.NewMoney(entry.Amount, currency)),
+		BalanceAfter:          convertMoney(utils.NewMoney(entry.BalanceAfter, currency)),
+		TransferId:            entry.TransferID.Int64,
+		CounterpartyAccountId: entry.CounterpartyAccountID,
+		CreatedAt:             timestamppb.New(entry.CreatedAt),
+	}
+}
+
+func convertStatement(st db.Statement, currency string) *pb.Statement {
+	res := &pb.Statement{
+		Id:             st.ID,
+		AccountId:      st.AccountID,
+		PeriodStart:    timestamppb.New(st.PeriodStart),
+		PeriodEnd:      timestamppb.New(st.PeriodEnd),
+		OpeningBalance: convertMoney(utils.NewMoney(st.OpeningBalance, currency)),
+		ClosingBalance: convertMoney(utils.NewMoney(st.ClosingBalance, currency)),
+		EntryCount:     st.EntryCount,
+		CreatedAt:      timestamppb.New(st.CreatedAt),
+	}
+	if st.EmailedAt.Valid {
+		res.EmailedAt = timestamppb.New(st.EmailedAt.Time)
+	}
+	return res
+}
+
+func convertCurrency(currency db.Currency) *pb.Currency {
+	res := &pb.Currency{
+		Code:              currency.Code,
+		Name:              currency.Name,
+		Exponent:          currency.Exponent,
+		Enabled:           currency.Enabled,
+		MinTransferAmount: convertMoney(utils.NewMoney(currency.MinTransferAmount, currency.Code)),
+		UpdatedAt:         timestamppb.New(currency.UpdatedAt),
+		CreatedAt:         timestamppb.New(currency.CreatedAt),
+	}
+	if currency.MaxTransferAmount.Valid {
+		res.MaxTransferAmount = convertMoney(utils.NewMoney(currency.MaxTransferAmount.Int64, currency.Code))
+	}
+	return res
+}
+
+func convertFXRate(rate db.FxRate) *pb.FXRate {
+	return &pb.FXRate{
+		FromCurrency:  rate.FromCurrency,
+		ToCurrency:    rate.ToCurrency,
+		Rate:          rate.Rate,
+		SpreadBps:     rate.SpreadBps,
+		EffectiveFrom: timestamppb.New(rate.EffectiveFrom),
+		Source:        rate.Source,
+	}
+}
+
+func convertFXQuote(quote db.FxQuote) *pb.FXQuote {
+	return &pb.FXQuote{
+		Id:              quote.ID.String(),
+		Amount:          convertMoney(utils.NewMoney(quote.FromAmount, quote.FromCurrency)),
+		ConvertedAmount: convertMoney(utils.NewMoney(quote.ToAmount, quote.ToCurrency)),
+		Rate:            quote.Rate,
+		SpreadBps:       quote.SpreadBps,
+		ExpiresAt:       timestamppb.New(quote.ExpiresAt),
+	}
+}
+
+func convertMoney(money utils.Money) *pb.Money {
+	return &pb.Money{
+		Amount:   money.Decimal(),
+		Currency: money.Currency,
+	}
+}
+
+// request money is validated beforehand
+func parseMoney(money *pb.Money) utils.Money {
+	result, _ := utils.ParseMoney(money.GetAmount(), money.GetCurrency())
+	return result
+}
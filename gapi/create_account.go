@@ -0,0 +1,73 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/currency"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/valid"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.CreateAccount)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateCreateAccountRequest(req, s.currencies)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	idempotency, err := s.getIdempotency(ctx, authPayload.Username, req)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.CreateAccountTxParams{
+		CreateAccountParams: db.CreateAccountParams{
+			Owner:    authPayload.Username,
+			Balance:  0,
+			Currency: req.GetCurrency(),
+		},
+		Idempotency: idempotency,
+	}
+
+	txResult, err := s.store.CreateAccountTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrIdempotencyKeyReused) {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot create the account: %v", err)
+		}
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				if pqErr.Constraint == "accounts_currency_fkey" {
+					return nil, status.Errorf(codes.InvalidArgument, "unsupported currency: %v", err)
+				}
+				return nil, status.Errorf(codes.PermissionDenied, "owner doesn't exist: %v", err)
+			case "unique_violation":
+				return nil, status.Errorf(codes.AlreadyExists, "account in this currency already exists: %v", err)
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "cannot create the account: %v", err)
+	}
+
+	res := &pb.CreateAccountResponse{
+		Account: convertAccount(txResult.Account),
+	}
+	return res, nil
+}
+
+func validateCreateAccountRequest(req *pb.CreateAccountRequest, currencies *currency.Registry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateCurrency(req.GetCurrency(), currencies.Supported); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+	return
+}
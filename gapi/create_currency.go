@@ -0,0 +1,98 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dxtym/bankrupt/authz"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/valid"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) CreateCurrency(ctx context.Context, req *pb.CreateCurrencyRequest) (*pb.CreateCurrencyResponse, error) {
+	_, err := s.authorizeUser(ctx, authz.ManageRates)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateCreateCurrencyRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	arg := db.CreateCurrencyParams{
+		Code:              req.GetCode(),
+		Name:              req.GetName(),
+		Exponent:          req.GetExponent(),
+		Enabled:           req.Enabled == nil || req.GetEnabled(),
+		MinTransferAmount: 1,
+	}
+	if req.MinTransferAmount != nil {
+		arg.MinTransferAmount, _ = utils.ParseAmount(req.GetMinTransferAmount(), int(req.GetExponent()))
+	}
+	if req.MaxTransferAmount != nil {
+		maxAmount, _ := utils.ParseAmount(req.GetMaxTransferAmount(), int(req.GetExponent()))
+		arg.MaxTransferAmount = sql.NullInt64{Int64: maxAmount, Valid: true}
+	}
+
+	currency, err := s.store.CreateCurrency(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "unique_violation":
+				return nil, status.Errorf(codes.AlreadyExists, "currency already exists: %v", err)
+			case "check_violation":
+				return nil, status.Errorf(codes.InvalidArgument, "invalid transfer limits: %v", err)
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "cannot create currency: %v", err)
+	}
+
+	s.currencies.Invalidate()
+
+	res := &pb.CreateCurrencyResponse{
+		Currency: convertCurrency(currency),
+	}
+	return res, nil
+}
+
+func validateCreateCurrencyRequest(req *pb.CreateCurrencyRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateCurrencyCode(req.GetCode()); err != nil {
+		violations = append(violations, fieldViolation("code", err))
+	}
+	if err := valid.ValidateString(req.GetName(), 1, 64); err != nil {
+		violations = append(violations, fieldViolation("name", err))
+	}
+	if err := valid.ValidateCurrencyExponent(req.GetExponent()); err != nil {
+		return append(violations, fieldViolation("exponent", err))
+	}
+	if req.MinTransferAmount != nil {
+		if err := validateTransferLimit(req.GetMinTransferAmount(), req.GetExponent()); err != nil {
+			violations = append(violations, fieldViolation("min_transfer_amount", err))
+		}
+	}
+	if req.MaxTransferAmount != nil {
+		if err := validateTransferLimit(req.GetMaxTransferAmount(), req.GetExponent()); err != nil {
+			violations = append(violations, fieldViolation("max_transfer_amount", err))
+		}
+	}
+	return
+}
+
+// transfer limit is positive decimal amount in currency minor units
+func validateTransferLimit(value string, exponent int32) error {
+	amount, err := utils.ParseAmount(value, int(exponent))
+	if err != nil {
+		return err
+	}
+	if amount <= 0 {
+		return utils.ErrInvalidAmount
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/currency"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/valid"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) CreateFXQuote(ctx context.Context, req *pb.CreateFXQuoteRequest) (*pb.CreateFXQuoteResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.MoveMoney)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateCreateFXQuoteRequest(req, s.currencies)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	amount := parseMoney(req.GetAmount())
+	rate, err := s.store.GetFXRate(ctx, db.GetFXRateParams{
+		FromCurrency: amount.Currency,
+		ToCurrency:   req.GetToCurrency(),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "no rate for %s to %s: %v", amount.Currency, req.GetToCurrency(), err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get rate: %v", err)
+	}
+
+	appliedRate, err := utils.ApplySpread(rate.Rate, rate.SpreadBps)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot apply spread: %v", err)
+	}
+
+	converted, err := utils.ConvertMoney(amount, req.GetToCurrency(), appliedRate)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot convert amount: %v", err)
+	}
+	if !converted.IsPositive() {
+		return nil, status.Errorf(codes.InvalidArgument, "amount is too small to convert")
+	}
+
+	quote, err := s.store.CreateFXQuote(ctx, db.CreateFXQuoteParams{
+		ID:           uuid.New(),
+		Username:     authPayload.Username,
+		FromCurrency: amount.Currency,
+		ToCurrency:   converted.Currency,
+		FromAmount:   amount.Amount,
+		ToAmount:     converted.Amount,
+		Rate:         appliedRate,
+		SpreadBps:    rate.SpreadBps,
+		ExpiresAt:    time.Now().Add(s.config.FXQuoteTTL),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create quote: %v", err)
+	}
+
+	res := &pb.CreateFXQuoteResponse{
+		Quote: convertFXQuote(quote),
+	}
+	return res, nil
+}
+
+func validateCreateFXQuoteRequest(req *pb.CreateFXQuoteRequest, currencies *currency.Registry) (violations []*errdetails.BadRequest_FieldViolation) {
+	violations = append(violations, validateMoney("amount", req.GetAmount(), currencies)...)
+	if err := valid.ValidateCurrency(req.GetToCurrency(), currencies.Supported); err != nil {
+		violations = append(violations, fieldViolation("to_currency", err))
+	}
+	if req.GetToCurrency() == req.GetAmount().GetCurrency() {
+		violations = append(violations, fieldViolation("to_currency", fmt.Errorf("must differ from amount currency")))
+	}
+	return
+}
@@ -0,0 +1,97 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/currency"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) CreateHold(ctx context.Context, req *pb.CreateHoldRequest) (*pb.CreateHoldResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.MoveMoney)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateCreateHoldRequest(req, s.currencies)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if err := s.requireVerifiedEmail(ctx, authPayload); err != nil {
+		return nil, err
+	}
+
+	account, err := s.validateAccount(ctx, req.GetAccountId(), req.GetAmount().GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeOwner(authPayload, authz.MoveMoney, account.Owner); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.validateAccount(ctx, req.GetToAccountId(), req.GetAmount().GetCurrency()); err != nil {
+		return nil, err
+	}
+
+	idempotency, err := s.getIdempotency(ctx, authPayload.Username, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.store.CreateHoldTx(ctx, db.CreateHoldTxParams{
+		AccountID:   req.GetAccountId(),
+		ToAccountID: req.GetToAccountId(),
+		Amount:      parseMoney(req.GetAmount()),
+		ExpiresAt:   time.Now().Add(s.config.HoldTTL),
+		Idempotency: idempotency,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrSystemAccount) ||
+			errors.Is(err, db.ErrIdempotencyKeyReused) || errors.Is(err, utils.ErrCurrencyMismatch) {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot create hold: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot create hold: %v", err)
+	}
+
+	held, err := s.store.GetHeldAmount(ctx, result.Account.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get held amount: %v", err)
+	}
+
+	res := &pb.CreateHoldResponse{
+		Hold:             convertHold(result.Hold),
+		AvailableBalance: convertMoney(result.Account.AvailableMoney(held)),
+	}
+	return res, nil
+}
+
+func validateCreateHoldRequest(req *pb.CreateHoldRequest, currencies *currency.Registry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+	if err := valid.ValidateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	}
+	if req.GetAccountId() == req.GetToAccountId() {
+		violations = append(violations, fieldViolation("to_account_id", fmt.Errorf("cannot hold for the same account")))
+	}
+	if moneyViolations := validateMoney("amount", req.GetAmount(), currencies); len(moneyViolations) > 0 {
+		return append(violations, moneyViolations...)
+	}
+	if err := currencies.CheckTransferAmount(parseMoney(req.GetAmount())); err != nil {
+		violations = append(violations, fieldViolation("amount.amount", err))
+	}
+	return
+}
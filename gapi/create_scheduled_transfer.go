@@ -0,0 +1,151 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/currency"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/token"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) CreateScheduledTransfer(ctx context.Context, req *pb.CreateScheduledTransferRequest) (*pb.CreateScheduledTransferResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.MoveMoney)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateCreateScheduledTransferRequest(req, s.currencies)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if err := s.requireVerifiedEmail(ctx, authPayload); err != nil {
+		return nil, err
+	}
+
+	fromAccount, err := s.validateAccount(ctx, req.GetFromAccountId(), req.GetAmount().GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeOwner(authPayload, authz.MoveMoney, fromAccount.Owner); err != nil {
+		return nil, err
+	}
+
+	toAccount, err := s.validateAccount(ctx, req.GetToAccountId(), req.GetAmount().GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if toAccount.IsSystem() {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot transfer to system account")
+	}
+
+	startAt := time.Now()
+	if req.StartAt != nil {
+		startAt = req.GetStartAt().AsTime()
+	}
+	var endAt sql.NullTime
+	if req.EndAt != nil {
+		endAt = sql.NullTime{Time: req.GetEndAt().AsTime(), Valid: true}
+	}
+
+	nextRunAt, err := firstRun(req.GetSchedule(), startAt, endAt)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.store.CreateScheduledTransfer(ctx, db.CreateScheduledTransferParams{
+		Owner:         authPayload.Username,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        parseMoney(req.GetAmount()).Amount,
+		Currency:      fromAccount.Currency,
+		Schedule:      req.GetSchedule(),
+		StartAt:       startAt,
+		EndAt:         endAt,
+		NextRunAt:     sql.NullTime{Time: nextRunAt, Valid: true},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create scheduled transfer: %v", err)
+	}
+
+	res := &pb.CreateScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(schedule),
+	}
+	return res, nil
+}
+
+// first run at or after the later of start and now
+func firstRun(spec string, startAt time.Time, endAt sql.NullTime) (time.Time, error) {
+	after := startAt.Add(-time.Second)
+	if now := time.Now(); now.After(after) {
+		after = now
+	}
+	return nextRun(spec, after, endAt)
+}
+
+// next run strictly after the given time, even if it is already due
+func nextRun(spec string, after time.Time, endAt sql.NullTime) (time.Time, error) {
+	next, err := utils.NextRun(spec, after, endAt.Time)
+	if err != nil {
+		return next, status.Errorf(codes.Internal, "cannot compute next run: %v", err)
+	}
+	if next.IsZero() {
+		return next, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			fieldViolation("end_at", fmt.Errorf("schedule has no runs before end_at")),
+		})
+	}
+	return next, nil
+}
+
+func (s *Server) getOwnedScheduledTransfer(ctx context.Context, payload *token.Payload, op authz.Operation, id int64) (db.ScheduledTransfer, error) {
+	schedule, err := s.store.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return schedule, status.Errorf(codes.NotFound, "scheduled transfer not found: %v", err)
+		}
+		return schedule, status.Errorf(codes.Internal, "cannot get scheduled transfer: %v", err)
+	}
+
+	if err := s.authorizeOwner(payload, op, schedule.Owner); err != nil {
+		return schedule, err
+	}
+
+	return schedule, nil
+}
+
+func validateCreateScheduledTransferRequest(req *pb.CreateScheduledTransferRequest, currencies *currency.Registry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+	if err := valid.ValidateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	}
+	if req.GetFromAccountId() == req.GetToAccountId() {
+		violations = append(violations, fieldViolation("to_account_id", fmt.Errorf("cannot transfer to the same account")))
+	}
+	if err := valid.ValidateSchedule(req.GetSchedule()); err != nil {
+		violations = append(violations, fieldViolation("schedule", err))
+	}
+	if req.StartAt != nil && req.EndAt != nil && !req.GetEndAt().AsTime().After(req.GetStartAt().AsTime()) {
+		violations = append(violations, fieldViolation("end_at", fmt.Errorf("must be after start_at")))
+	}
+	if moneyViolations := validateMoney("amount", req.GetAmount(), currencies); len(moneyViolations) > 0 {
+		return append(violations, moneyViolations...)
+	}
+	if err := currencies.CheckTransferAmount(parseMoney(req.GetAmount())); err != nil {
+		violations = append(violations, fieldViolation("amount.amount", err))
+	}
+	return
+}
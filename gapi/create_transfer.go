@@ -0,0 +1,180 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/currency"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/limits"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/valid"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) CreateTransfer(ctx context.Context, req *pb.CreateTransferRequest) (*pb.CreateTransferResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.MoveMoney)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateCreateTransferRequest(req, s.currencies)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if err := s.requireVerifiedEmail(ctx, authPayload); err != nil {
+		return nil, err
+	}
+
+	fromAccount, err := s.validateAccount(ctx, req.GetFromAccountId(), req.GetAmount().GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeOwner(authPayload, authz.MoveMoney, fromAccount.Owner); err != nil {
+		return nil, err
+	}
+
+	// quoted transfers convert into the currency of to account
+	var toAccount db.Account
+	if req.GetFxQuoteId() == "" {
+		toAccount, err = s.validateAccount(ctx, req.GetToAccountId(), req.GetAmount().GetCurrency())
+	} else {
+		toAccount, err = s.getAccount(ctx, req.GetToAccountId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if toAccount.IsSystem() {
+		return nil, status.Errorf(codes.PermissionDenied, "cannot transfer to system account")
+	}
+
+	idempotency, err := s.getIdempotency(ctx, authPayload.Username, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var result db.TransferTxResult
+	if req.GetFxQuoteId() == "" {
+		result, err = s.store.TransferTx(ctx, db.TransferTxParams{
+			FromAccountId: req.GetFromAccountId(),
+			ToAccountId:   req.GetToAccountId(),
+			Amount:        parseMoney(req.GetAmount()),
+			Memo:          req.GetMemo(),
+			Idempotency:   idempotency,
+		})
+	} else {
+		var fxResult db.FXTransferTxResult
+		fxResult, err = s.store.FXTransferTx(ctx, db.FXTransferTxParams{
+			FromAccountId: req.GetFromAccountId(),
+			ToAccountId:   req.GetToAccountId(),
+			Amount:        parseMoney(req.GetAmount()),
+			QuoteID:       uuid.MustParse(req.GetFxQuoteId()),
+			Username:      authPayload.Username,
+			Memo:          req.GetMemo(),
+			Idempotency:   idempotency,
+		})
+		result = fxResult.TransferTxResult
+	}
+	if err != nil {
+		if errors.Is(err, db.ErrFXQuoteNotFound) {
+			return nil, status.Errorf(codes.NotFound, "cannot transfer money: %v", err)
+		}
+		if errors.Is(err, limits.ErrExceeded) {
+			return nil, limitExceededError(err)
+		}
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrIdempotencyKeyReused) || errors.Is(err, utils.ErrCurrencyMismatch) ||
+			errors.Is(err, db.ErrFXQuoteExpired) || errors.Is(err, db.ErrFXQuoteUsed) || errors.Is(err, db.ErrFXQuoteMismatch) {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot transfer money: %v", err)
+		}
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				return nil, status.Errorf(codes.FailedPrecondition, "account no longer exists: %v", err)
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "cannot transfer money: %v", err)
+	}
+
+	res := &pb.CreateTransferResponse{
+		Transfer:    convertTransfer(result.Transfer),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry, fromAccount.Currency),
+		ToEntry:     convertEntry(result.ToEntry, toAccount.Currency),
+	}
+	return res, nil
+}
+
+// check account exists and holds money in the given currency
+func (s *Server) validateAccount(ctx context.Context, accountID int64, currency string) (db.Account, error) {
+	account, err := s.getAccount(ctx, accountID)
+	if err != nil {
+		return account, err
+	}
+
+	if account.Currency != currency {
+		err := fmt.Errorf("account [%d] mismatch %s vs %s", accountID, account.Currency, currency)
+		return account, status.Errorf(codes.FailedPrecondition, "currency mismatch: %v", err)
+	}
+
+	return account, nil
+}
+
+func (s *Server) getAccount(ctx context.Context, accountID int64) (db.Account, error) {
+	account, err := s.store.GetAccount(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return account, status.Errorf(codes.NotFound, "account [%d] not found: %v", accountID, err)
+		}
+		return account, status.Errorf(codes.Internal, "cannot get account: %v", err)
+	}
+	return account, nil
+}
+
+func validateCreateTransferRequest(req *pb.CreateTransferRequest, currencies *currency.Registry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+	if err := valid.ValidateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	}
+	if req.GetFromAccountId() == req.GetToAccountId() {
+		violations = append(violations, fieldViolation("to_account_id", fmt.Errorf("cannot transfer to the same account")))
+	}
+	if req.GetFxQuoteId() != "" {
+		if _, err := uuid.Parse(req.GetFxQuoteId()); err != nil {
+			violations = append(violations, fieldViolation("fx_quote_id", err))
+		}
+	}
+	if err := valid.ValidateMemo(req.GetMemo()); err != nil {
+		violations = append(violations, fieldViolation("memo", err))
+	}
+	if moneyViolations := validateMoney("amount", req.GetAmount(), currencies); len(moneyViolations) > 0 {
+		return append(violations, moneyViolations...)
+	}
+	if err := currencies.CheckTransferAmount(parseMoney(req.GetAmount())); err != nil {
+		violations = append(violations, fieldViolation("amount.amount", err))
+	}
+	return
+}
+
+func validateMoney(field string, money *pb.Money, currencies *currency.Registry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateCurrency(money.GetCurrency(), currencies.Supported); err != nil {
+		return append(violations, fieldViolation(field+".currency", err))
+	}
+	if err := valid.ValidateAmount(money.GetAmount(), money.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation(field+".amount", err))
+	}
+	return
+}
@@ -0,0 +1,176 @@
+package gapi
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/batch"
+	"github.com/dxtym/bankrupt/currency"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/limits"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *Server) CreateTransferBatch(req *pb.CreateTransferBatchRequest, stream pb.Bankrupt_CreateTransferBatchServer) error {
+	ctx := stream.Context()
+	authPayload, err := s.authorizeUser(ctx, authz.MoveMoney)
+	if err != nil {
+		return authorizationError(err)
+	}
+
+	violations := validateCreateTransferBatchRequest(req, s.currencies)
+	if len(violations) > 0 {
+		return invalidArgumentError(violations)
+	}
+
+	if err := s.requireVerifiedEmail(ctx, authPayload); err != nil {
+		return err
+	}
+
+	fromAccount, err := s.authorizeAccount(ctx, authPayload, authz.MoveMoney, req.GetFromAccountId())
+	if err != nil {
+		return err
+	}
+
+	legs := make([]db.TransferBatchLeg, len(req.GetLegs()))
+	for i, leg := range req.GetLegs() {
+		if leg.GetAmount().GetCurrency() != fromAccount.Currency {
+			err := fmt.Errorf("leg [%d] mismatch %s vs %s", i, fromAccount.Currency, leg.GetAmount().GetCurrency())
+			return status.Errorf(codes.FailedPrecondition, "currency mismatch: %v", err)
+		}
+		legs[i] = db.TransferBatchLeg{
+			ToAccountId: leg.GetToAccountId(),
+			Amount:      parseMoney(leg.GetAmount()),
+			Memo:        leg.GetMemo(),
+		}
+	}
+
+	idempotency, err := s.getIdempotency(ctx, authPayload.Username, req)
+	if err != nil {
+		return err
+	}
+
+	mode := req.GetMode()
+	if mode == "" {
+		mode = db.BatchAtomic
+	}
+
+	_, err = batch.Run(ctx, s.store, batch.Params{
+		FromAccountID: fromAccount.ID,
+		Owner:         fromAccount.Owner,
+		Legs:          legs,
+		Mode:          mode,
+		ChunkSize:     int(req.GetChunkSize()),
+		Idempotency:   idempotency,
+	}, func(progress batch.Progress) error {
+		return stream.Send(convertBatchProgress(progress))
+	})
+	if err != nil {
+		return batchError(err)
+	}
+	return nil
+}
+
+// atomic batches fail with the first failed leg
+func batchError(err error) error {
+	if errors.Is(err, limits.ErrExceeded) {
+		return limitExceededError(err)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Errorf(codes.NotFound, "cannot transfer batch: %v", err)
+	}
+	if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrSystemAccount) || errors.Is(err, utils.ErrCurrencyMismatch) ||
+		errors.Is(err, db.ErrIdempotencyKeyReused) {
+		return status.Errorf(codes.FailedPrecondition, "cannot transfer batch: %v", err)
+	}
+	if errors.Is(err, db.ErrBatchConflict) {
+		return status.Errorf(codes.Aborted, "cannot transfer batch: %v", err)
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Errorf(codes.Internal, "cannot transfer batch: %v", err)
+}
+
+func convertBatchProgress(progress batch.Progress) *pb.CreateTransferBatchResponse {
+	res := &pb.CreateTransferBatchResponse{
+		Batch:     convertTransferBatch(progress.Batch),
+		Processed: int32(progress.Processed),
+		Posted:    int32(progress.Posted),
+		Failed:    int32(progress.Failed),
+		Results:   make([]*pb.TransferBatchLegResult, 0, len(progress.Results)),
+	}
+	for _, result := range progress.Results {
+		legResult := &pb.TransferBatchLegResult{Index: int32(result.Index)}
+		if result.Err != nil {
+			legResult.Error = result.Err.Error()
+		} else {
+			legResult.Transfer = convertTransfer(result.Transfer)
+		}
+		res.Results = append(res.Results, legResult)
+	}
+	return res
+}
+
+func convertTransferBatch(transferBatch db.TransferBatch) *pb.TransferBatch {
+	res := &pb.TransferBatch{
+		Id:            transferBatch.ID,
+		FromAccountId: transferBatch.FromAccountID,
+		Mode:          transferBatch.Mode,
+		Status:        transferBatch.Status,
+		LegCount:      transferBatch.LegCount,
+		PostedCount:   transferBatch.PostedCount,
+		FailedCount:   transferBatch.FailedCount,
+		CreatedAt:     timestamppb.New(transferBatch.CreatedAt),
+	}
+	if transferBatch.FinishedAt.Valid {
+		res.FinishedAt = timestamppb.New(transferBatch.FinishedAt.Time)
+	}
+	return res
+}
+
+func validateCreateTransferBatchRequest(req *pb.CreateTransferBatchRequest, currencies *currency.Registry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+	switch req.GetMode() {
+	case "", db.BatchAtomic, db.BatchChunked:
+	default:
+		violations = append(violations, fieldViolation("mode", fmt.Errorf("must be atomic or chunked")))
+	}
+	if req.GetChunkSize() < 0 || req.GetChunkSize() > batch.MaxChunkSize {
+		violations = append(violations, fieldViolation("chunk_size", fmt.Errorf("should be between 1 and %d", batch.MaxChunkSize)))
+	}
+	if n := len(req.GetLegs()); n < 1 || n > batch.MaxLegs {
+		return append(violations, fieldViolation("legs", fmt.Errorf("should have 1-%d legs", batch.MaxLegs)))
+	}
+
+	for i, leg := range req.GetLegs() {
+		field := fmt.Sprintf("legs[%d]", i)
+		if err := valid.ValidateID(leg.GetToAccountId()); err != nil {
+			violations = append(violations, fieldViolation(field+".to_account_id", err))
+		}
+		if leg.GetToAccountId() == req.GetFromAccountId() {
+			violations = append(violations, fieldViolation(field+".to_account_id", fmt.Errorf("cannot transfer to the same account")))
+		}
+		if err := valid.ValidateMemo(leg.GetMemo()); err != nil {
+			violations = append(violations, fieldViolation(field+".memo", err))
+		}
+		if moneyViolations := validateMoney(field+".amount", leg.GetAmount(), currencies); len(moneyViolations) > 0 {
+			violations = append(violations, moneyViolations...)
+			continue
+		}
+		if err := currencies.CheckTransferAmount(parseMoney(leg.GetAmount())); err != nil {
+			violations = append(violations, fieldViolation(field+".amount.amount", err))
+		}
+	}
+	return
+}
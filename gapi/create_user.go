@@ -2,14 +2,12 @@ package gapi
 
 import (
 	"context"
-	"time"
 
 	db "github.com/dxtym/bankrupt/db/sqlc"
 	"github.com/dxtym/bankrupt/pb"
 	"github.com/dxtym/bankrupt/utils"
 	"github.com/dxtym/bankrupt/valid"
 	"github.com/dxtym/bankrupt/worker"
-	"github.com/hibiken/asynq"
 	"github.com/lib/pq"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
@@ -26,7 +24,7 @@ func (s *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb
 		return nil, status.Errorf(codes.Internal, "user not authorized: %v", err)
 	}
 
-	// run redis queue and create user in one transaction
+	// verification email goes through the outbox, so it is only sent if the user is committed
 	arg := db.CreateUserTxParams{
 		CreateUserParams: db.CreateUserParams{
 			Username:       req.GetUsername(),
@@ -34,17 +32,7 @@ func (s *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb
 			FullName:       req.GetFullName(),
 			Email:          req.GetEmail(),
 		},
-		AfterCreate: func(user db.User) error {
-			taskPayload := &worker.PayloadSendEmail{
-				Username: user.Username,
-			}
-			opts := []asynq.Option{
-				asynq.MaxRetry(10),
-				asynq.Timeout(10 * time.Second),
-				asynq.Queue(worker.QueueCritical),
-			}
-			return s.taskDistributor.DistributorTaskSendEmail(ctx, *taskPayload, opts...)
-		},
+		Outbox: []db.OutboxTask{worker.VerifyEmailTask(req.GetUsername())},
 	}
 
 	txResult, err := s.store.CreateUserTx(ctx, arg)
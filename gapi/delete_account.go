@@ -0,0 +1,54 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/valid"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) DeleteAccount(ctx context.Context, req *pb.DeleteAccountRequest) (*pb.DeleteAccountResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.DeleteAccount)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateDeleteAccountRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := s.authorizeAccount(ctx, authPayload, authz.DeleteAccount, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	// money can't just disappear with the account
+	if account.Balance != 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "account balance must be zero")
+	}
+
+	if err := s.store.DeleteAccount(ctx, account.ID); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				return nil, status.Errorf(codes.FailedPrecondition, "account has transaction history: %v", err)
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "cannot delete the account: %v", err)
+	}
+
+	return &pb.DeleteAccountResponse{}, nil
+}
+
+func validateDeleteAccountRequest(req *pb.DeleteAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dxtym/bankrupt/authz"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (s *Server) DeleteScheduledTransfer(ctx context.Context, req *pb.DeleteScheduledTransferRequest) (*pb.DeleteScheduledTransferResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.MoveMoney)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateDeleteScheduledTransferRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	schedule, err := s.getOwnedScheduledTransfer(ctx, authPayload, authz.MoveMoney, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	// cancelled rather than deleted so past runs keep their schedule
+	schedule, err = s.store.UpdateScheduledTransferTx(ctx, db.UpdateScheduledTransferTxParams{
+		ID: schedule.ID,
+		Update: func(schedule db.ScheduledTransfer) (db.UpdateScheduledTransferParams, error) {
+			return db.UpdateScheduledTransferParams{
+				Amount:    schedule.Amount,
+				Schedule:  schedule.Schedule,
+				EndAt:     schedule.EndAt,
+				NextRunAt: sql.NullTime{},
+				Status:    db.ScheduleCancelled,
+			}, nil
+		},
+	})
+	if err != nil {
+		return nil, scheduledTransferUpdateError(err)
+	}
+
+	res := &pb.DeleteScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(schedule),
+	}
+	return res, nil
+}
+
+func validateDeleteScheduledTransferRequest(req *pb.DeleteScheduledTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return
+}
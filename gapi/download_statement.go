@@ -0,0 +1,67 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/statement"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) DownloadStatement(ctx context.Context, req *pb.DownloadStatementRequest) (*pb.DownloadStatementResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.ReadAccount)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateDownloadStatementRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	record, err := s.store.GetStatement(ctx, req.GetId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "statement not found: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get statement: %v", err)
+	}
+
+	account, err := s.authorizeAccount(ctx, authPayload, authz.ReadAccount, record.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ledger is append only, so rendering again gives the issued statement
+	st, err := statement.Build(ctx, s.store, account, record.PeriodStart, record.PeriodEnd)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot build statement: %v", err)
+	}
+
+	doc, err := statement.Render(st, req.GetFormat())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot render statement: %v", err)
+	}
+
+	return &pb.DownloadStatementResponse{
+		Filename:    doc.Filename,
+		ContentType: doc.ContentType,
+		Content:     doc.Content,
+	}, nil
+}
+
+func validateDownloadStatementRequest(req *pb.DownloadStatementRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	if !statement.IsFormat(req.GetFormat()) {
+		violations = append(violations, fieldViolation("format", fmt.Errorf("must be %s or %s", statement.FormatCSV, statement.FormatPDF)))
+	}
+	return
+}
@@ -1,6 +1,11 @@
 package gapi
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/limits"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -24,5 +29,30 @@ func invalidArgumentError(violations []*errdetails.BadRequest_FieldViolation) er
 }
 
 func authorizationError(err error) error {
+	if errors.Is(err, authz.ErrForbidden) {
+		return status.Errorf(codes.PermissionDenied, "authorization failed: %s", err.Error())
+	}
 	return status.Errorf(codes.Unauthenticated, "authorization failed: %s", err.Error())
 }
+
+// reports the violated limit with the allowance left as quota failure
+func limitExceededError(err error) error {
+	statusExhausted := status.New(codes.ResourceExhausted, fmt.Sprintf("cannot transfer money: %v", err))
+
+	var exceeded *limits.ExceededError
+	if !errors.As(err, &exceeded) {
+		return statusExhausted.Err()
+	}
+
+	quotaFailure := &errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{{
+			Subject:     fmt.Sprintf("%s:%s:%s", exceeded.Scope, exceeded.Limit, exceeded.Currency),
+			Description: exceeded.Error(),
+		}},
+	}
+	status, err := statusExhausted.WithDetails(quotaFailure)
+	if err != nil {
+		return statusExhausted.Err()
+	}
+	return status.Err()
+}
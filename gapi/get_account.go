@@ -0,0 +1,47 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) GetAccount(ctx context.Context, req *pb.GetAccountRequest) (*pb.GetAccountResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.ReadAccount)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateGetAccountRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := s.authorizeAccount(ctx, authPayload, authz.ReadAccount, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	held, err := s.store.GetHeldAmount(ctx, account.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get held amount: %v", err)
+	}
+
+	res := &pb.GetAccountResponse{
+		Account:          convertAccount(account),
+		AvailableBalance: convertMoney(account.AvailableMoney(held)),
+	}
+	return res, nil
+}
+
+func validateGetAccountRequest(req *pb.GetAccountRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return
+}
@@ -0,0 +1,67 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dxtym/bankrupt/authz"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) GetFXRate(ctx context.Context, req *pb.GetFXRateRequest) (*pb.GetFXRateResponse, error) {
+	if _, err := s.authorizeUser(ctx, authz.ReadRates); err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateGetFXRateRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	arg := db.GetFXRateAtParams{
+		FromCurrency: req.GetFromCurrency(),
+		ToCurrency:   req.GetToCurrency(),
+		At:           time.Now(),
+	}
+	if req.At != nil {
+		arg.At = req.GetAt().AsTime()
+	}
+
+	rate, err := s.store.GetFXRateAt(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "no rate for %s to %s at %s: %v", arg.FromCurrency, arg.ToCurrency, arg.At.Format(time.RFC3339), err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get rate: %v", err)
+	}
+
+	res := &pb.GetFXRateResponse{
+		Rate: convertFXRate(rate),
+	}
+	return res, nil
+}
+
+func validateGetFXRateRequest(req *pb.GetFXRateRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateCurrencyCode(req.GetFromCurrency()); err != nil {
+		violations = append(violations, fieldViolation("from_currency", err))
+	}
+	if err := valid.ValidateCurrencyCode(req.GetToCurrency()); err != nil {
+		violations = append(violations, fieldViolation("to_currency", err))
+	}
+	if req.GetFromCurrency() == req.GetToCurrency() {
+		violations = append(violations, fieldViolation("to_currency", fmt.Errorf("must differ from from_currency")))
+	}
+	if req.At != nil {
+		if err := req.GetAt().CheckValid(); err != nil {
+			violations = append(violations, fieldViolation("at", err))
+		}
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dxtym/bankrupt/authz"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// runs returned along with the schedule
+const recentScheduledRuns = 10
+
+func (s *Server) GetScheduledTransfer(ctx context.Context, req *pb.GetScheduledTransferRequest) (*pb.GetScheduledTransferResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.ReadAccount)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateGetScheduledTransferRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	schedule, err := s.getOwnedScheduledTransfer(ctx, authPayload, authz.ReadAccount, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := s.store.ListScheduledTransferRuns(ctx, db.ListScheduledTransferRunsParams{
+		ScheduledTransferID: schedule.ID,
+		Limit:               recentScheduledRuns,
+		Offset:              0,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot list scheduled transfer runs: %v", err)
+	}
+
+	res := &pb.GetScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(schedule),
+		RecentRuns:        make([]*pb.ScheduledTransferRun, 0, len(runs)),
+	}
+	for _, run := range runs {
+		res.RecentRuns = append(res.RecentRuns, convertScheduledTransferRun(run))
+	}
+	return res, nil
+}
+
+func validateGetScheduledTransferRequest(req *pb.GetScheduledTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return
+}
@@ -0,0 +1,131 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dxtym/bankrupt/authz"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pagination"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultStatementPageSize = 100
+	maxStatementPageSize     = 500
+)
+
+func (s *Server) GetStatement(ctx context.Context, req *pb.GetStatementRequest) (*pb.GetStatementResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.ReadAccount)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateGetStatementRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := s.authorizeAccount(ctx, authPayload, authz.ReadAccount, req.GetAccountId())
+	if err != nil {
+		return nil, err
+	}
+
+	fromTime, toTime := req.GetFromTime().AsTime(), req.GetToTime().AsTime()
+
+	// balances come from stored running balances, no history is summed
+	opening, err := s.getBalanceAt(ctx, account.ID, fromTime)
+	if err != nil {
+		return nil, err
+	}
+	closing, err := s.getBalanceAt(ctx, account.ID, toTime)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.ListStatementEntriesParams{
+		AccountID: account.ID,
+		FromTime:  fromTime,
+		ToTime:    toTime,
+	}
+
+	// same query serves both, offset paging keeps the cursor at the start
+	scope := fmt.Sprintf("statement:%d:%d:%d", account.ID, fromTime.UnixNano(), toTime.UnixNano())
+	pageSize := req.GetPageSize()
+	if isOffsetPaging(req) {
+		arg.LimitCount = pageSize
+		arg.OffsetCount = (req.GetPageId() - 1) * pageSize
+	} else {
+		var cursor pagination.Cursor
+		cursor, pageSize, err = s.parsePageToken(req, scope, defaultStatementPageSize, maxStatementPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		arg.AfterID = cursor.ID
+		arg.LimitCount = pageSize + 1
+	}
+
+	entries, err := s.store.ListStatementEntries(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot list statement entries: %v", err)
+	}
+
+	var next *pagination.Cursor
+	if !isOffsetPaging(req) {
+		entries, next = pagination.Trim(entries, pageSize, statementEntryCursor)
+	}
+
+	res := &pb.GetStatementResponse{
+		AccountId:      account.ID,
+		FromTime:       timestamppb.New(fromTime),
+		ToTime:         timestamppb.New(toTime),
+		OpeningBalance: convertMoney(utils.NewMoney(opening, account.Currency)),
+		ClosingBalance: convertMoney(utils.NewMoney(closing, account.Currency)),
+		Entries:        make([]*pb.StatementEntry, 0, len(entries)),
+		NextPageToken:  s.pageTokens.Encode(next, scope),
+	}
+	for _, entry := range entries {
+		res.Entries = append(res.Entries, convertStatementEntry(entry, account.Currency))
+	}
+	return res, nil
+}
+
+func statementEntryCursor(entry db.ListStatementEntriesRow) pagination.Cursor {
+	return pagination.Cursor{ID: entry.ID}
+}
+
+func (s *Server) getBalanceAt(ctx context.Context, accountID int64, at time.Time) (int64, error) {
+	balance, err := s.store.GetBalanceBefore(ctx, db.GetBalanceBeforeParams{
+		AccountID: accountID,
+		Before:    at,
+	})
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "cannot get balance: %v", err)
+	}
+	return balance, nil
+}
+
+func validateGetStatementRequest(req *pb.GetStatementRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+	if req.FromTime == nil {
+		violations = append(violations, fieldViolation("from_time", fmt.Errorf("must be set")))
+	}
+	if req.ToTime == nil {
+		violations = append(violations, fieldViolation("to_time", fmt.Errorf("must be set")))
+	}
+	if req.FromTime != nil && req.ToTime != nil && !req.GetToTime().AsTime().After(req.GetFromTime().AsTime()) {
+		violations = append(violations, fieldViolation("to_time", fmt.Errorf("must be after from_time")))
+	}
+	violations = append(violations, validatePageRequest(req, valid.ValidateStatementPageSize)...)
+	return
+}
@@ -0,0 +1,63 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) GetTransfer(ctx context.Context, req *pb.GetTransferRequest) (*pb.GetTransferResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.ReadAccount)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateGetTransferRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	transfer, err := s.store.GetTransfer(ctx, req.GetId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "transfer not found: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get transfer: %v", err)
+	}
+
+	// user must own either side of the transfer
+	if _, err := s.authorizeAccount(ctx, authPayload, authz.ReadAccount, transfer.FromAccountID); err != nil {
+		if status.Code(err) != codes.PermissionDenied {
+			return nil, err
+		}
+		if _, err := s.authorizeAccount(ctx, authPayload, authz.ReadAccount, transfer.ToAccountID); err != nil {
+			return nil, err
+		}
+	}
+
+	reversals, err := s.store.ListTransferReversals(ctx, sql.NullInt64{Int64: transfer.ID, Valid: true})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot list reversals: %v", err)
+	}
+
+	res := &pb.GetTransferResponse{
+		Transfer: convertTransfer(transfer),
+	}
+	for _, reversal := range reversals {
+		res.Reversals = append(res.Reversals, convertTransfer(reversal))
+	}
+	return res, nil
+}
+
+func validateGetTransferRequest(req *pb.GetTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return
+}
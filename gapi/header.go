@@ -0,0 +1,16 @@
+package gapi
+
+import (
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+)
+
+// forward custom http headers to grpc metadata
+func HeaderMatcher(key string) (string, bool) {
+	switch strings.ToLower(key) {
+	case idempotencyKeyHeader:
+		return idempotencyKeyHeader, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
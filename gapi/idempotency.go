@@ -0,0 +1,50 @@
+package gapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const idempotencyKeyHeader = "idempotency-key"
+
+// read optional idempotency key and fingerprint the request it guards
+func (s *Server) getIdempotency(ctx context.Context, username string, req proto.Message) (*db.IdempotencyParams, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	keys := md.Get(idempotencyKeyHeader)
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	if err := valid.ValidateIdempotencyKey(keys[0]); err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{
+			fieldViolation(idempotencyKeyHeader, err),
+		})
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot hash request: %v", err)
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(req.ProtoReflect().Descriptor().FullName()))
+	hash.Write(data)
+
+	return &db.IdempotencyParams{
+		Username:    username,
+		Key:         keys[0],
+		RequestHash: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
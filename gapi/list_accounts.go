@@ -0,0 +1,73 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dxtym/bankrupt/authz"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pagination"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.ListAccounts)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateListAccountsRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	res := &pb.ListAccountsResponse{}
+	var accounts []db.Account
+	if isOffsetPaging(req) {
+		accounts, err = s.store.ListAccounts(ctx, db.ListAccountsParams{
+			Owner:  authPayload.Username,
+			Limit:  req.GetPageSize(),
+			Offset: (req.GetPageId() - 1) * req.GetPageSize(),
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot list accounts: %v", err)
+		}
+	} else {
+		scope := "accounts:" + authPayload.Username
+		cursor, pageSize, err := s.parsePageToken(req, scope, pagination.DefaultPageSize, pagination.MaxPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		accounts, err = s.store.ListAccountsPage(ctx, db.ListAccountsPageParams{
+			Owner:          authPayload.Username,
+			AfterCreatedAt: cursor.CreatedAt,
+			AfterID:        cursor.ID,
+			LimitCount:     pageSize + 1,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot list accounts: %v", err)
+		}
+
+		var next *pagination.Cursor
+		accounts, next = pagination.Trim(accounts, pageSize, accountCursor)
+		res.NextPageToken = s.pageTokens.Encode(next, scope)
+	}
+
+	res.Accounts = make([]*pb.Account, 0, len(accounts))
+	for _, account := range accounts {
+		res.Accounts = append(res.Accounts, convertAccount(account))
+	}
+	return res, nil
+}
+
+func accountCursor(account db.Account) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: account.CreatedAt, ID: account.ID}
+}
+
+func validateListAccountsRequest(req *pb.ListAccountsRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	return validatePageRequest(req, valid.ValidatePageSize)
+}
@@ -0,0 +1,27 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) ListCurrencies(ctx context.Context, req *pb.ListCurrenciesRequest) (*pb.ListCurrenciesResponse, error) {
+	if _, err := s.authorizeUser(ctx, authz.ReadRates); err != nil {
+		return nil, authorizationError(err)
+	}
+
+	currencies, err := s.store.ListCurrencies(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot list currencies: %v", err)
+	}
+
+	res := &pb.ListCurrenciesResponse{}
+	for _, currency := range currencies {
+		res.Currencies = append(res.Currencies, convertCurrency(currency))
+	}
+	return res, nil
+}
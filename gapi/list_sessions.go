@@ -0,0 +1,28 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) ListSessions(ctx context.Context, req *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.ManageSessions)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	sessions, err := s.store.ListActiveSessions(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot list sessions: %v", err)
+	}
+
+	res := &pb.ListSessionsResponse{}
+	for _, session := range sessions {
+		res.Sessions = append(res.Sessions, convertSession(session))
+	}
+	return res, nil
+}
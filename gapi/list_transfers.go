@@ -0,0 +1,94 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dxtym/bankrupt/authz"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pagination"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) ListTransfers(ctx context.Context, req *pb.ListTransfersRequest) (*pb.ListTransfersResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.ReadAccount)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateListTransfersRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := s.authorizeAccount(ctx, authPayload, authz.ReadAccount, req.GetAccountId())
+	if err != nil {
+		return nil, err
+	}
+
+	statusFilter := sql.NullString{String: req.GetStatus(), Valid: req.GetStatus() != ""}
+
+	res := &pb.ListTransfersResponse{}
+	var transfers []db.Transfer
+	if isOffsetPaging(req) {
+		transfers, err = s.store.ListTransfers(ctx, db.ListTransfersParams{
+			FromAccountID: account.ID,
+			ToAccountID:   account.ID,
+			Limit:         req.GetPageSize(),
+			Offset:        (req.GetPageId() - 1) * req.GetPageSize(),
+			Status:        statusFilter,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot list transfers: %v", err)
+		}
+	} else {
+		scope := fmt.Sprintf("transfers:%d:%s", account.ID, req.GetStatus())
+		cursor, pageSize, err := s.parsePageToken(req, scope, pagination.DefaultPageSize, pagination.MaxPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		transfers, err = s.store.ListTransfersPage(ctx, db.ListTransfersPageParams{
+			AccountID:      account.ID,
+			Status:         statusFilter,
+			AfterCreatedAt: cursor.CreatedAt,
+			AfterID:        cursor.ID,
+			LimitCount:     pageSize + 1,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "cannot list transfers: %v", err)
+		}
+
+		var next *pagination.Cursor
+		transfers, next = pagination.Trim(transfers, pageSize, transferCursor)
+		res.NextPageToken = s.pageTokens.Encode(next, scope)
+	}
+
+	res.Transfers = make([]*pb.Transfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		res.Transfers = append(res.Transfers, convertTransfer(transfer))
+	}
+	return res, nil
+}
+
+func transferCursor(transfer db.Transfer) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: transfer.CreatedAt, ID: transfer.ID}
+}
+
+func validateListTransfersRequest(req *pb.ListTransfersRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+	violations = append(violations, validatePageRequest(req, valid.ValidatePageSize)...)
+	if req.GetStatus() != "" {
+		if err := valid.ValidateTransferStatus(req.GetStatus(), db.IsTransferStatus); err != nil {
+			violations = append(violations, fieldViolation("status", err))
+		}
+	}
+	return
+}
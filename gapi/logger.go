@@ -39,6 +39,34 @@ func GrpcLogger(
 	return
 }
 
+func GrpcStreamLogger(
+	srv any,
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	logger := log.Info()
+	if err != nil {
+		logger = log.Error().Err(err)
+	}
+	duration := time.Since(start)
+
+	statusCode := codes.Unknown
+	if status, ok := status.FromError(err); ok {
+		statusCode = status.Code()
+	}
+
+	logger.Str("protocol", "grpc").
+		Str("method", info.FullMethod).
+		Int("status_code", int(statusCode)).
+		Str("status_text", statusCode.String()).
+		Dur("duration", duration).
+		Msg("received a gRPC stream")
+	return err
+}
+
 type ResponseRecorder struct {
 	http.ResponseWriter
 	statusCode int
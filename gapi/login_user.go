@@ -6,6 +6,7 @@ import (
 
 	db "github.com/dxtym/bankrupt/db/sqlc"
 	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/token"
 	"github.com/dxtym/bankrupt/utils"
 	"github.com/dxtym/bankrupt/valid"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -34,12 +35,12 @@ func (s *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (*pb.L
 		return nil, status.Errorf(codes.Unauthenticated, "password is incorrect: %v", err)
 	}
 
-	accessToken, accessPayload, err := s.token.CreateToken(user.Username, s.config.TokenDuration)
+	accessToken, accessPayload, err := s.token.CreateToken(user.Username, user.Role, token.AccessToken, s.config.TokenDuration)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot create access token: %v", err)
 	}
 
-	refreshToken, refreshPayload, err := s.token.CreateToken(user.Username, s.config.RefreshDuration)
+	refreshToken, refreshPayload, err := s.token.CreateToken(user.Username, user.Role, token.RefreshToken, s.config.RefreshDuration)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot create refresh token: %v", err)
 	}
@@ -47,13 +48,14 @@ func (s *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (*pb.L
 	meta := s.GetMetadata(ctx)
 
 	session, err := s.store.CreateSession(ctx, db.CreateSessionParams{
-		ID:           refreshPayload.Id,
-		Username:     user.Username,
-		RefreshToken: refreshToken,
-		UserAgent:    meta.userAgent,
-		ClientIp:     meta.clientIP,
-		IsBlocked:    false,
-		ExpiresAt:    refreshPayload.ExpiredAt,
+		ID:               refreshPayload.Id,
+		FamilyID:         refreshPayload.Id,
+		Username:         user.Username,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		UserAgent:        meta.userAgent,
+		ClientIp:         meta.clientIP,
+		IsBlocked:        false,
+		ExpiresAt:        refreshPayload.ExpiredAt,
 	})
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "cannot create session: %v", err)
@@ -0,0 +1,28 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// access tokens already issued stay valid until they expire
+func (s *Server) LogoutAll(ctx context.Context, req *pb.LogoutAllRequest) (*pb.LogoutAllResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.ManageSessions)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	revoked, err := s.store.BlockUserSessions(ctx, authPayload.Username)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot revoke sessions: %v", err)
+	}
+
+	res := &pb.LogoutAllResponse{
+		RevokedSessions: revoked,
+	}
+	return res, nil
+}
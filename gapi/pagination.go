@@ -0,0 +1,59 @@
+package gapi
+
+import (
+	"fmt"
+
+	"github.com/dxtym/bankrupt/pagination"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type pageTokenRequest interface {
+	GetPageSize() int32
+	GetPageToken() string
+}
+
+type pageRequest interface {
+	pageTokenRequest
+	GetPageId() int32
+}
+
+// deprecated offset paging is kept while clients still send page_id
+func isOffsetPaging(req pageRequest) bool {
+	return req.GetPageId() != 0
+}
+
+func validatePageRequest(req pageRequest, validatePageSize func(int32) error) (violations []*errdetails.BadRequest_FieldViolation) {
+	if !isOffsetPaging(req) {
+		if req.GetPageSize() < 0 {
+			violations = append(violations, fieldViolation("page_size", pagination.ErrInvalidPageSize))
+		}
+		return
+	}
+
+	if req.GetPageToken() != "" {
+		violations = append(violations, fieldViolation("page_token", fmt.Errorf("cannot be combined with page_id")))
+	}
+	if req.GetPageId() < 1 {
+		violations = append(violations, fieldViolation("page_id", fmt.Errorf("must be a positive integer")))
+	}
+	if err := validatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+	return
+}
+
+// position and size of the requested page, the token must come from the same query
+func (s *Server) parsePageToken(req pageTokenRequest, scope string, defaultSize, maxSize int32) (pagination.Cursor, int32, error) {
+	pageSize, err := pagination.PageSize(req.GetPageSize(), defaultSize, maxSize)
+	if err != nil {
+		return pagination.Cursor{}, 0, status.Errorf(codes.InvalidArgument, "invalid page size: %v", err)
+	}
+
+	cursor, err := s.pageTokens.Decode(req.GetPageToken(), scope)
+	if err != nil {
+		return cursor, 0, status.Errorf(codes.InvalidArgument, "cannot parse page token: %v", err)
+	}
+	return cursor, pageSize, nil
+}
@@ -0,0 +1,93 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/token"
+	"github.com/dxtym/bankrupt/utils"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *Server) RenewAccessToken(ctx context.Context, req *pb.RenewAccessTokenRequest) (*pb.RenewAccessTokenResponse, error) {
+	violations := validateRenewAccessTokenRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	refreshPayload, err := s.token.VerifyToken(req.GetRefreshToken(), token.RefreshToken)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid refresh token: %v", err)
+	}
+
+	// role may have changed since login
+	user, err := s.store.GetUser(ctx, refreshPayload.Username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "no such user: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get user: %v", err)
+	}
+
+	// rotate refresh token, the new one keeps the expiry of the login
+	meta := s.GetMetadata(ctx)
+	var refreshToken string
+	var nextPayload *token.Payload
+	_, err = s.store.RotateSessionTx(ctx, db.RotateSessionTxParams{
+		ID:               refreshPayload.Id,
+		Username:         refreshPayload.Username,
+		RefreshTokenHash: utils.HashToken(req.GetRefreshToken()),
+		NextSession: func(rotated db.Session) (db.CreateSessionParams, error) {
+			refreshToken, nextPayload, err = s.token.CreateToken(user.Username, user.Role, token.RefreshToken, time.Until(rotated.ExpiresAt))
+			if err != nil {
+				return db.CreateSessionParams{}, err
+			}
+
+			return db.CreateSessionParams{
+				ID:               nextPayload.Id,
+				RefreshTokenHash: utils.HashToken(refreshToken),
+				UserAgent:        meta.userAgent,
+				ClientIp:         meta.clientIP,
+				ExpiresAt:        nextPayload.ExpiredAt,
+			}, nil
+		},
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, status.Errorf(codes.NotFound, "session not found: %v", err)
+		case errors.Is(err, db.ErrSessionMismatch), errors.Is(err, db.ErrSessionBlocked),
+			errors.Is(err, db.ErrSessionExpired), errors.Is(err, db.ErrRefreshTokenReused):
+			return nil, status.Errorf(codes.Unauthenticated, "cannot renew session: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot rotate session: %v", err)
+	}
+
+	accessToken, accessPayload, err := s.token.CreateToken(user.Username, user.Role, token.AccessToken, s.config.TokenDuration)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot create access token: %v", err)
+	}
+
+	res := &pb.RenewAccessTokenResponse{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  timestamppb.New(accessPayload.ExpiredAt),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(nextPayload.ExpiredAt),
+	}
+	return res, nil
+}
+
+func validateRenewAccessTokenRequest(req *pb.RenewAccessTokenRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetRefreshToken() == "" {
+		violations = append(violations, fieldViolation("refresh_token", fmt.Errorf("must not be empty")))
+	}
+	return
+}
@@ -0,0 +1,40 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dxtym/bankrupt/authz"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/worker"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) ResendVerifyEmail(ctx context.Context, req *pb.ResendVerifyEmailRequest) (*pb.ResendVerifyEmailResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.UpdateUser)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	user, err := s.store.ResendVerifyEmailTx(ctx, db.ResendVerifyEmailTxParams{
+		Username: authPayload.Username,
+		Outbox:   []db.OutboxTask{worker.VerifyEmailTask(authPayload.Username)},
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, status.Errorf(codes.NotFound, "cannot resend verify email: %v", err)
+		case errors.Is(err, db.ErrEmailAlreadyVerified):
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot resend verify email: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot resend verify email: %v", err)
+	}
+
+	res := &pb.ResendVerifyEmailResponse{
+		User: convertUser(user),
+	}
+	return res, nil
+}
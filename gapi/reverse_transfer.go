@@ -0,0 +1,95 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/currency"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) ReverseTransfer(ctx context.Context, req *pb.ReverseTransferRequest) (*pb.ReverseTransferResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.ReverseTransfer)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateReverseTransferRequest(req, s.currencies)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	transfer, err := s.store.GetTransfer(ctx, req.GetId())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "transfer not found: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get transfer: %v", err)
+	}
+
+	// receiver can refund, bankers can reverse anything
+	if _, err := s.authorizeAccount(ctx, authPayload, authz.ReverseTransfer, transfer.ToAccountID); err != nil {
+		return nil, err
+	}
+
+	// giving a deposit back to the cash account is a withdrawal
+	sender, err := s.store.GetAccount(ctx, transfer.FromAccountID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot get account: %v", err)
+	}
+	if sender.IsSystem() {
+		if err := s.authorizeOwner(authPayload, authz.ReverseSystem, sender.Owner); err != nil {
+			return nil, err
+		}
+	}
+
+	idempotency, err := s.getIdempotency(ctx, authPayload.Username, req)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.ReverseTransferTxParams{
+		TransferID:  transfer.ID,
+		Idempotency: idempotency,
+	}
+	if req.GetAmount() != nil {
+		arg.Amount = parseMoney(req.GetAmount())
+	}
+
+	result, err := s.store.ReverseTransferTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrTransferReversed) || errors.Is(err, db.ErrReversalAmountExceeded) || errors.Is(err, db.ErrTransferNotReversible) ||
+			errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrIdempotencyKeyReused) || errors.Is(err, utils.ErrCurrencyMismatch) {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot reverse transfer: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot reverse transfer: %v", err)
+	}
+
+	res := &pb.ReverseTransferResponse{
+		Reversal:    convertTransfer(result.Transfer),
+		Original:    convertTransfer(result.Original),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry, transfer.Currency),
+		ToEntry:     convertEntry(result.ToEntry, transfer.Currency),
+	}
+	return res, nil
+}
+
+func validateReverseTransferRequest(req *pb.ReverseTransferRequest, currencies *currency.Registry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	if req.GetAmount() != nil {
+		violations = append(violations, validateMoney("amount", req.GetAmount(), currencies)...)
+	}
+	return
+}
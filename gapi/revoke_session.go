@@ -0,0 +1,54 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) RevokeSession(ctx context.Context, req *pb.RevokeSessionRequest) (*pb.RevokeSessionResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.ManageSessions)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateRevokeSessionRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	session, err := s.store.GetSession(ctx, uuid.MustParse(req.GetSessionId()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "session not found: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get session: %v", err)
+	}
+
+	if err := s.authorizeOwner(authPayload, authz.ManageSessions, session.Username); err != nil {
+		return nil, err
+	}
+
+	session, err = s.store.BlockSession(ctx, session.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot revoke session: %v", err)
+	}
+
+	res := &pb.RevokeSessionResponse{
+		Session: convertSession(session),
+	}
+	return res, nil
+}
+
+func validateRevokeSessionRequest(req *pb.RevokeSessionRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if _, err := uuid.Parse(req.GetSessionId()); err != nil {
+		violations = append(violations, fieldViolation("session_id", err))
+	}
+	return
+}
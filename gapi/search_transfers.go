@@ -0,0 +1,204 @@
+package gapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/currency"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pagination"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	directionIn   = "in"
+	directionOut  = "out"
+	directionBoth = "both"
+
+	sortByCreatedAt = "created_at"
+	sortByAmount    = "amount"
+)
+
+func (s *Server) SearchTransfers(ctx context.Context, req *pb.SearchTransfersRequest) (*pb.SearchTransfersResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.ReadAccount)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateSearchTransfersRequest(req, s.currencies)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	// search own accounts, or the owner's when bankers search a given account
+	owner := authPayload.Username
+	if req.GetAccountId() != 0 {
+		account, err := s.authorizeAccount(ctx, authPayload, authz.ReadAccount, req.GetAccountId())
+		if err != nil {
+			return nil, err
+		}
+		owner = account.Owner
+	}
+
+	scope, err := searchScope(owner, req)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot build page scope: %v", err)
+	}
+
+	cursor, pageSize, err := s.parsePageToken(req, scope, pagination.DefaultPageSize, pagination.MaxPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	sortBy := req.GetSortBy()
+	if sortBy == "" {
+		sortBy = sortByCreatedAt
+	}
+	direction := req.GetDirection()
+	if direction == "" {
+		direction = directionBoth
+	}
+
+	arg := db.SearchTransfersParams{
+		Owner:          owner,
+		AccountID:      sql.NullInt64{Int64: req.GetAccountId(), Valid: req.GetAccountId() != 0},
+		Outgoing:       direction != directionIn,
+		Incoming:       direction != directionOut,
+		CounterpartyID: sql.NullInt64{Int64: req.GetCounterpartyAccountId(), Valid: req.GetCounterpartyAccountId() != 0},
+		Currency:       sql.NullString{String: req.GetCurrency(), Valid: req.GetCurrency() != ""},
+		Status:         sql.NullString{String: req.GetStatus(), Valid: req.GetStatus() != ""},
+		Query:          sql.NullString{String: req.GetQuery(), Valid: req.GetQuery() != ""},
+		SortBy:         sortBy,
+		Descending:     req.GetDescending(),
+		AfterCreatedAt: cursor.CreatedAt,
+		AfterAmount:    cursor.Amount,
+		AfterID:        sql.NullInt64{Int64: cursor.ID, Valid: req.GetPageToken() != ""},
+		LimitCount:     pageSize + 1,
+	}
+	if req.GetMinAmount() != "" {
+		minAmount, _ := utils.ParseMoney(req.GetMinAmount(), req.GetCurrency())
+		arg.MinAmount = sql.NullInt64{Int64: minAmount.Amount, Valid: true}
+	}
+	if req.GetMaxAmount() != "" {
+		maxAmount, _ := utils.ParseMoney(req.GetMaxAmount(), req.GetCurrency())
+		arg.MaxAmount = sql.NullInt64{Int64: maxAmount.Amount, Valid: true}
+	}
+	if req.FromTime != nil {
+		arg.FromTime = sql.NullTime{Time: req.GetFromTime().AsTime(), Valid: true}
+	}
+	if req.ToTime != nil {
+		arg.ToTime = sql.NullTime{Time: req.GetToTime().AsTime(), Valid: true}
+	}
+
+	transfers, err := s.store.SearchTransfers(ctx, arg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "cannot search transfers: %v", err)
+	}
+
+	transfers, next := pagination.Trim(transfers, pageSize, transferSearchCursor)
+
+	res := &pb.SearchTransfersResponse{
+		Transfers:     make([]*pb.Transfer, 0, len(transfers)),
+		NextPageToken: s.pageTokens.Encode(next, scope),
+	}
+	for _, transfer := range transfers {
+		res.Transfers = append(res.Transfers, convertTransfer(transfer))
+	}
+	return res, nil
+}
+
+func transferSearchCursor(transfer db.Transfer) pagination.Cursor {
+	return pagination.Cursor{CreatedAt: transfer.CreatedAt, ID: transfer.ID, Amount: transfer.Amount}
+}
+
+// page tokens are bound to the user and every filter, changing any of them starts over
+func searchScope(username string, req *pb.SearchTransfersRequest) (string, error) {
+	filters := proto.Clone(req).(*pb.SearchTransfersRequest)
+	filters.PageSize = 0
+	filters.PageToken = ""
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(filters)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("transfer_search:%s:%x", username, sha256.Sum256(data)), nil
+}
+
+func validateSearchTransfersRequest(req *pb.SearchTransfersRequest, currencies *currency.Registry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetAccountId() != 0 {
+		if err := valid.ValidateID(req.GetAccountId()); err != nil {
+			violations = append(violations, fieldViolation("account_id", err))
+		}
+	}
+	if req.GetCounterpartyAccountId() != 0 {
+		if err := valid.ValidateID(req.GetCounterpartyAccountId()); err != nil {
+			violations = append(violations, fieldViolation("counterparty_account_id", err))
+		}
+	}
+	switch req.GetDirection() {
+	case "", directionIn, directionOut, directionBoth:
+	default:
+		violations = append(violations, fieldViolation("direction", fmt.Errorf("must be in, out or both")))
+	}
+	switch req.GetSortBy() {
+	case "", sortByCreatedAt, sortByAmount:
+	default:
+		violations = append(violations, fieldViolation("sort_by", fmt.Errorf("must be created_at or amount")))
+	}
+	if req.GetStatus() != "" {
+		if err := valid.ValidateTransferStatus(req.GetStatus(), db.IsTransferStatus); err != nil {
+			violations = append(violations, fieldViolation("status", err))
+		}
+	}
+	if err := valid.ValidateMemo(req.GetQuery()); err != nil {
+		violations = append(violations, fieldViolation("query", err))
+	}
+	if req.GetPageSize() < 0 {
+		violations = append(violations, fieldViolation("page_size", pagination.ErrInvalidPageSize))
+	}
+	if req.FromTime != nil && req.ToTime != nil && !req.GetFromTime().AsTime().Before(req.GetToTime().AsTime()) {
+		violations = append(violations, fieldViolation("to_time", fmt.Errorf("must be after from_time")))
+	}
+	violations = append(violations, validateAmountRange(req, currencies)...)
+	return
+}
+
+// amounts are only comparable within one currency
+func validateAmountRange(req *pb.SearchTransfersRequest, currencies *currency.Registry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetCurrency() == "" {
+		if req.GetMinAmount() != "" || req.GetMaxAmount() != "" {
+			violations = append(violations, fieldViolation("currency", fmt.Errorf("required with amount range")))
+		}
+		return
+	}
+	if err := valid.ValidateCurrency(req.GetCurrency(), currencies.Supported); err != nil {
+		return append(violations, fieldViolation("currency", err))
+	}
+
+	var minAmount, maxAmount utils.Money
+	if req.GetMinAmount() != "" {
+		if err := valid.ValidateAmount(req.GetMinAmount(), req.GetCurrency()); err != nil {
+			return append(violations, fieldViolation("min_amount", err))
+		}
+		minAmount, _ = utils.ParseMoney(req.GetMinAmount(), req.GetCurrency())
+	}
+	if req.GetMaxAmount() != "" {
+		if err := valid.ValidateAmount(req.GetMaxAmount(), req.GetCurrency()); err != nil {
+			return append(violations, fieldViolation("max_amount", err))
+		}
+		maxAmount, _ = utils.ParseMoney(req.GetMaxAmount(), req.GetCurrency())
+	}
+	if req.GetMinAmount() != "" && req.GetMaxAmount() != "" && minAmount.Amount > maxAmount.Amount {
+		violations = append(violations, fieldViolation("max_amount", fmt.Errorf("must not be less than min_amount")))
+	}
+	return
+}
@@ -3,7 +3,10 @@ package gapi
 import (
 	"fmt"
 
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/currency"
 	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pagination"
 	"github.com/dxtym/bankrupt/pb"
 	"github.com/dxtym/bankrupt/token"
 	"github.com/dxtym/bankrupt/utils"
@@ -16,9 +19,12 @@ type Server struct {
 	store           db.Store
 	token           token.Maker
 	taskDistributor worker.TaskDistributor
+	currencies      *currency.Registry
+	pageTokens      *pagination.Signer
+	policy          authz.Policy
 }
 
-func NewServer(config utils.Config, s db.Store, td worker.TaskDistributor) (*Server, error) {
+func NewServer(config utils.Config, s db.Store, td worker.TaskDistributor, currencies *currency.Registry) (*Server, error) {
 	token, err := token.NewPasetoMaker([]byte(config.TokenSymmetricKey))
 	if err != nil {
 		return nil, fmt.Errorf("cannot load token maker: %w", err)
@@ -28,6 +34,9 @@ func NewServer(config utils.Config, s db.Store, td worker.TaskDistributor) (*Ser
 		store:           s,
 		token:           token,
 		taskDistributor: td,
+		currencies:      currencies,
+		pageTokens:      pagination.NewSigner(config.TokenSymmetricKey),
+		policy:          authz.DefaultPolicy,
 	}
 	return server, nil
 }
@@ -0,0 +1,95 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dxtym/bankrupt/authz"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/fx"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/valid"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// source of rates set by bankers
+const manualRateSource = "manual"
+
+func (s *Server) SetFXRate(ctx context.Context, req *pb.SetFXRateRequest) (*pb.SetFXRateResponse, error) {
+	_, err := s.authorizeUser(ctx, authz.ManageRates)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateSetFXRateRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	arg := db.CreateFXRateParams{
+		FromCurrency:  req.GetFromCurrency(),
+		ToCurrency:    req.GetToCurrency(),
+		Rate:          req.GetRate(),
+		SpreadBps:     fx.DefaultSpreadBps,
+		EffectiveFrom: time.Now(),
+		Source:        manualRateSource,
+	}
+	if req.SpreadBps != nil {
+		arg.SpreadBps = req.GetSpreadBps()
+	}
+	if req.EffectiveFrom != nil {
+		arg.EffectiveFrom = req.GetEffectiveFrom().AsTime()
+	}
+
+	rate, err := s.store.CreateFXRate(ctx, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Errorf(codes.AlreadyExists, "rate already set for effective_from, history is not rewritten")
+		}
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				return nil, status.Errorf(codes.NotFound, "currency doesn't exist: %v", err)
+			case "check_violation", "numeric_value_out_of_range":
+				return nil, status.Errorf(codes.InvalidArgument, "invalid rate: %v", err)
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "cannot set rate: %v", err)
+	}
+
+	res := &pb.SetFXRateResponse{
+		Rate: convertFXRate(rate),
+	}
+	return res, nil
+}
+
+func validateSetFXRateRequest(req *pb.SetFXRateRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateCurrencyCode(req.GetFromCurrency()); err != nil {
+		violations = append(violations, fieldViolation("from_currency", err))
+	}
+	if err := valid.ValidateCurrencyCode(req.GetToCurrency()); err != nil {
+		violations = append(violations, fieldViolation("to_currency", err))
+	}
+	if req.GetFromCurrency() == req.GetToCurrency() {
+		violations = append(violations, fieldViolation("to_currency", fmt.Errorf("must differ from from_currency")))
+	}
+	if err := utils.ValidateRate(req.GetRate()); err != nil {
+		violations = append(violations, fieldViolation("rate", err))
+	}
+	if req.SpreadBps != nil && (req.GetSpreadBps() < 0 || req.GetSpreadBps() >= 10000) {
+		violations = append(violations, fieldViolation("spread_bps", fmt.Errorf("must be between 0 and 9999")))
+	}
+	if req.EffectiveFrom != nil {
+		if err := req.GetEffectiveFrom().CheckValid(); err != nil {
+			violations = append(violations, fieldViolation("effective_from", err))
+		}
+	}
+	return
+}
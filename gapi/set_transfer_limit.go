@@ -0,0 +1,137 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/currency"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/limits"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/valid"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) SetTransferLimit(ctx context.Context, req *pb.SetTransferLimitRequest) (*pb.SetTransferLimitResponse, error) {
+	_, err := s.authorizeUser(ctx, authz.ManageLimits)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateSetTransferLimitRequest(req, s.currencies)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	amount := func(max *string) sql.NullInt64 {
+		if max == nil {
+			return sql.NullInt64{}
+		}
+		money, _ := utils.ParseMoney(*max, req.GetCurrency())
+		return sql.NullInt64{Int64: money.Amount, Valid: true}
+	}
+	arg := db.SetTransferLimitParams{
+		Tier:           sql.NullString{String: req.GetTier(), Valid: req.GetTier() != ""},
+		AccountID:      sql.NullInt64{Int64: req.GetAccountId(), Valid: req.GetAccountId() != 0},
+		Currency:       sql.NullString{String: req.GetCurrency(), Valid: req.GetCurrency() != ""},
+		MaxPerTransfer: amount(req.MaxPerTransfer),
+		MaxDaily:       amount(req.MaxDaily),
+		MaxWeekly:      amount(req.MaxWeekly),
+		MaxMonthly:     amount(req.MaxMonthly),
+		MaxHourlyCount: sql.NullInt64{Int64: req.GetMaxHourlyCount(), Valid: req.MaxHourlyCount != nil},
+		Clear:          req.GetClear(),
+	}
+
+	limit, err := s.store.SetTransferLimit(ctx, arg)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code.Name() {
+			case "foreign_key_violation":
+				return nil, status.Errorf(codes.NotFound, "account or currency doesn't exist: %v", err)
+			}
+		}
+		return nil, status.Errorf(codes.Internal, "cannot set transfer limit: %v", err)
+	}
+
+	res := &pb.SetTransferLimitResponse{
+		Limit: convertTransferLimit(limit),
+	}
+	return res, nil
+}
+
+func validateSetTransferLimitRequest(req *pb.SetTransferLimitRequest, currencies *currency.Registry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetTier() != "" {
+		if err := valid.ValidateTier(req.GetTier()); err != nil {
+			violations = append(violations, fieldViolation("tier", err))
+		}
+		if req.GetAccountId() != 0 {
+			violations = append(violations, fieldViolation("account_id", fmt.Errorf("cannot be combined with tier")))
+		}
+	}
+	if req.GetAccountId() != 0 {
+		if err := valid.ValidateID(req.GetAccountId()); err != nil {
+			violations = append(violations, fieldViolation("account_id", err))
+		}
+	}
+	if req.MaxHourlyCount != nil {
+		if req.GetMaxHourlyCount() < 1 {
+			violations = append(violations, fieldViolation("max_hourly_count", fmt.Errorf("must be a positive integer")))
+		}
+		if req.GetCurrency() != "" {
+			violations = append(violations, fieldViolation("currency", fmt.Errorf("cannot be combined with max_hourly_count")))
+		}
+	}
+
+	set := map[string]bool{
+		limits.PerTransfer: req.MaxPerTransfer != nil,
+		limits.Daily:       req.MaxDaily != nil,
+		limits.Weekly:      req.MaxWeekly != nil,
+		limits.Monthly:     req.MaxMonthly != nil,
+		limits.HourlyCount: req.MaxHourlyCount != nil,
+	}
+	for _, limit := range req.GetClear() {
+		isSet, ok := set[limit]
+		if !ok {
+			violations = append(violations, fieldViolation("clear", fmt.Errorf("unknown limit %q", limit)))
+		} else if isSet {
+			violations = append(violations, fieldViolation("clear", fmt.Errorf("cannot set and clear %s", limit)))
+		}
+	}
+
+	amounts := []struct {
+		field string
+		max   *string
+	}{
+		{"max_per_transfer", req.MaxPerTransfer},
+		{"max_daily", req.MaxDaily},
+		{"max_weekly", req.MaxWeekly},
+		{"max_monthly", req.MaxMonthly},
+	}
+	if req.GetCurrency() == "" {
+		for _, amount := range amounts {
+			if amount.max != nil {
+				violations = append(violations, fieldViolation("currency", fmt.Errorf("required with amount limits")))
+				break
+			}
+		}
+		return
+	}
+	if err := valid.ValidateCurrency(req.GetCurrency(), currencies.Supported); err != nil {
+		return append(violations, fieldViolation("currency", err))
+	}
+	for _, amount := range amounts {
+		if amount.max == nil {
+			continue
+		}
+		if err := valid.ValidateAmount(*amount.max, req.GetCurrency()); err != nil {
+			violations = append(violations, fieldViolation(amount.field, err))
+		}
+	}
+	return
+}
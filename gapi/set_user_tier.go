@@ -0,0 +1,52 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dxtym/bankrupt/authz"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) SetUserTier(ctx context.Context, req *pb.SetUserTierRequest) (*pb.SetUserTierResponse, error) {
+	_, err := s.authorizeUser(ctx, authz.ManageLimits)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateSetUserTierRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	user, err := s.store.UpdateUserTier(ctx, db.UpdateUserTierParams{
+		Username: req.GetUsername(),
+		Tier:     req.GetTier(),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot set user tier: %v", err)
+	}
+
+	res := &pb.SetUserTierResponse{
+		User: convertUser(user),
+	}
+	return res, nil
+}
+
+func validateSetUserTierRequest(req *pb.SetUserTierRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateUsername(req.GetUsername()); err != nil {
+		violations = append(violations, fieldViolation("username", err))
+	}
+	if err := valid.ValidateTier(req.GetTier()); err != nil {
+		violations = append(violations, fieldViolation("tier", err))
+	}
+	return
+}
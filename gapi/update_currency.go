@@ -0,0 +1,111 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dxtym/bankrupt/authz"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/dxtym/bankrupt/valid"
+	"github.com/lib/pq"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) UpdateCurrency(ctx context.Context, req *pb.UpdateCurrencyRequest) (*pb.UpdateCurrencyResponse, error) {
+	_, err := s.authorizeUser(ctx, authz.ManageRates)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateUpdateCurrencyRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	currency, err := s.store.GetCurrency(ctx, req.GetCode())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "currency not found: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot get currency: %v", err)
+	}
+
+	// limits are parsed with exponent of the stored currency
+	violations = validateUpdateCurrencyLimits(req, currency.Exponent)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	arg := db.UpdateCurrencyParams{
+		Code: req.GetCode(),
+		Name: sql.NullString{
+			String: req.GetName(),
+			Valid:  req.Name != nil,
+		},
+		Enabled: sql.NullBool{
+			Bool:  req.GetEnabled(),
+			Valid: req.Enabled != nil,
+		},
+		SetMaxTransferAmount: req.GetClearMaxTransferAmount() || req.MaxTransferAmount != nil,
+	}
+	if req.MinTransferAmount != nil {
+		minAmount, _ := utils.ParseAmount(req.GetMinTransferAmount(), int(currency.Exponent))
+		arg.MinTransferAmount = sql.NullInt64{Int64: minAmount, Valid: true}
+	}
+	if req.MaxTransferAmount != nil {
+		maxAmount, _ := utils.ParseAmount(req.GetMaxTransferAmount(), int(currency.Exponent))
+		arg.MaxTransferAmount = sql.NullInt64{Int64: maxAmount, Valid: true}
+	}
+
+	currency, err = s.store.UpdateCurrency(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "currency not found: %v", err)
+		}
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "check_violation" {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid transfer limits: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot update currency: %v", err)
+	}
+
+	s.currencies.Invalidate()
+
+	res := &pb.UpdateCurrencyResponse{
+		Currency: convertCurrency(currency),
+	}
+	return res, nil
+}
+
+func validateUpdateCurrencyRequest(req *pb.UpdateCurrencyRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateCurrencyCode(req.GetCode()); err != nil {
+		violations = append(violations, fieldViolation("code", err))
+	}
+	if req.Name != nil {
+		if err := valid.ValidateString(req.GetName(), 1, 64); err != nil {
+			violations = append(violations, fieldViolation("name", err))
+		}
+	}
+	if req.GetClearMaxTransferAmount() && req.MaxTransferAmount != nil {
+		violations = append(violations, fieldViolation("clear_max_transfer_amount", fmt.Errorf("cannot be combined with max_transfer_amount")))
+	}
+	return
+}
+
+func validateUpdateCurrencyLimits(req *pb.UpdateCurrencyRequest, exponent int32) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.MinTransferAmount != nil {
+		if err := validateTransferLimit(req.GetMinTransferAmount(), exponent); err != nil {
+			violations = append(violations, fieldViolation("min_transfer_amount", err))
+		}
+	}
+	if req.MaxTransferAmount != nil {
+		if err := validateTransferLimit(req.GetMaxTransferAmount(), exponent); err != nil {
+			violations = append(violations, fieldViolation("max_transfer_amount", err))
+		}
+	}
+	return
+}
@@ -0,0 +1,140 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/currency"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) UpdateScheduledTransfer(ctx context.Context, req *pb.UpdateScheduledTransferRequest) (*pb.UpdateScheduledTransferResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.MoveMoney)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateUpdateScheduledTransferRequest(req, s.currencies)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	schedule, err := s.getOwnedScheduledTransfer(ctx, authPayload, authz.MoveMoney, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Amount != nil && req.GetAmount().GetCurrency() != schedule.Currency {
+		err := fmt.Errorf("scheduled transfer [%d] mismatch %s vs %s", schedule.ID, schedule.Currency, req.GetAmount().GetCurrency())
+		return nil, status.Errorf(codes.FailedPrecondition, "currency mismatch: %v", err)
+	}
+
+	// re-read under lock, the worker may have run or finished it meanwhile
+	schedule, err = s.store.UpdateScheduledTransferTx(ctx, db.UpdateScheduledTransferTxParams{
+		ID: schedule.ID,
+		Update: func(schedule db.ScheduledTransfer) (db.UpdateScheduledTransferParams, error) {
+			return updateScheduledTransferParams(schedule, req)
+		},
+	})
+	if err != nil {
+		return nil, scheduledTransferUpdateError(err)
+	}
+
+	res := &pb.UpdateScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(schedule),
+	}
+	return res, nil
+}
+
+func updateScheduledTransferParams(schedule db.ScheduledTransfer, req *pb.UpdateScheduledTransferRequest) (db.UpdateScheduledTransferParams, error) {
+	arg := db.UpdateScheduledTransferParams{
+		ID:        schedule.ID,
+		Amount:    schedule.Amount,
+		Schedule:  schedule.Schedule,
+		EndAt:     schedule.EndAt,
+		NextRunAt: schedule.NextRunAt,
+		Status:    schedule.Status,
+	}
+	if req.Amount != nil {
+		arg.Amount = parseMoney(req.GetAmount()).Amount
+	}
+	if req.Schedule != nil {
+		arg.Schedule = req.GetSchedule()
+	}
+	if req.EndAt != nil {
+		arg.EndAt = sql.NullTime{Time: req.GetEndAt().AsTime(), Valid: true}
+	}
+	if req.GetClearEndAt() {
+		arg.EndAt = sql.NullTime{}
+	}
+	if req.Paused != nil {
+		arg.Status = db.ScheduleActive
+		if req.GetPaused() {
+			arg.Status = db.SchedulePaused
+		}
+	}
+	if arg.Status != db.ScheduleActive {
+		return arg, nil
+	}
+
+	// an active schedule keeps its pending run even if it is already due,
+	// runs missed while paused are skipped
+	var next time.Time
+	var err error
+	if schedule.Status == db.ScheduleActive && schedule.NextRunAt.Valid {
+		next, err = nextRun(arg.Schedule, schedule.NextRunAt.Time.Add(-time.Second), arg.EndAt)
+	} else {
+		next, err = firstRun(arg.Schedule, schedule.StartAt, arg.EndAt)
+	}
+	if err != nil {
+		return arg, err
+	}
+	arg.NextRunAt = sql.NullTime{Time: next, Valid: true}
+	return arg, nil
+}
+
+// callback errors are already grpc statuses
+func scheduledTransferUpdateError(err error) error {
+	if errors.Is(err, db.ErrScheduleClosed) {
+		return status.Errorf(codes.FailedPrecondition, "scheduled transfer is %v", err)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return status.Errorf(codes.NotFound, "scheduled transfer not found: %v", err)
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Errorf(codes.Internal, "cannot update scheduled transfer: %v", err)
+}
+
+func validateUpdateScheduledTransferRequest(req *pb.UpdateScheduledTransferRequest, currencies *currency.Registry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	if req.Schedule != nil {
+		if err := valid.ValidateSchedule(req.GetSchedule()); err != nil {
+			violations = append(violations, fieldViolation("schedule", err))
+		}
+	}
+	if req.GetClearEndAt() && req.EndAt != nil {
+		violations = append(violations, fieldViolation("clear_end_at", fmt.Errorf("cannot be combined with end_at")))
+	}
+	if req.Amount != nil {
+		if moneyViolations := validateMoney("amount", req.GetAmount(), currencies); len(moneyViolations) > 0 {
+			return append(violations, moneyViolations...)
+		}
+		if err := currencies.CheckTransferAmount(parseMoney(req.GetAmount())); err != nil {
+			violations = append(violations, fieldViolation("amount.amount", err))
+		}
+	}
+	return
+}
@@ -5,17 +5,19 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/dxtym/bankrupt/authz"
 	db "github.com/dxtym/bankrupt/db/sqlc"
 	"github.com/dxtym/bankrupt/pb"
 	"github.com/dxtym/bankrupt/utils"
 	"github.com/dxtym/bankrupt/valid"
+	"github.com/dxtym/bankrupt/worker"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 func (s *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
-	authPayload, err := s.authorizeUser(ctx)
+	authPayload, err := s.authorizeUser(ctx, authz.UpdateUser)
 	if err != nil {
 		return nil, authorizationError(err)
 	}
@@ -25,8 +27,8 @@ func (s *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb
 		return nil, invalidArgumentError(violations)
 	}
 
-	if authPayload.Username != req.GetUsername() {
-		return nil, status.Errorf(codes.PermissionDenied, "cannot update other users")
+	if err := s.authorizeOwner(authPayload, authz.UpdateUser, req.GetUsername()); err != nil {
+		return nil, err
 	}
 
 	arg := db.UpdateUserParams{
@@ -56,7 +58,13 @@ func (s *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb
 		}
 	}
 
-	user, err := s.store.UpdateUser(ctx, arg)
+	// a new address loses its verification until the link sent to it is used
+	result, err := s.store.UpdateUserTx(ctx, db.UpdateUserTxParams{
+		UpdateUserParams: arg,
+		OnEmailChanged: func(user db.User) []db.OutboxTask {
+			return []db.OutboxTask{worker.VerifyEmailTask(user.Username)}
+		},
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
@@ -65,7 +73,7 @@ func (s *Server) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb
 	}
 
 	res := &pb.UpdateUserResponse{
-		User: convertUser(user),
+		User: convertUser(result.User),
 	}
 	return res, nil
 }
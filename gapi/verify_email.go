@@ -0,0 +1,50 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	violations := validateVerifyEmailRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	result, err := s.store.VerifyEmailTx(ctx, db.VerifyEmailTxParams{
+		EmailId:    req.GetEmailId(),
+		SecretCode: req.GetSecretCode(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrVerifyEmailInvalid):
+			return nil, status.Errorf(codes.NotFound, "cannot verify email: %v", err)
+		case errors.Is(err, db.ErrVerifyEmailUsed), errors.Is(err, db.ErrVerifyEmailExpired),
+			errors.Is(err, db.ErrVerifyEmailChanged):
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot verify email: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot verify email: %v", err)
+	}
+
+	res := &pb.VerifyEmailResponse{
+		User: convertUser(result.User),
+	}
+	return res, nil
+}
+
+func validateVerifyEmailRequest(req *pb.VerifyEmailRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetEmailId()); err != nil {
+		violations = append(violations, fieldViolation("email_id", err))
+	}
+	if err := valid.ValidateSecretCode(req.GetSecretCode()); err != nil {
+		violations = append(violations, fieldViolation("secret_code", err))
+	}
+	return
+}
@@ -0,0 +1,51 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dxtym/bankrupt/authz"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) VoidHold(ctx context.Context, req *pb.VoidHoldRequest) (*pb.VoidHoldResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.MoveMoney)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateVoidHoldRequest(req)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	hold, err := s.getOwnedHold(ctx, authPayload, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	hold, err = s.store.VoidHoldTx(ctx, hold.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrHoldNotActive) || errors.Is(err, db.ErrHoldExpired) {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot void hold: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot void hold: %v", err)
+	}
+
+	res := &pb.VoidHoldResponse{
+		Hold: convertHold(hold),
+	}
+	return res, nil
+}
+
+func validateVoidHoldRequest(req *pb.VoidHoldRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetId()); err != nil {
+		violations = append(violations, fieldViolation("id", err))
+	}
+	return
+}
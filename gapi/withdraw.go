@@ -0,0 +1,66 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dxtym/bankrupt/authz"
+	"github.com/dxtym/bankrupt/currency"
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/valid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) Withdraw(ctx context.Context, req *pb.WithdrawRequest) (*pb.WithdrawResponse, error) {
+	authPayload, err := s.authorizeUser(ctx, authz.Withdraw)
+	if err != nil {
+		return nil, authorizationError(err)
+	}
+
+	violations := validateWithdrawRequest(req, s.currencies)
+	if len(violations) > 0 {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := s.validateAccount(ctx, req.GetAccountId(), req.GetAmount().GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	idempotency, err := s.getIdempotency(ctx, authPayload.Username, req)
+	if err != nil {
+		return nil, err
+	}
+
+	arg := db.CashTxParams{
+		AccountID:   req.GetAccountId(),
+		Amount:      parseMoney(req.GetAmount()),
+		Idempotency: idempotency,
+	}
+
+	result, err := s.store.WithdrawTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrSystemAccount) || errors.Is(err, db.ErrIdempotencyKeyReused) {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot withdraw money: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cannot withdraw money: %v", err)
+	}
+
+	res := &pb.WithdrawResponse{
+		Transfer: convertTransfer(result.Transfer),
+		Account:  convertAccount(result.FromAccount),
+		Entry:    convertEntry(result.FromEntry, account.Currency),
+	}
+	return res, nil
+}
+
+func validateWithdrawRequest(req *pb.WithdrawRequest, currencies *currency.Registry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := valid.ValidateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+	violations = append(violations, validateMoney("amount", req.GetAmount(), currencies)...)
+	return
+}
@@ -0,0 +1,138 @@
+package limits
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dxtym/bankrupt/utils"
+)
+
+var ErrExceeded = errors.New("transfer limit exceeded")
+
+// what the limits are counted against
+const (
+	ScopeUser    = "user"
+	ScopeAccount = "account"
+)
+
+const (
+	PerTransfer = "per_transfer"
+	Daily       = "daily"
+	Weekly      = "weekly"
+	Monthly     = "monthly"
+	HourlyCount = "hourly_count"
+)
+
+// amounts are in minor units of one currency, null means unlimited
+type Limits struct {
+	PerTransfer sql.NullInt64
+	Daily       sql.NullInt64
+	Weekly      sql.NullInt64
+	Monthly     sql.NullInt64
+	HourlyCount sql.NullInt64
+}
+
+// what was already sent in rolling windows ending now
+type Usage struct {
+	Daily       int64
+	Weekly      int64
+	Monthly     int64
+	HourlyCount int64
+}
+
+// violated limit with the allowance left in its window
+type ExceededError struct {
+	Scope     string
+	Limit     string
+	Currency  string // empty for count limits
+	Max       int64
+	Remaining int64 // zero for per transfer limits, they have no window
+}
+
+func (e *ExceededError) Error() string {
+	switch e.Limit {
+	case HourlyCount:
+		return fmt.Sprintf("%s %s limit of %d transfers reached, %d remaining", e.Scope, e.Limit, e.Max, e.Remaining)
+	case PerTransfer:
+		return fmt.Sprintf("%s %s limit of %s %s exceeded", e.Scope, e.Limit, utils.NewMoney(e.Max, e.Currency).Decimal(), e.Currency)
+	}
+	return fmt.Sprintf("%s %s limit of %s %s exceeded, %s remaining", e.Scope, e.Limit,
+		utils.NewMoney(e.Max, e.Currency).Decimal(), e.Currency, utils.NewMoney(e.Remaining, e.Currency).Decimal())
+}
+
+func (e *ExceededError) Is(target error) bool {
+	return target == ErrExceeded
+}
+
+// limits set in other replace those in l
+func (l Limits) Override(other Limits) Limits {
+	for _, field := range []struct{ dst, src *sql.NullInt64 }{
+		{&l.PerTransfer, &other.PerTransfer},
+		{&l.Daily, &other.Daily},
+		{&l.Weekly, &other.Weekly},
+		{&l.Monthly, &other.Monthly},
+		{&l.HourlyCount, &other.HourlyCount},
+	} {
+		if field.src.Valid {
+			*field.dst = *field.src
+		}
+	}
+	return l
+}
+
+func (l Limits) IsSet() bool {
+	return l.PerTransfer.Valid || l.Daily.Valid || l.Weekly.Valid || l.Monthly.Valid || l.HourlyCount.Valid
+}
+
+// usage after one more transfer of amount
+func (u Usage) Add(amount int64) Usage {
+	return Usage{
+		Daily:       u.Daily + amount,
+		Weekly:      u.Weekly + amount,
+		Monthly:     u.Monthly + amount,
+		HourlyCount: u.HourlyCount + 1,
+	}
+}
+
+// check that one more transfer of amount fits, the first violated limit is returned
+func Check(scope string, l Limits, usage Usage, amount utils.Money) error {
+	if l.PerTransfer.Valid && amount.Amount > l.PerTransfer.Int64 {
+		return &ExceededError{
+			Scope:    scope,
+			Limit:    PerTransfer,
+			Currency: amount.Currency,
+			Max:      l.PerTransfer.Int64,
+		}
+	}
+
+	for _, window := range []struct {
+		limit string
+		max   sql.NullInt64
+		used  int64
+	}{
+		{Daily, l.Daily, usage.Daily},
+		{Weekly, l.Weekly, usage.Weekly},
+		{Monthly, l.Monthly, usage.Monthly},
+	} {
+		if window.max.Valid && window.used+amount.Amount > window.max.Int64 {
+			return &ExceededError{
+				Scope:     scope,
+				Limit:     window.limit,
+				Currency:  amount.Currency,
+				Max:       window.max.Int64,
+				Remaining: max(window.max.Int64-window.used, 0),
+			}
+		}
+	}
+
+	if l.HourlyCount.Valid && usage.HourlyCount >= l.HourlyCount.Int64 {
+		return &ExceededError{
+			Scope:     scope,
+			Limit:     HourlyCount,
+			Max:       l.HourlyCount.Int64,
+			Remaining: 0,
+		}
+	}
+	return nil
+}
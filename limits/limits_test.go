@@ -0,0 +1,77 @@
+package limits
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func limit(n int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: n, Valid: true}
+}
+
+func TestOverride(t *testing.T) {
+	global := Limits{PerTransfer: limit(1000), Daily: limit(5000), HourlyCount: limit(10)}
+	tier := Limits{Daily: limit(20000)}
+
+	got := global.Override(tier)
+	require.Equal(t, limit(1000), got.PerTransfer)
+	require.Equal(t, limit(20000), got.Daily)
+	require.Equal(t, limit(10), got.HourlyCount)
+	require.False(t, got.Weekly.Valid)
+
+	require.True(t, got.IsSet())
+	require.False(t, Limits{}.IsSet())
+}
+
+func TestCheck(t *testing.T) {
+	l := Limits{
+		PerTransfer: limit(1000),
+		Daily:       limit(2000),
+		Weekly:      limit(5000),
+		HourlyCount: limit(3),
+	}
+
+	testCases := []struct {
+		name      string
+		usage     Usage
+		amount    int64
+		limit     string
+		remaining int64
+	}{
+		{name: "OK", usage: Usage{Daily: 1000, Weekly: 1000, HourlyCount: 2}, amount: 1000},
+		{name: "PerTransfer", amount: 1001, limit: PerTransfer},
+		{name: "Daily", usage: Usage{Daily: 1500, Weekly: 1500}, amount: 600, limit: Daily, remaining: 500},
+		{name: "Weekly", usage: Usage{Daily: 0, Weekly: 4800}, amount: 300, limit: Weekly, remaining: 200},
+		{name: "Overspent", usage: Usage{Daily: 2500, Weekly: 2500}, amount: 1, limit: Daily, remaining: 0},
+		{name: "HourlyCount", usage: Usage{HourlyCount: 3}, amount: 1, limit: HourlyCount, remaining: 0},
+		// monthly is unlimited
+		{name: "Unlimited", usage: Usage{Monthly: 1 << 40}, amount: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Check(ScopeUser, l, tc.usage, utils.NewMoney(tc.amount, utils.USD))
+			if tc.limit == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, ErrExceeded)
+			var exceeded *ExceededError
+			require.True(t, errors.As(err, &exceeded))
+			require.Equal(t, ScopeUser, exceeded.Scope)
+			require.Equal(t, tc.limit, exceeded.Limit)
+			require.Equal(t, tc.remaining, exceeded.Remaining)
+			require.NotEmpty(t, exceeded.Error())
+		})
+	}
+}
+
+func TestUsageAdd(t *testing.T) {
+	usage := Usage{Daily: 100, Weekly: 300, Monthly: 900, HourlyCount: 2}.Add(50)
+	require.Equal(t, Usage{Daily: 150, Weekly: 350, Monthly: 950, HourlyCount: 3}, usage)
+}
@@ -1,6 +1,7 @@
 package mail
 
 import (
+	"bytes"
 	"fmt"
 	"net/smtp"
 
@@ -12,6 +13,13 @@ const (
 	smtpServerAddress = "smtp.gmail.com:587"
 )
 
+// file generated in memory, e.g. a statement
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
 type EmailSender interface {
 	SendEmail(
 		subject string,
@@ -19,7 +27,8 @@ type EmailSender interface {
 		to []string,
 		cc []string,
 		bcc []string,
-		attachments []string,
+		attachFiles []string,
+		attachments []Attachment,
 	) error
 }
 
@@ -43,7 +52,8 @@ func (s *Sender) SendEmail(
 	to []string,
 	cc []string,
 	bcc []string,
-	attachments []string,
+	attachFiles []string,
+	attachments []Attachment,
 ) error {
 	e := email.NewEmail()
 	e.From = fmt.Sprintf("%s <%s>", s.name, s.fromAddress)
@@ -53,8 +63,17 @@ func (s *Sender) SendEmail(
 	e.Subject = subject
 	e.HTML = []byte(content)
 
-	for _, file := range attachments {
-		e.AttachFile(file)
+	for _, file := range attachFiles {
+		if _, err := e.AttachFile(file); err != nil {
+			return fmt.Errorf("failed to attach file %s: %w", file, err)
+		}
+	}
+
+	for _, attachment := range attachments {
+		_, err := e.Attach(bytes.NewReader(attachment.Content), attachment.Filename, attachment.ContentType)
+		if err != nil {
+			return fmt.Errorf("failed to attach %s: %w", attachment.Filename, err)
+		}
 	}
 
 	return e.Send(smtpServerAddress, smtp.PlainAuth("", s.fromAddress, s.fromPassword, smtpAuthAddress))
@@ -8,10 +8,15 @@ import (
 	"os"
 
 	"github.com/dxtym/bankrupt/api"
+	"github.com/dxtym/bankrupt/currency"
 	db "github.com/dxtym/bankrupt/db/sqlc"
 	_ "github.com/dxtym/bankrupt/doc/statik"
+	"github.com/dxtym/bankrupt/fx"
 	"github.com/dxtym/bankrupt/gapi"
+	"github.com/dxtym/bankrupt/mail"
+	"github.com/dxtym/bankrupt/outbox"
 	"github.com/dxtym/bankrupt/pb"
+	"github.com/dxtym/bankrupt/reconcile"
 	"github.com/dxtym/bankrupt/utils"
 	"github.com/dxtym/bankrupt/worker"
 	"github.com/golang-migrate/migrate/v4"
@@ -52,9 +57,27 @@ func main() {
 	}
 	taskDistributor := worker.NewRedisTaskDistributor(redisOpt)
 
-	go runProcessor(redisOpt, store)
-	go runGRPCServer(config, store, taskDistributor)
-	runGatewayServer(config, store, taskDistributor)
+	// one-off reconciliation, e.g. before or after a deploy
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		runReconciliation(store, taskDistributor)
+		return
+	}
+
+	// shared so currency updates invalidate both servers
+	currencies := currency.NewRegistry(store, config.CurrencyCacheTTL)
+	if err := currencies.Refresh(context.Background()); err != nil {
+		log.Fatal().Msgf("cannot load currencies: %s", err)
+	}
+
+	rateProvider := fx.NewFileRateProvider(config.FXRatesFile)
+
+	mailer := mail.NewEmailSender(config.EmailSenderName, config.EmailSenderAddress, config.EmailSenderPassword)
+
+	go runOutboxRelay(config, store, taskDistributor)
+	go runProcessor(config, redisOpt, store, taskDistributor, mailer, rateProvider)
+	go runScheduler(config, redisOpt)
+	go runGRPCServer(config, store, taskDistributor, currencies)
+	runGatewayServer(config, store, taskDistributor, currencies)
 }
 
 func runDbMigration(migrateURL, source string) {
@@ -69,22 +92,105 @@ func runDbMigration(migrateURL, source string) {
 	log.Info().Msg("db migrated succesfully")
 }
 
-func runProcessor(redisOpt asynq.RedisClientOpt, store db.Store) {
-	rtp := worker.NewRedisTaskProcessor(redisOpt, store)
+func runOutboxRelay(config utils.Config, store db.Store, td worker.TaskDistributor) {
+	relay := outbox.NewRelay(store, td)
+	log.Info().Msg("starting outbox relay")
+	relay.Run(context.Background(), config.OutboxRelayInterval)
+}
+
+func runProcessor(
+	config utils.Config,
+	redisOpt asynq.RedisClientOpt,
+	store db.Store,
+	td worker.TaskDistributor,
+	mailer mail.EmailSender,
+	rateProvider fx.RateProvider,
+) {
+	rtp := worker.NewRedisTaskProcessor(redisOpt, store, td, mailer, rateProvider, config.VerifyEmailURL)
 	log.Info().Msg("starting processor")
 	if err := rtp.Run(); err != nil {
 		log.Fatal().Msgf("cannot start processor: %s", err)
 	}
 }
 
-func runGRPCServer(config utils.Config, store db.Store, td worker.TaskDistributor) {
-	server, err := gapi.NewServer(config, store, td)
+func runReconciliation(store db.Store, td worker.TaskDistributor) {
+	reconciler := reconcile.NewReconciler(store, reconcile.DefaultBatchSize)
+	run, _, err := reconciler.Run(context.Background(), reconcile.SourceCLI)
+	if err != nil {
+		log.Fatal().Msgf("cannot reconcile ledger: %s", err)
+	}
+
+	if run.CriticalFindings > 0 {
+		err := td.DistributorTaskSendReconciliationAlert(context.Background(),
+			worker.PayloadSendReconciliationAlert{RunID: run.ID}, asynq.Queue(worker.QueueCritical))
+		if err != nil {
+			log.Error().Err(err).Msg("cannot send reconciliation alert")
+		}
+		os.Exit(1)
+	}
+}
+
+func runScheduler(config utils.Config, redisOpt asynq.RedisClientOpt) {
+	rts := worker.NewRedisTaskScheduler(redisOpt)
+
+	err := rts.SchedulerTaskCleanupIdempotencyKeys("@hourly", worker.PayloadCleanupIdempotencyKeys{
+		TTL: config.IdempotencyKeyTTL,
+	}, asynq.Queue(worker.QueueDefault))
+	if err != nil {
+		log.Fatal().Msgf("cannot schedule task: %s", err)
+	}
+
+	err = rts.SchedulerTaskRefreshFXRates(config.FXRatesCronspec, worker.PayloadRefreshFXRates{},
+		asynq.Queue(worker.QueueDefault))
+	if err != nil {
+		log.Fatal().Msgf("cannot schedule task: %s", err)
+	}
+
+	err = rts.SchedulerTaskExpireHolds(config.HoldExpiryCronspec, worker.PayloadExpireHolds{},
+		asynq.Queue(worker.QueueDefault))
+	if err != nil {
+		log.Fatal().Msgf("cannot schedule task: %s", err)
+	}
+
+	err = rts.SchedulerTaskRunScheduledTransfers(config.ScheduledTransfersCronspec, worker.PayloadRunScheduledTransfers{},
+		asynq.Queue(worker.QueueCritical))
+	if err != nil {
+		log.Fatal().Msgf("cannot schedule task: %s", err)
+	}
+
+	err = rts.SchedulerTaskReconcileLedger(config.ReconciliationCronspec, worker.PayloadReconcileLedger{},
+		asynq.Queue(worker.QueueDefault))
+	if err != nil {
+		log.Fatal().Msgf("cannot schedule task: %s", err)
+	}
+
+	err = rts.SchedulerTaskSendMonthlyStatements(config.StatementsCronspec, worker.PayloadSendMonthlyStatements{},
+		asynq.Queue(worker.QueueDefault))
+	if err != nil {
+		log.Fatal().Msgf("cannot schedule task: %s", err)
+	}
+
+	err = rts.SchedulerTaskPurgeExpiredSessions(config.SessionsPurgeCronspec, worker.PayloadPurgeExpiredSessions{},
+		asynq.Queue(worker.QueueDefault))
+	if err != nil {
+		log.Fatal().Msgf("cannot schedule task: %s", err)
+	}
+
+	log.Info().Msg("starting scheduler")
+	if err := rts.Run(); err != nil {
+		log.Fatal().Msgf("cannot start scheduler: %s", err)
+	}
+}
+
+func runGRPCServer(config utils.Config, store db.Store, td worker.TaskDistributor, currencies *currency.Registry) {
+	server, err := gapi.NewServer(config, store, td, currencies)
 	if err != nil {
 		log.Fatal().Msgf("cannot create server: %s", err)
 	}
 
 	logger := grpc.UnaryInterceptor(gapi.GrpcLogger)
-	grpcServer := grpc.NewServer(logger)
+	streamLogger := grpc.StreamInterceptor(gapi.GrpcStreamLogger)
+	grpcServer := grpc.NewServer(logger, streamLogger)
 	pb.RegisterBankruptServer(grpcServer, server)
 	reflection.Register(grpcServer)
 
@@ -99,8 +205,8 @@ func runGRPCServer(config utils.Config, store db.Store, td worker.TaskDistributo
 	}
 }
 
-func runGatewayServer(config utils.Config, store db.Store, td worker.TaskDistributor) {
-	server, err := gapi.NewServer(config, store, td)
+func runGatewayServer(config utils.Config, store db.Store, td worker.TaskDistributor, currencies *currency.Registry) {
+	server, err := gapi.NewServer(config, store, td, currencies)
 	if err != nil {
 		log.Fatal().Msgf("cannot create server: %s", err)
 	}
@@ -112,7 +218,7 @@ func runGatewayServer(config utils.Config, store db.Store, td worker.TaskDistrib
 		UnmarshalOptions: protojson.UnmarshalOptions{
 			DiscardUnknown: true,
 		},
-	}))
+	}), runtime.WithIncomingHeaderMatcher(gapi.HeaderMatcher))
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -147,8 +253,8 @@ func runGatewayServer(config utils.Config, store db.Store, td worker.TaskDistrib
 	}
 }
 
-func runHTTPServer(config utils.Config, store db.Store) {
-	server, err := api.NewServer(config, store)
+func runHTTPServer(config utils.Config, store db.Store, currencies *currency.Registry) {
+	server, err := api.NewServer(config, store, currencies)
 	if err != nil {
 		log.Fatal().Msgf("cannot create server: %s", err)
 	}
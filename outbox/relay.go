@@ -0,0 +1,159 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DefaultBatchSize   = 100
+	DefaultMaxAttempts = 20
+	DefaultRetention   = 7 * 24 * time.Hour
+)
+
+// longest wait between attempts of one message
+const maxBackoff = 5 * time.Minute
+
+// how long a published task id must stay reserved, a message is published again at the latest
+// after all of its backoffs, which adds up to about an hour with the defaults
+const TaskRetention = 24 * time.Hour
+
+// queries needed by the relay, satisfied by db.Store
+type Store interface {
+	ClaimOutboxMessagesTx(ctx context.Context, arg db.ClaimOutboxMessagesTxParams) error
+	DeletePublishedOutboxMessages(ctx context.Context, publishedBefore time.Time) (int64, error)
+}
+
+// satisfied by worker.TaskDistributor
+type Publisher interface {
+	DistributorOutboxMessage(ctx context.Context, message db.OutboxMessage) error
+}
+
+// publishes committed outbox messages in id order, a message that can't be
+// published holds back the ones after it until it succeeds or is given up,
+// every app instance runs one and each claims its own batch of rows
+type Relay struct {
+	store       Store
+	publisher   Publisher
+	batchSize   int32
+	maxAttempts int32
+	now         func() time.Time
+}
+
+func NewRelay(store Store, publisher Publisher) *Relay {
+	return &Relay{
+		store:       store,
+		publisher:   publisher,
+		batchSize:   DefaultBatchSize,
+		maxAttempts: DefaultMaxAttempts,
+		now:         time.Now,
+	}
+}
+
+// poll until ctx is done, published messages are kept for a while for debugging
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var cleaned time.Time
+	for {
+		if _, err := r.Publish(ctx); err != nil {
+			log.Error().Err(err).Msg("cannot relay outbox")
+		}
+
+		if r.now().Sub(cleaned) > time.Hour {
+			deleted, err := r.store.DeletePublishedOutboxMessages(ctx, r.now().Add(-DefaultRetention))
+			if err != nil {
+				log.Error().Err(err).Msg("cannot delete published outbox messages")
+			} else {
+				cleaned = r.now()
+				log.Info().Int64("deleted", deleted).Msg("outbox cleaned")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish pending messages until the first one that isn't due or fails
+func (r *Relay) Publish(ctx context.Context) (int, error) {
+	var published int
+	for {
+		var claimed int
+		var stopped bool
+		var publishErr error
+		err := r.store.ClaimOutboxMessagesTx(ctx, db.ClaimOutboxMessagesTxParams{
+			Limit: r.batchSize,
+			Publish: func(q db.OutboxQuerier, messages []db.OutboxMessage) error {
+				claimed = len(messages)
+				for _, message := range messages {
+					if message.AvailableAt.After(r.now()) {
+						stopped = true
+						return nil
+					}
+
+					if err := r.publisher.DistributorOutboxMessage(ctx, message); err != nil {
+						// the failure is recorded when the claim commits
+						publishErr = fmt.Errorf("cannot publish outbox message [%d]: %w", message.ID, err)
+						return r.fail(ctx, q, message, err)
+					}
+
+					// a task sent before a rollback is sent again, its task id makes that a no-op
+					if err := q.MarkOutboxMessagePublished(ctx, message.ID); err != nil {
+						return fmt.Errorf("cannot mark outbox message [%d] published: %w", message.ID, err)
+					}
+					published++
+				}
+				return nil
+			},
+		})
+		if err != nil {
+			return published, fmt.Errorf("cannot relay outbox messages: %w", err)
+		}
+		if publishErr != nil {
+			return published, publishErr
+		}
+
+		if stopped || claimed < int(r.batchSize) {
+			return published, nil
+		}
+	}
+}
+
+// back off exponentially, give up after max attempts
+func (r *Relay) fail(ctx context.Context, q db.OutboxQuerier, message db.OutboxMessage, cause error) error {
+	attempts := message.Attempts + 1
+
+	dead := attempts >= r.maxAttempts
+	_, err := q.MarkOutboxMessageFailed(ctx, db.MarkOutboxMessageFailedParams{
+		ID:          message.ID,
+		LastError:   cause.Error(),
+		AvailableAt: r.now().Add(backoff(attempts)),
+		Dead:        dead,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot mark outbox message [%d] failed: %w", message.ID, err)
+	}
+
+	if dead {
+		log.Error().Err(cause).Int64("outbox_id", message.ID).Str("type", message.TaskType).
+			Int32("attempts", attempts).Msg("outbox message given up")
+	}
+	return nil
+}
+
+// wait before the next attempt of a message that failed attempts times
+func backoff(attempts int32) time.Duration {
+	if attempts < 10 && time.Second<<attempts < maxBackoff {
+		return time.Second << attempts
+	}
+	return maxBackoff
+}
@@ -0,0 +1,160 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+var errRedisDown = errors.New("redis down")
+
+// keeps messages in id order like the query
+type fakeStore struct {
+	messages []db.OutboxMessage
+}
+
+func (f *fakeStore) ClaimOutboxMessagesTx(ctx context.Context, arg db.ClaimOutboxMessagesTxParams) error {
+	var pending []db.OutboxMessage
+	for _, message := range f.messages {
+		if !message.PublishedAt.Valid && !message.DeadAt.Valid && len(pending) < int(arg.Limit) {
+			pending = append(pending, message)
+		}
+	}
+	return arg.Publish(f, pending)
+}
+
+func (f *fakeStore) MarkOutboxMessagePublished(ctx context.Context, id int64) error {
+	message := f.get(id)
+	message.Attempts++
+	message.PublishedAt.Valid = true
+	return nil
+}
+
+func (f *fakeStore) MarkOutboxMessageFailed(ctx context.Context, arg db.MarkOutboxMessageFailedParams) (db.OutboxMessage, error) {
+	message := f.get(arg.ID)
+	message.Attempts++
+	message.LastError = arg.LastError
+	message.AvailableAt = arg.AvailableAt
+	message.DeadAt.Valid = arg.Dead
+	return *message, nil
+}
+
+func (f *fakeStore) DeletePublishedOutboxMessages(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeStore) get(id int64) *db.OutboxMessage {
+	for i := range f.messages {
+		if f.messages[i].ID == id {
+			return &f.messages[i]
+		}
+	}
+	return nil
+}
+
+// fails messages in down until they are removed
+type fakePublisher struct {
+	published []int64
+	down      map[int64]bool
+}
+
+func (f *fakePublisher) DistributorOutboxMessage(ctx context.Context, message db.OutboxMessage) error {
+	if f.down[message.ID] {
+		return errRedisDown
+	}
+	f.published = append(f.published, message.ID)
+	return nil
+}
+
+func newTestRelay(n int, now time.Time) (*Relay, *fakeStore, *fakePublisher) {
+	store := &fakeStore{}
+	for i := 1; i <= n; i++ {
+		store.messages = append(store.messages, db.OutboxMessage{ID: int64(i), AvailableAt: now.Add(-time.Minute)})
+	}
+	publisher := &fakePublisher{down: map[int64]bool{}}
+
+	relay := NewRelay(store, publisher)
+	relay.batchSize = 2
+	relay.now = func() time.Time { return now }
+	return relay, store, publisher
+}
+
+func TestPublish(t *testing.T) {
+	relay, store, publisher := newTestRelay(5, time.Now())
+
+	published, err := relay.Publish(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 5, published)
+	require.Equal(t, []int64{1, 2, 3, 4, 5}, publisher.published)
+
+	for _, message := range store.messages {
+		require.True(t, message.PublishedAt.Valid)
+	}
+
+	// nothing left
+	published, err = relay.Publish(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, published)
+}
+
+func TestPublishKeepsOrder(t *testing.T) {
+	now := time.Now()
+	relay, store, publisher := newTestRelay(4, now)
+	publisher.down[2] = true
+
+	published, err := relay.Publish(context.Background())
+	require.ErrorIs(t, err, errRedisDown)
+	require.Equal(t, 1, published)
+	require.Equal(t, []int64{1}, publisher.published)
+
+	failed := store.get(2)
+	require.Equal(t, int32(1), failed.Attempts)
+	require.Equal(t, errRedisDown.Error(), failed.LastError)
+	require.Equal(t, now.Add(2*time.Second), failed.AvailableAt)
+
+	// later messages wait while the failed one backs off
+	published, err = relay.Publish(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, published)
+
+	delete(publisher.down, 2)
+	relay.now = func() time.Time { return now.Add(time.Minute) }
+
+	published, err = relay.Publish(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, published)
+	require.Equal(t, []int64{1, 2, 3, 4}, publisher.published)
+}
+
+func TestPublishGivesUp(t *testing.T) {
+	now := time.Now()
+	relay, store, publisher := newTestRelay(2, now)
+	publisher.down[1] = true
+	store.get(1).Attempts = DefaultMaxAttempts - 1
+
+	_, err := relay.Publish(context.Background())
+	require.ErrorIs(t, err, errRedisDown)
+
+	dead := store.get(1)
+	require.True(t, dead.DeadAt.Valid)
+	require.Equal(t, now.Add(maxBackoff), dead.AvailableAt)
+
+	// dead message no longer holds back the next one
+	published, err := relay.Publish(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+	require.Equal(t, []int64{2}, publisher.published)
+}
+
+// a task id must outlive every retry of its message
+func TestTaskRetention(t *testing.T) {
+	var window time.Duration
+	for attempts := int32(1); attempts < DefaultMaxAttempts; attempts++ {
+		window += backoff(attempts)
+	}
+	require.Less(t, window, TaskRetention)
+}
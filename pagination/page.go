@@ -0,0 +1,34 @@
+package pagination
+
+import "errors"
+
+var ErrInvalidPageSize = errors.New("page size must not be negative")
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// zero page size means default, larger than max is capped
+func PageSize(pageSize int32, defaultSize, maxSize int32) (int32, error) {
+	switch {
+	case pageSize < 0:
+		return 0, ErrInvalidPageSize
+	case pageSize == 0:
+		return defaultSize, nil
+	case pageSize > maxSize:
+		return maxSize, nil
+	}
+	return pageSize, nil
+}
+
+// rows are fetched with page size + 1, the extra row only tells another page follows
+func Trim[T any](rows []T, pageSize int32, cursor func(T) Cursor) ([]T, *Cursor) {
+	if len(rows) <= int(pageSize) {
+		return rows, nil
+	}
+
+	rows = rows[:pageSize]
+	next := cursor(rows[len(rows)-1])
+	return rows, &next
+}
@@ -0,0 +1,82 @@
+package pagination
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageToken(t *testing.T) {
+	signer := NewSigner(utils.RandomString(32))
+	cursor := &Cursor{CreatedAt: time.Now().UTC().Truncate(time.Microsecond), ID: 42, Amount: 1250}
+
+	token := signer.Encode(cursor, "accounts:alice")
+	require.NotEmpty(t, token)
+
+	got, err := signer.Decode(token, "accounts:alice")
+	require.NoError(t, err)
+	require.True(t, cursor.CreatedAt.Equal(got.CreatedAt))
+	require.Equal(t, cursor.ID, got.ID)
+	require.Equal(t, cursor.Amount, got.Amount)
+
+	// first page
+	require.Empty(t, signer.Encode(nil, "accounts:alice"))
+	got, err = signer.Decode("", "accounts:alice")
+	require.NoError(t, err)
+	require.Zero(t, got)
+
+	// token of another query
+	_, err = signer.Decode(token, "accounts:bob")
+	require.ErrorIs(t, err, ErrInvalidPageToken)
+
+	// token signed with another key
+	_, err = NewSigner(utils.RandomString(32)).Decode(token, "accounts:alice")
+	require.ErrorIs(t, err, ErrInvalidPageToken)
+
+	// tampered cursor
+	body, signature, _ := strings.Cut(token, ".")
+	forged := signer.Encode(&Cursor{ID: 1}, "accounts:alice")
+	forgedBody, _, _ := strings.Cut(forged, ".")
+	_, err = signer.Decode(forgedBody+"."+signature, "accounts:alice")
+	require.ErrorIs(t, err, ErrInvalidPageToken)
+
+	for _, token := range []string{"garbage", body, body + ".", "." + signature} {
+		_, err = signer.Decode(token, "accounts:alice")
+		require.ErrorIs(t, err, ErrInvalidPageToken)
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	testCases := []struct {
+		pageSize int32
+		expected int32
+		err      error
+	}{
+		{pageSize: 0, expected: DefaultPageSize},
+		{pageSize: 5, expected: 5},
+		{pageSize: MaxPageSize + 1, expected: MaxPageSize},
+		{pageSize: -1, err: ErrInvalidPageSize},
+	}
+
+	for _, tc := range testCases {
+		pageSize, err := PageSize(tc.pageSize, DefaultPageSize, MaxPageSize)
+		require.ErrorIs(t, err, tc.err)
+		require.Equal(t, tc.expected, pageSize)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	ids := []int64{1, 2, 3}
+	cursor := func(id int64) Cursor { return Cursor{ID: id} }
+
+	rows, next := Trim(ids, 3, cursor)
+	require.Equal(t, ids, rows)
+	require.Nil(t, next)
+
+	rows, next = Trim(ids, 2, cursor)
+	require.Equal(t, []int64{1, 2}, rows)
+	require.Equal(t, int64(2), next.ID)
+}
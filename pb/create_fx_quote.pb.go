@@ -0,0 +1,226 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.1
+// source: create_fx_quote.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateFXQuoteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Amount     *Money `protobuf:"bytes,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	ToCurrency string `protobuf:"bytes,2,opt,name=to_currency,json=toCurrency,proto3" json:"to_currency,omitempty"`
+}
+
+func (x *CreateFXQuoteRequest) Reset() {
+	*x = CreateFXQuoteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_create_fx_quote_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateFXQuoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFXQuoteRequest) ProtoMessage() {}
+
+func (x *CreateFXQuoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_create_fx_quote_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFXQuoteRequest.ProtoReflect.Descriptor instead.
+func (*CreateFXQuoteRequest) Descriptor() ([]byte, []int) {
+	return file_create_fx_quote_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateFXQuoteRequest) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *CreateFXQuoteRequest) GetToCurrency() string {
+	if x != nil {
+		return x.ToCurrency
+	}
+	return ""
+}
+
+type CreateFXQuoteResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Quote *FXQuote `protobuf:"bytes,1,opt,name=quote,proto3" json:"quote,omitempty"`
+}
+
+func (x *CreateFXQuoteResponse) Reset() {
+	*x = CreateFXQuoteResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_create_fx_quote_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateFXQuoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFXQuoteResponse) ProtoMessage() {}
+
+func (x *CreateFXQuoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_create_fx_quote_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFXQuoteResponse.ProtoReflect.Descriptor instead.
+func (*CreateFXQuoteResponse) Descriptor() ([]byte, []int) {
+	return file_create_fx_quote_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateFXQuoteResponse) GetQuote() *FXQuote {
+	if x != nil {
+		return x.Quote
+	}
+	return nil
+}
+
+var File_create_fx_quote_proto protoreflect.FileDescriptor
+
+var file_create_fx_quote_proto_rawDesc = []byte{
+	0x0a, 0x15, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x66, 0x78, 0x5f, 0x71, 0x75, 0x6f, 0x74,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x1a, 0x08, 0x66, 0x78, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0b, 0x6d, 0x6f, 0x6e, 0x65, 0x79, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0x5a, 0x0a, 0x14, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x58, 0x51, 0x75,
+	0x6f, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x06, 0x61, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x70, 0x62, 0x2e,
+	0x4d, 0x6f, 0x6e, 0x65, 0x79, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1f, 0x0a,
+	0x0b, 0x74, 0x6f, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x74, 0x6f, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x3a,
+	0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x46, 0x58, 0x51, 0x75, 0x6f, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x71, 0x75, 0x6f, 0x74, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x62, 0x2e, 0x46, 0x58, 0x51, 0x75,
+	0x6f, 0x74, 0x65, 0x52, 0x05, 0x71, 0x75, 0x6f, 0x74, 0x65, 0x42, 0x1e, 0x5a, 0x1c, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x78, 0x74, 0x79, 0x6d, 0x2f, 0x62,
+	0x61, 0x6e, 0x6b, 0x72, 0x75, 0x70, 0x74, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_create_fx_quote_proto_rawDescOnce sync.Once
+	file_create_fx_quote_proto_rawDescData = file_create_fx_quote_proto_rawDesc
+)
+
+func file_create_fx_quote_proto_rawDescGZIP() []byte {
+	file_create_fx_quote_proto_rawDescOnce.Do(func() {
+		file_create_fx_quote_proto_rawDescData = protoimpl.X.CompressGZIP(file_create_fx_quote_proto_rawDescData)
+	})
+	return file_create_fx_quote_proto_rawDescData
+}
+
+var file_create_fx_quote_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_create_fx_quote_proto_goTypes = []any{
+	(*CreateFXQuoteRequest)(nil),  // 0: pb.CreateFXQuoteRequest
+	(*CreateFXQuoteResponse)(nil), // 1: pb.CreateFXQuoteResponse
+	(*Money)(nil),                 // 2: pb.Money
+	(*FXQuote)(nil),               // 3: pb.FXQuote
+}
+var file_create_fx_quote_proto_depIdxs = []int32{
+	2, // 0: pb.CreateFXQuoteRequest.amount:type_name -> pb.Money
+	3, // 1: pb.CreateFXQuoteResponse.quote:type_name -> pb.FXQuote
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_create_fx_quote_proto_init() }
+func file_create_fx_quote_proto_init() {
+	if File_create_fx_quote_proto != nil {
+		return
+	}
+	file_fx_proto_init()
+	file_money_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_create_fx_quote_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateFXQuoteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_create_fx_quote_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateFXQuoteResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_create_fx_quote_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_create_fx_quote_proto_goTypes,
+		DependencyIndexes: file_create_fx_quote_proto_depIdxs,
+		MessageInfos:      file_create_fx_quote_proto_msgTypes,
+	}.Build()
+	File_create_fx_quote_proto = out.File
+	file_create_fx_quote_proto_rawDesc = nil
+	file_create_fx_quote_proto_goTypes = nil
+	file_create_fx_quote_proto_depIdxs = nil
+}
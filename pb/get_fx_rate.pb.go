@@ -0,0 +1,237 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.1
+// source: get_fx_rate.proto
+
+package pb
+
+import (
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetFXRateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FromCurrency string               `protobuf:"bytes,1,opt,name=from_currency,json=fromCurrency,proto3" json:"from_currency,omitempty"`
+	ToCurrency   string               `protobuf:"bytes,2,opt,name=to_currency,json=toCurrency,proto3" json:"to_currency,omitempty"`
+	At           *timestamp.Timestamp `protobuf:"bytes,3,opt,name=at,proto3" json:"at,omitempty"` // defaults to now
+}
+
+func (x *GetFXRateRequest) Reset() {
+	*x = GetFXRateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_get_fx_rate_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFXRateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFXRateRequest) ProtoMessage() {}
+
+func (x *GetFXRateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_get_fx_rate_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFXRateRequest.ProtoReflect.Descriptor instead.
+func (*GetFXRateRequest) Descriptor() ([]byte, []int) {
+	return file_get_fx_rate_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetFXRateRequest) GetFromCurrency() string {
+	if x != nil {
+		return x.FromCurrency
+	}
+	return ""
+}
+
+func (x *GetFXRateRequest) GetToCurrency() string {
+	if x != nil {
+		return x.ToCurrency
+	}
+	return ""
+}
+
+func (x *GetFXRateRequest) GetAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+type GetFXRateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rate *FXRate `protobuf:"bytes,1,opt,name=rate,proto3" json:"rate,omitempty"`
+}
+
+func (x *GetFXRateResponse) Reset() {
+	*x = GetFXRateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_get_fx_rate_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFXRateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFXRateResponse) ProtoMessage() {}
+
+func (x *GetFXRateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_get_fx_rate_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFXRateResponse.ProtoReflect.Descriptor instead.
+func (*GetFXRateResponse) Descriptor() ([]byte, []int) {
+	return file_get_fx_rate_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetFXRateResponse) GetRate() *FXRate {
+	if x != nil {
+		return x.Rate
+	}
+	return nil
+}
+
+var File_get_fx_rate_proto protoreflect.FileDescriptor
+
+var file_get_fx_rate_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x67, 0x65, 0x74, 0x5f, 0x66, 0x78, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x08, 0x66, 0x78, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0x84, 0x01, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x46, 0x58, 0x52, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x66, 0x72, 0x6f, 0x6d, 0x5f,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x66, 0x72, 0x6f, 0x6d, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1f, 0x0a, 0x0b,
+	0x74, 0x6f, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x74, 0x6f, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x2a, 0x0a,
+	0x02, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x61, 0x74, 0x22, 0x33, 0x0a, 0x11, 0x47, 0x65, 0x74,
+	0x46, 0x58, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e,
+	0x0a, 0x04, 0x72, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x70,
+	0x62, 0x2e, 0x46, 0x58, 0x52, 0x61, 0x74, 0x65, 0x52, 0x04, 0x72, 0x61, 0x74, 0x65, 0x42, 0x1e,
+	0x5a, 0x1c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x78, 0x74,
+	0x79, 0x6d, 0x2f, 0x62, 0x61, 0x6e, 0x6b, 0x72, 0x75, 0x70, 0x74, 0x2f, 0x70, 0x62, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_get_fx_rate_proto_rawDescOnce sync.Once
+	file_get_fx_rate_proto_rawDescData = file_get_fx_rate_proto_rawDesc
+)
+
+func file_get_fx_rate_proto_rawDescGZIP() []byte {
+	file_get_fx_rate_proto_rawDescOnce.Do(func() {
+		file_get_fx_rate_proto_rawDescData = protoimpl.X.CompressGZIP(file_get_fx_rate_proto_rawDescData)
+	})
+	return file_get_fx_rate_proto_rawDescData
+}
+
+var file_get_fx_rate_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_get_fx_rate_proto_goTypes = []any{
+	(*GetFXRateRequest)(nil),    // 0: pb.GetFXRateRequest
+	(*GetFXRateResponse)(nil),   // 1: pb.GetFXRateResponse
+	(*timestamp.Timestamp)(nil), // 2: google.protobuf.Timestamp
+	(*FXRate)(nil),              // 3: pb.FXRate
+}
+var file_get_fx_rate_proto_depIdxs = []int32{
+	2, // 0: pb.GetFXRateRequest.at:type_name -> google.protobuf.Timestamp
+	3, // 1: pb.GetFXRateResponse.rate:type_name -> pb.FXRate
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_get_fx_rate_proto_init() }
+func file_get_fx_rate_proto_init() {
+	if File_get_fx_rate_proto != nil {
+		return
+	}
+	file_fx_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_get_fx_rate_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*GetFXRateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_get_fx_rate_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*GetFXRateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_get_fx_rate_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_get_fx_rate_proto_goTypes,
+		DependencyIndexes: file_get_fx_rate_proto_depIdxs,
+		MessageInfos:      file_get_fx_rate_proto_msgTypes,
+	}.Build()
+	File_get_fx_rate_proto = out.File
+	file_get_fx_rate_proto_rawDesc = nil
+	file_get_fx_rate_proto_goTypes = nil
+	file_get_fx_rate_proto_depIdxs = nil
+}
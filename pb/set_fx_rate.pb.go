@@ -0,0 +1,260 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.1
+// source: set_fx_rate.proto
+
+package pb
+
+import (
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SetFXRateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FromCurrency  string               `protobuf:"bytes,1,opt,name=from_currency,json=fromCurrency,proto3" json:"from_currency,omitempty"`
+	ToCurrency    string               `protobuf:"bytes,2,opt,name=to_currency,json=toCurrency,proto3" json:"to_currency,omitempty"`
+	Rate          string               `protobuf:"bytes,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	SpreadBps     *int32               `protobuf:"varint,4,opt,name=spread_bps,json=spreadBps,proto3,oneof" json:"spread_bps,omitempty"`
+	EffectiveFrom *timestamp.Timestamp `protobuf:"bytes,5,opt,name=effective_from,json=effectiveFrom,proto3" json:"effective_from,omitempty"` // defaults to now
+}
+
+func (x *SetFXRateRequest) Reset() {
+	*x = SetFXRateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_set_fx_rate_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetFXRateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetFXRateRequest) ProtoMessage() {}
+
+func (x *SetFXRateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_set_fx_rate_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetFXRateRequest.ProtoReflect.Descriptor instead.
+func (*SetFXRateRequest) Descriptor() ([]byte, []int) {
+	return file_set_fx_rate_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SetFXRateRequest) GetFromCurrency() string {
+	if x != nil {
+		return x.FromCurrency
+	}
+	return ""
+}
+
+func (x *SetFXRateRequest) GetToCurrency() string {
+	if x != nil {
+		return x.ToCurrency
+	}
+	return ""
+}
+
+func (x *SetFXRateRequest) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *SetFXRateRequest) GetSpreadBps() int32 {
+	if x != nil && x.SpreadBps != nil {
+		return *x.SpreadBps
+	}
+	return 0
+}
+
+func (x *SetFXRateRequest) GetEffectiveFrom() *timestamp.Timestamp {
+	if x != nil {
+		return x.EffectiveFrom
+	}
+	return nil
+}
+
+type SetFXRateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Rate *FXRate `protobuf:"bytes,1,opt,name=rate,proto3" json:"rate,omitempty"`
+}
+
+func (x *SetFXRateResponse) Reset() {
+	*x = SetFXRateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_set_fx_rate_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetFXRateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetFXRateResponse) ProtoMessage() {}
+
+func (x *SetFXRateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_set_fx_rate_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetFXRateResponse.ProtoReflect.Descriptor instead.
+func (*SetFXRateResponse) Descriptor() ([]byte, []int) {
+	return file_set_fx_rate_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SetFXRateResponse) GetRate() *FXRate {
+	if x != nil {
+		return x.Rate
+	}
+	return nil
+}
+
+var File_set_fx_rate_proto protoreflect.FileDescriptor
+
+var file_set_fx_rate_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x73, 0x65, 0x74, 0x5f, 0x66, 0x78, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x08, 0x66, 0x78, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0xe2, 0x01, 0x0a, 0x10, 0x53, 0x65, 0x74, 0x46, 0x58, 0x52, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x66, 0x72, 0x6f, 0x6d, 0x5f,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c,
+	0x66, 0x72, 0x6f, 0x6d, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1f, 0x0a, 0x0b,
+	0x74, 0x6f, 0x5f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x74, 0x6f, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x12, 0x0a,
+	0x04, 0x72, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x61, 0x74,
+	0x65, 0x12, 0x22, 0x0a, 0x0a, 0x73, 0x70, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x62, 0x70, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x09, 0x73, 0x70, 0x72, 0x65, 0x61, 0x64, 0x42,
+	0x70, 0x73, 0x88, 0x01, 0x01, 0x12, 0x41, 0x0a, 0x0e, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0d, 0x65, 0x66, 0x66, 0x65, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x46, 0x72, 0x6f, 0x6d, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x73, 0x70, 0x72,
+	0x65, 0x61, 0x64, 0x5f, 0x62, 0x70, 0x73, 0x22, 0x33, 0x0a, 0x11, 0x53, 0x65, 0x74, 0x46, 0x58,
+	0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x04,
+	0x72, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x70, 0x62, 0x2e,
+	0x46, 0x58, 0x52, 0x61, 0x74, 0x65, 0x52, 0x04, 0x72, 0x61, 0x74, 0x65, 0x42, 0x1e, 0x5a, 0x1c,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x78, 0x74, 0x79, 0x6d,
+	0x2f, 0x62, 0x61, 0x6e, 0x6b, 0x72, 0x75, 0x70, 0x74, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_set_fx_rate_proto_rawDescOnce sync.Once
+	file_set_fx_rate_proto_rawDescData = file_set_fx_rate_proto_rawDesc
+)
+
+func file_set_fx_rate_proto_rawDescGZIP() []byte {
+	file_set_fx_rate_proto_rawDescOnce.Do(func() {
+		file_set_fx_rate_proto_rawDescData = protoimpl.X.CompressGZIP(file_set_fx_rate_proto_rawDescData)
+	})
+	return file_set_fx_rate_proto_rawDescData
+}
+
+var file_set_fx_rate_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_set_fx_rate_proto_goTypes = []any{
+	(*SetFXRateRequest)(nil),    // 0: pb.SetFXRateRequest
+	(*SetFXRateResponse)(nil),   // 1: pb.SetFXRateResponse
+	(*timestamp.Timestamp)(nil), // 2: google.protobuf.Timestamp
+	(*FXRate)(nil),              // 3: pb.FXRate
+}
+var file_set_fx_rate_proto_depIdxs = []int32{
+	2, // 0: pb.SetFXRateRequest.effective_from:type_name -> google.protobuf.Timestamp
+	3, // 1: pb.SetFXRateResponse.rate:type_name -> pb.FXRate
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_set_fx_rate_proto_init() }
+func file_set_fx_rate_proto_init() {
+	if File_set_fx_rate_proto != nil {
+		return
+	}
+	file_fx_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_set_fx_rate_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*SetFXRateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_set_fx_rate_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*SetFXRateResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_set_fx_rate_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_set_fx_rate_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_set_fx_rate_proto_goTypes,
+		DependencyIndexes: file_set_fx_rate_proto_depIdxs,
+		MessageInfos:      file_set_fx_rate_proto_msgTypes,
+	}.Build()
+	File_set_fx_rate_proto = out.File
+	file_set_fx_rate_proto_rawDesc = nil
+	file_set_fx_rate_proto_goTypes = nil
+	file_set_fx_rate_proto_depIdxs = nil
+}
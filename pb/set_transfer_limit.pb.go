@@ -0,0 +1,301 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.1
+// source: set_transfer_limit.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SetTransferLimitRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tier           string   `protobuf:"bytes,1,opt,name=tier,proto3" json:"tier,omitempty"` // at most one of tier and account_id, global if neither
+	AccountId      int64    `protobuf:"varint,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Currency       string   `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`                                           // required with amount limits, empty with count limit
+	MaxPerTransfer *string  `protobuf:"bytes,4,opt,name=max_per_transfer,json=maxPerTransfer,proto3,oneof" json:"max_per_transfer,omitempty"` // unset limits keep their current value
+	MaxDaily       *string  `protobuf:"bytes,5,opt,name=max_daily,json=maxDaily,proto3,oneof" json:"max_daily,omitempty"`
+	MaxWeekly      *string  `protobuf:"bytes,6,opt,name=max_weekly,json=maxWeekly,proto3,oneof" json:"max_weekly,omitempty"`
+	MaxMonthly     *string  `protobuf:"bytes,7,opt,name=max_monthly,json=maxMonthly,proto3,oneof" json:"max_monthly,omitempty"`
+	MaxHourlyCount *int64   `protobuf:"varint,8,opt,name=max_hourly_count,json=maxHourlyCount,proto3,oneof" json:"max_hourly_count,omitempty"`
+	Clear          []string `protobuf:"bytes,9,rep,name=clear,proto3" json:"clear,omitempty"` // limits to remove: per_transfer, daily, weekly, monthly or hourly_count
+}
+
+func (x *SetTransferLimitRequest) Reset() {
+	*x = SetTransferLimitRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_set_transfer_limit_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetTransferLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTransferLimitRequest) ProtoMessage() {}
+
+func (x *SetTransferLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_set_transfer_limit_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTransferLimitRequest.ProtoReflect.Descriptor instead.
+func (*SetTransferLimitRequest) Descriptor() ([]byte, []int) {
+	return file_set_transfer_limit_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SetTransferLimitRequest) GetTier() string {
+	if x != nil {
+		return x.Tier
+	}
+	return ""
+}
+
+func (x *SetTransferLimitRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *SetTransferLimitRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *SetTransferLimitRequest) GetMaxPerTransfer() string {
+	if x != nil && x.MaxPerTransfer != nil {
+		return *x.MaxPerTransfer
+	}
+	return ""
+}
+
+func (x *SetTransferLimitRequest) GetMaxDaily() string {
+	if x != nil && x.MaxDaily != nil {
+		return *x.MaxDaily
+	}
+	return ""
+}
+
+func (x *SetTransferLimitRequest) GetMaxWeekly() string {
+	if x != nil && x.MaxWeekly != nil {
+		return *x.MaxWeekly
+	}
+	return ""
+}
+
+func (x *SetTransferLimitRequest) GetMaxMonthly() string {
+	if x != nil && x.MaxMonthly != nil {
+		return *x.MaxMonthly
+	}
+	return ""
+}
+
+func (x *SetTransferLimitRequest) GetMaxHourlyCount() int64 {
+	if x != nil && x.MaxHourlyCount != nil {
+		return *x.MaxHourlyCount
+	}
+	return 0
+}
+
+func (x *SetTransferLimitRequest) GetClear() []string {
+	if x != nil {
+		return x.Clear
+	}
+	return nil
+}
+
+type SetTransferLimitResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Limit *TransferLimit `protobuf:"bytes,1,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *SetTransferLimitResponse) Reset() {
+	*x = SetTransferLimitResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_set_transfer_limit_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetTransferLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTransferLimitResponse) ProtoMessage() {}
+
+func (x *SetTransferLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_set_transfer_limit_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTransferLimitResponse.ProtoReflect.Descriptor instead.
+func (*SetTransferLimitResponse) Descriptor() ([]byte, []int) {
+	return file_set_transfer_limit_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SetTransferLimitResponse) GetLimit() *TransferLimit {
+	if x != nil {
+		return x.Limit
+	}
+	return nil
+}
+
+var File_set_transfer_limit_proto protoreflect.FileDescriptor
+
+var file_set_transfer_limit_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x73, 0x65, 0x74, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x70, 0x62, 0x1a, 0x14,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9f, 0x03, 0x0a, 0x17, 0x53, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x74, 0x69, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x5f,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12,
+	0x2d, 0x0a, 0x10, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73,
+	0x66, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0e, 0x6d, 0x61, 0x78,
+	0x50, 0x65, 0x72, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x20,
+	0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x01, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x44, 0x61, 0x69, 0x6c, 0x79, 0x88, 0x01, 0x01,
+	0x12, 0x22, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x77, 0x65, 0x65, 0x6b, 0x6c, 0x79, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x57, 0x65, 0x65, 0x6b, 0x6c,
+	0x79, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x6d, 0x6f, 0x6e, 0x74,
+	0x68, 0x6c, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0a, 0x6d, 0x61, 0x78,
+	0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x6c, 0x79, 0x88, 0x01, 0x01, 0x12, 0x2d, 0x0a, 0x10, 0x6d, 0x61,
+	0x78, 0x5f, 0x68, 0x6f, 0x75, 0x72, 0x6c, 0x79, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x03, 0x48, 0x04, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x48, 0x6f, 0x75, 0x72, 0x6c,
+	0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6c, 0x65,
+	0x61, 0x72, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x63, 0x6c, 0x65, 0x61, 0x72, 0x42,
+	0x13, 0x0a, 0x11, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x74, 0x72, 0x61, 0x6e,
+	0x73, 0x66, 0x65, 0x72, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x61, 0x69,
+	0x6c, 0x79, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x77, 0x65, 0x65, 0x6b, 0x6c,
+	0x79, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x6c,
+	0x79, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x68, 0x6f, 0x75, 0x72, 0x6c, 0x79,
+	0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x43, 0x0a, 0x18, 0x53, 0x65, 0x74, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x66, 0x65, 0x72, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x27, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x70, 0x62, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x42, 0x1e, 0x5a, 0x1c, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x78, 0x74, 0x79, 0x6d, 0x2f,
+	0x62, 0x61, 0x6e, 0x6b, 0x72, 0x75, 0x70, 0x74, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_set_transfer_limit_proto_rawDescOnce sync.Once
+	file_set_transfer_limit_proto_rawDescData = file_set_transfer_limit_proto_rawDesc
+)
+
+func file_set_transfer_limit_proto_rawDescGZIP() []byte {
+	file_set_transfer_limit_proto_rawDescOnce.Do(func() {
+		file_set_transfer_limit_proto_rawDescData = protoimpl.X.CompressGZIP(file_set_transfer_limit_proto_rawDescData)
+	})
+	return file_set_transfer_limit_proto_rawDescData
+}
+
+var file_set_transfer_limit_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_set_transfer_limit_proto_goTypes = []any{
+	(*SetTransferLimitRequest)(nil),  // 0: pb.SetTransferLimitRequest
+	(*SetTransferLimitResponse)(nil), // 1: pb.SetTransferLimitResponse
+	(*TransferLimit)(nil),            // 2: pb.TransferLimit
+}
+var file_set_transfer_limit_proto_depIdxs = []int32{
+	2, // 0: pb.SetTransferLimitResponse.limit:type_name -> pb.TransferLimit
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_set_transfer_limit_proto_init() }
+func file_set_transfer_limit_proto_init() {
+	if File_set_transfer_limit_proto != nil {
+		return
+	}
+	file_transfer_limit_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_set_transfer_limit_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*SetTransferLimitRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_set_transfer_limit_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*SetTransferLimitResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_set_transfer_limit_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_set_transfer_limit_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_set_transfer_limit_proto_goTypes,
+		DependencyIndexes: file_set_transfer_limit_proto_depIdxs,
+		MessageInfos:      file_set_transfer_limit_proto_msgTypes,
+	}.Build()
+	File_set_transfer_limit_proto = out.File
+	file_set_transfer_limit_proto_rawDesc = nil
+	file_set_transfer_limit_proto_goTypes = nil
+	file_set_transfer_limit_proto_depIdxs = nil
+}
@@ -0,0 +1,277 @@
+package reconcile
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	SourceTask = "task"
+	SourceCLI  = "cli"
+)
+
+const (
+	StatusClean    = "clean"    // nothing found
+	StatusWarning  = "warning"  // only warnings found
+	StatusCritical = "critical" // money doesn't add up
+	StatusErrored  = "errored"  // scan didn't finish
+)
+
+const (
+	KindBalanceDrift       = "balance_drift"       // balance differs from sum of entries
+	KindUnbalancedTransfer = "unbalanced_transfer" // entries of transfer don't net to zero
+	KindOrphanEntry        = "orphan_entry"        // entry not linked to any transfer
+)
+
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+)
+
+// rows read per query
+const DefaultBatchSize = 500
+
+// findings kept per run, the rest are only counted
+const maxFindings = 1000
+
+type Finding struct {
+	Kind       string `json:"kind"`
+	Severity   string `json:"severity"`
+	AccountID  int64  `json:"account_id,omitempty"`
+	TransferID int64  `json:"transfer_id,omitempty"`
+	EntryID    int64  `json:"entry_id,omitempty"`
+	Expected   int64  `json:"expected"`
+	Actual     int64  `json:"actual"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+type Report struct {
+	AccountsChecked  int64     `json:"accounts_checked"`
+	TransfersChecked int64     `json:"transfers_checked"`
+	Findings         []Finding `json:"findings"`
+	Critical         int       `json:"critical"`
+	Warnings         int       `json:"warnings"`
+}
+
+func (report *Report) add(finding Finding) {
+	if finding.Severity == SeverityCritical {
+		report.Critical++
+	} else {
+		report.Warnings++
+	}
+	if len(report.Findings) < maxFindings {
+		report.Findings = append(report.Findings, finding)
+	}
+}
+
+func (report Report) Status() string {
+	switch {
+	case report.Critical > 0:
+		return StatusCritical
+	case report.Warnings > 0:
+		return StatusWarning
+	}
+	return StatusClean
+}
+
+// queries the reconciler needs, satisfied by db.Store
+type Ledger interface {
+	ReconcileAccounts(ctx context.Context, arg db.ReconcileAccountsParams) ([]db.ReconcileAccountsRow, error)
+	ReconcileTransfers(ctx context.Context, arg db.ReconcileTransfersParams) ([]db.ReconcileTransfersRow, error)
+	ListOrphanEntries(ctx context.Context, arg db.ListOrphanEntriesParams) ([]db.Entry, error)
+	CreateReconciliationRun(ctx context.Context, source string) (db.ReconciliationRun, error)
+	FinishReconciliationRun(ctx context.Context, arg db.FinishReconciliationRunParams) (db.ReconciliationRun, error)
+}
+
+type Reconciler struct {
+	store     Ledger
+	batchSize int32
+}
+
+func NewReconciler(store Ledger, batchSize int32) *Reconciler {
+	return &Reconciler{
+		store:     store,
+		batchSize: batchSize,
+	}
+}
+
+// scan the whole ledger and record what was found
+func (r *Reconciler) Run(ctx context.Context, source string) (db.ReconciliationRun, Report, error) {
+	run, err := r.store.CreateReconciliationRun(ctx, source)
+	if err != nil {
+		return run, Report{}, fmt.Errorf("cannot create reconciliation run: %w", err)
+	}
+
+	report, scanErr := r.Scan(ctx)
+	if report.Findings == nil {
+		report.Findings = []Finding{}
+	}
+
+	findings, err := json.Marshal(report.Findings)
+	if err != nil {
+		return run, report, fmt.Errorf("cannot marshal findings: %w", err)
+	}
+
+	arg := db.FinishReconciliationRunParams{
+		ID:               run.ID,
+		Status:           report.Status(),
+		AccountsChecked:  report.AccountsChecked,
+		TransfersChecked: report.TransfersChecked,
+		CriticalFindings: int32(report.Critical),
+		Findings:         findings,
+	}
+	if scanErr != nil {
+		arg.Status = StatusErrored
+		arg.Error = sql.NullString{String: scanErr.Error(), Valid: true}
+	}
+
+	run, err = r.store.FinishReconciliationRun(ctx, arg)
+	if err != nil {
+		return run, report, fmt.Errorf("cannot finish reconciliation run: %w", err)
+	}
+
+	log.Info().Int64("run_id", run.ID).Str("source", source).Str("status", run.Status).
+		Int64("accounts", report.AccountsChecked).Int64("transfers", report.TransfersChecked).
+		Int("critical", report.Critical).Int("warnings", report.Warnings).Msg("reconciliation finished")
+
+	if scanErr != nil {
+		return run, report, fmt.Errorf("cannot scan ledger: %w", scanErr)
+	}
+	return run, report, nil
+}
+
+// check accounts, transfers and entries in batches
+func (r *Reconciler) Scan(ctx context.Context) (report Report, err error) {
+	if err = r.scanAccounts(ctx, &report); err != nil {
+		return
+	}
+	if err = r.scanTransfers(ctx, &report); err != nil {
+		return
+	}
+	err = r.scanEntries(ctx, &report)
+	return
+}
+
+func (r *Reconciler) scanAccounts(ctx context.Context, report *Report) error {
+	var afterID int64
+	for {
+		accounts, err := r.store.ReconcileAccounts(ctx, db.ReconcileAccountsParams{
+			AfterID:    afterID,
+			LimitCount: r.batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, account := range accounts {
+			if account.Balance != account.EntriesSum {
+				report.add(Finding{
+					Kind:      KindBalanceDrift,
+					Severity:  SeverityCritical,
+					AccountID: account.ID,
+					Expected:  account.EntriesSum,
+					Actual:    account.Balance,
+				})
+			}
+		}
+
+		report.AccountsChecked += int64(len(accounts))
+		if len(accounts) < int(r.batchSize) {
+			return nil
+		}
+		afterID = accounts[len(accounts)-1].ID
+	}
+}
+
+func (r *Reconciler) scanTransfers(ctx context.Context, report *Report) error {
+	var afterID int64
+	for {
+		transfers, err := r.store.ReconcileTransfers(ctx, db.ReconcileTransfersParams{
+			AfterID:    afterID,
+			LimitCount: r.batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, transfer := range transfers {
+			if finding, ok := checkTransfer(transfer); !ok {
+				report.add(finding)
+			}
+		}
+
+		report.TransfersChecked += int64(len(transfers))
+		if len(transfers) < int(r.batchSize) {
+			return nil
+		}
+		afterID = transfers[len(transfers)-1].ID
+	}
+}
+
+func (r *Reconciler) scanEntries(ctx context.Context, report *Report) error {
+	var afterID int64
+	for {
+		entries, err := r.store.ListOrphanEntries(ctx, db.ListOrphanEntriesParams{
+			AfterID:    afterID,
+			LimitCount: r.batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		// balance drift already catches orphans that moved money
+		for _, entry := range entries {
+			report.add(Finding{
+				Kind:      KindOrphanEntry,
+				Severity:  SeverityWarning,
+				AccountID: entry.AccountID,
+				EntryID:   entry.ID,
+				Actual:    entry.Amount,
+			})
+		}
+
+		if len(entries) < int(r.batchSize) {
+			return nil
+		}
+		afterID = entries[len(entries)-1].ID
+	}
+}
+
+// posted transfers have two entries per currency netting to zero, others have none
+func checkTransfer(transfer db.ReconcileTransfersRow) (Finding, bool) {
+	var expected int64
+	switch transfer.Status {
+	case db.TransferPosted, db.TransferReversed:
+		expected = 2
+		if transfer.Converted {
+			expected = 4
+		}
+	}
+
+	finding := Finding{
+		Kind:       KindUnbalancedTransfer,
+		Severity:   SeverityCritical,
+		TransferID: transfer.ID,
+	}
+	switch {
+	case transfer.EntryCount != expected:
+		finding.Expected = expected
+		finding.Actual = transfer.EntryCount
+		finding.Detail = fmt.Sprintf("%s transfer has %d entries", transfer.Status, transfer.EntryCount)
+		return finding, false
+	case transfer.CurrencySum != 0:
+		finding.Actual = transfer.CurrencySum
+		finding.Detail = "entries in transfer currency don't net to zero"
+		return finding, false
+	case transfer.ToCurrencySum != 0:
+		finding.Actual = transfer.ToCurrencySum
+		finding.Detail = "entries in converted currency don't net to zero"
+		return finding, false
+	}
+	return finding, true
+}
@@ -0,0 +1,157 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLedger struct {
+	accounts  []db.ReconcileAccountsRow
+	transfers []db.ReconcileTransfersRow
+	entries   []db.Entry
+	err       error
+	finished  db.FinishReconciliationRunParams
+	calls     int
+}
+
+func (f *fakeLedger) ReconcileAccounts(ctx context.Context, arg db.ReconcileAccountsParams) ([]db.ReconcileAccountsRow, error) {
+	f.calls++
+	var rows []db.ReconcileAccountsRow
+	for _, row := range f.accounts {
+		if row.ID > arg.AfterID && len(rows) < int(arg.LimitCount) {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (f *fakeLedger) ReconcileTransfers(ctx context.Context, arg db.ReconcileTransfersParams) ([]db.ReconcileTransfersRow, error) {
+	var rows []db.ReconcileTransfersRow
+	for _, row := range f.transfers {
+		if row.ID > arg.AfterID && len(rows) < int(arg.LimitCount) {
+			rows = append(rows, row)
+		}
+	}
+	return rows, f.err
+}
+
+func (f *fakeLedger) ListOrphanEntries(ctx context.Context, arg db.ListOrphanEntriesParams) ([]db.Entry, error) {
+	var rows []db.Entry
+	for _, row := range f.entries {
+		if row.ID > arg.AfterID && len(rows) < int(arg.LimitCount) {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (f *fakeLedger) CreateReconciliationRun(ctx context.Context, source string) (db.ReconciliationRun, error) {
+	return db.ReconciliationRun{ID: 1, Source: source, Status: "running"}, nil
+}
+
+func (f *fakeLedger) FinishReconciliationRun(ctx context.Context, arg db.FinishReconciliationRunParams) (db.ReconciliationRun, error) {
+	f.finished = arg
+	return db.ReconciliationRun{
+		ID:               arg.ID,
+		Status:           arg.Status,
+		CriticalFindings: arg.CriticalFindings,
+		Findings:         arg.Findings,
+	}, nil
+}
+
+func TestReconcilerClean(t *testing.T) {
+	ledger := &fakeLedger{
+		accounts: []db.ReconcileAccountsRow{
+			{ID: 1, Balance: 100, EntriesSum: 100},
+			{ID: 2, Balance: -100, EntriesSum: -100},
+			{ID: 3},
+		},
+		transfers: []db.ReconcileTransfersRow{
+			{ID: 1, Status: db.TransferPosted, EntryCount: 2},
+			{ID: 2, Status: db.TransferReversed, EntryCount: 2},
+			{ID: 3, Status: db.TransferPosted, Converted: true, EntryCount: 4},
+			{ID: 4, Status: db.TransferPending},
+			{ID: 5, Status: db.TransferCancelled},
+		},
+	}
+
+	// batches smaller than the data force paging
+	run, report, err := NewReconciler(ledger, 2).Run(context.Background(), SourceCLI)
+	require.NoError(t, err)
+	require.Equal(t, StatusClean, run.Status)
+	require.Equal(t, int64(3), report.AccountsChecked)
+	require.Equal(t, int64(5), report.TransfersChecked)
+	require.Empty(t, report.Findings)
+	require.Equal(t, 2, ledger.calls)
+	require.JSONEq(t, "[]", string(ledger.finished.Findings))
+}
+
+func TestReconcilerFindings(t *testing.T) {
+	ledger := &fakeLedger{
+		accounts: []db.ReconcileAccountsRow{
+			{ID: 1, Balance: 150, EntriesSum: 100},
+		},
+		transfers: []db.ReconcileTransfersRow{
+			{ID: 1, Status: db.TransferPosted, EntryCount: 1, CurrencySum: -100},
+			{ID: 2, Status: db.TransferPosted, EntryCount: 2, CurrencySum: 10},
+			{ID: 3, Status: db.TransferPosted, Converted: true, EntryCount: 4, ToCurrencySum: -1},
+			{ID: 4, Status: db.TransferFailed, EntryCount: 2},
+		},
+		entries: []db.Entry{
+			{ID: 7, AccountID: 1, Amount: 50},
+		},
+	}
+
+	run, report, err := NewReconciler(ledger, DefaultBatchSize).Run(context.Background(), SourceTask)
+	require.NoError(t, err)
+	require.Equal(t, StatusCritical, run.Status)
+	require.Equal(t, int32(5), run.CriticalFindings)
+	require.Equal(t, 5, report.Critical)
+	require.Equal(t, 1, report.Warnings)
+
+	var findings []Finding
+	require.NoError(t, json.Unmarshal(ledger.finished.Findings, &findings))
+	require.Len(t, findings, 6)
+
+	require.Equal(t, KindBalanceDrift, findings[0].Kind)
+	require.Equal(t, int64(100), findings[0].Expected)
+	require.Equal(t, int64(150), findings[0].Actual)
+
+	for i, transferID := range []int64{1, 2, 3, 4} {
+		require.Equal(t, KindUnbalancedTransfer, findings[i+1].Kind)
+		require.Equal(t, transferID, findings[i+1].TransferID)
+	}
+
+	require.Equal(t, KindOrphanEntry, findings[5].Kind)
+	require.Equal(t, SeverityWarning, findings[5].Severity)
+	require.Equal(t, int64(7), findings[5].EntryID)
+}
+
+func TestReconcilerWarning(t *testing.T) {
+	ledger := &fakeLedger{
+		entries: []db.Entry{{ID: 1, AccountID: 1, Amount: 10}},
+	}
+
+	run, _, err := NewReconciler(ledger, DefaultBatchSize).Run(context.Background(), SourceTask)
+	require.NoError(t, err)
+	require.Equal(t, StatusWarning, run.Status)
+	require.Zero(t, run.CriticalFindings)
+}
+
+func TestReconcilerErrored(t *testing.T) {
+	ledger := &fakeLedger{
+		accounts: []db.ReconcileAccountsRow{{ID: 1, Balance: 1}},
+		err:      errors.New("connection reset"),
+	}
+
+	run, report, err := NewReconciler(ledger, DefaultBatchSize).Run(context.Background(), SourceTask)
+	require.Error(t, err)
+	require.Equal(t, StatusErrored, run.Status)
+	require.True(t, ledger.finished.Error.Valid)
+	require.Equal(t, 1, report.Critical)
+}
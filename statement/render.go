@@ -0,0 +1,151 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	FormatCSV = "csv"
+	FormatPDF = "pdf"
+)
+
+var ErrUnknownFormat = errors.New("unknown statement format")
+
+const dateLayout = "2006-01-02"
+
+type Document struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+func IsFormat(format string) bool {
+	return format == FormatCSV || format == FormatPDF
+}
+
+func Render(st Statement, format string) (Document, error) {
+	doc := Document{
+		Filename: fmt.Sprintf("statement_%d_%s_%s.%s", st.Account.ID,
+			st.From.Format(dateLayout), st.To.Format(dateLayout), format),
+	}
+
+	var err error
+	switch format {
+	case FormatCSV:
+		doc.ContentType = "text/csv"
+		doc.Content, err = RenderCSV(st)
+	case FormatPDF:
+		doc.ContentType = "application/pdf"
+		doc.Content, err = RenderPDF(st)
+	default:
+		err = fmt.Errorf("%s: %w", format, ErrUnknownFormat)
+	}
+	return doc, err
+}
+
+func RenderCSV(st Statement) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	records := [][]string{
+		{"date", "description", "entry_id", "transfer_id", "counterparty_account_id", "amount", "balance", "currency"},
+		{st.From.Format(time.RFC3339), "opening balance", "", "", "", "", st.money(st.OpeningBalance), st.Account.Currency},
+	}
+	for _, entry := range st.Entries {
+		records = append(records, []string{
+			entry.CreatedAt.Format(time.RFC3339),
+			describe(entry),
+			strconv.FormatInt(entry.ID, 10),
+			optionalID(entry.TransferID.Int64),
+			optionalID(entry.CounterpartyAccountID),
+			st.money(entry.Amount),
+			st.money(entry.BalanceAfter),
+			st.Account.Currency,
+		})
+	}
+	records = append(records, []string{
+		st.To.Format(time.RFC3339), "closing balance", "", "", "", "", st.money(st.ClosingBalance), st.Account.Currency,
+	})
+
+	if err := w.WriteAll(records); err != nil {
+		return nil, fmt.Errorf("cannot write csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func RenderPDF(st Statement) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("Statement for account %d", st.Account.ID), false)
+	pdf.SetCreationDate(st.To)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	widths := []float64{38, 72, 40, 40}
+	header := func() {
+		pdf.SetFont("Helvetica", "B", 9)
+		for i, title := range []string{"Date", "Description", "Amount", "Balance"} {
+			align := "L"
+			if i > 1 {
+				align = "R"
+			}
+			pdf.CellFormat(widths[i], 7, title, "B", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 9)
+	}
+	row := func(date, description, amount, balance string) {
+		// repeat the header on every new page
+		if pdf.GetY() > 270 {
+			pdf.AddPage()
+			header()
+		}
+		pdf.CellFormat(widths[0], 6, date, "", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 6, description, "", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 6, amount, "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 6, balance, "", 1, "R", false, 0, "")
+	}
+
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(0, 10, "Account statement", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, fmt.Sprintf("Account %d (%s), owner %s", st.Account.ID, st.Account.Currency, st.Account.Owner), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, fmt.Sprintf("Period from %s until %s", st.From.Format(dateLayout), st.To.Format(dateLayout)), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	header()
+	row(st.From.Format(dateLayout), "Opening balance", "", st.money(st.OpeningBalance))
+	for _, entry := range st.Entries {
+		row(entry.CreatedAt.Format(dateLayout), describe(entry), st.money(entry.Amount), st.money(entry.BalanceAfter))
+	}
+	row(st.To.Format(dateLayout), "Closing balance", "", st.money(st.ClosingBalance))
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("cannot write pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (st Statement) money(amount int64) string {
+	return utils.NewMoney(amount, st.Account.Currency).Decimal()
+}
+
+func optionalID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
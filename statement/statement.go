@@ -0,0 +1,88 @@
+package statement
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+)
+
+// entries read per query while building
+const pageSize = 500
+
+// queries needed to build a statement, satisfied by db.Store
+type Source interface {
+	GetBalanceBefore(ctx context.Context, arg db.GetBalanceBeforeParams) (int64, error)
+	ListStatementEntries(ctx context.Context, arg db.ListStatementEntriesParams) ([]db.ListStatementEntriesRow, error)
+}
+
+type Statement struct {
+	Account        db.Account
+	From           time.Time // inclusive
+	To             time.Time // exclusive
+	OpeningBalance int64
+	ClosingBalance int64
+	Entries        []db.ListStatementEntriesRow
+}
+
+// load all entries of account in the period with opening and closing balances
+func Build(ctx context.Context, source Source, account db.Account, from, to time.Time) (Statement, error) {
+	st := Statement{
+		Account: account,
+		From:    from,
+		To:      to,
+	}
+
+	var err error
+	st.OpeningBalance, err = source.GetBalanceBefore(ctx, db.GetBalanceBeforeParams{
+		AccountID: account.ID,
+		Before:    from,
+	})
+	if err != nil {
+		return st, fmt.Errorf("cannot get opening balance: %w", err)
+	}
+
+	var after db.ListStatementEntriesRow
+	for {
+		entries, err := source.ListStatementEntries(ctx, db.ListStatementEntriesParams{
+			AccountID:  account.ID,
+			FromTime:   from,
+			ToTime:     to,
+			AfterID:    after.ID,
+			LimitCount: pageSize,
+		})
+		if err != nil {
+			return st, fmt.Errorf("cannot list statement entries: %w", err)
+		}
+
+		st.Entries = append(st.Entries, entries...)
+		if len(entries) < pageSize {
+			break
+		}
+		after = entries[len(entries)-1]
+	}
+
+	st.ClosingBalance = st.OpeningBalance
+	if len(st.Entries) > 0 {
+		st.ClosingBalance = st.Entries[len(st.Entries)-1].BalanceAfter
+	}
+	return st, nil
+}
+
+// calendar month before t, in t's location
+func PreviousMonth(t time.Time) (from, to time.Time) {
+	to = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	from = to.AddDate(0, -1, 0)
+	return
+}
+
+func describe(entry db.ListStatementEntriesRow) string {
+	switch {
+	case entry.CounterpartyAccountID == 0:
+		return "adjustment"
+	case entry.Amount < 0:
+		return fmt.Sprintf("transfer to account %d", entry.CounterpartyAccountID)
+	}
+	return fmt.Sprintf("transfer from account %d", entry.CounterpartyAccountID)
+}
@@ -0,0 +1,115 @@
+package statement
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSource struct {
+	opening int64
+	entries []db.ListStatementEntriesRow
+	calls   int
+}
+
+func (f *fakeSource) GetBalanceBefore(ctx context.Context, arg db.GetBalanceBeforeParams) (int64, error) {
+	return f.opening, nil
+}
+
+func (f *fakeSource) ListStatementEntries(ctx context.Context, arg db.ListStatementEntriesParams) ([]db.ListStatementEntriesRow, error) {
+	f.calls++
+	var rows []db.ListStatementEntriesRow
+	for _, entry := range f.entries {
+		if entry.ID > arg.AfterID && len(rows) < int(arg.LimitCount) {
+			rows = append(rows, entry)
+		}
+	}
+	return rows, nil
+}
+
+func randomStatement(t *testing.T, n int) (Statement, *fakeSource) {
+	account := db.Account{ID: 7, Owner: "alice", Currency: utils.USD}
+	from, to := PreviousMonth(time.Date(2026, time.March, 15, 10, 0, 0, 0, time.UTC))
+
+	source := &fakeSource{opening: 1000}
+	balance := source.opening
+	for i := 0; i < n; i++ {
+		amount := int64(-10)
+		if i%2 == 0 {
+			amount = 25
+		}
+		balance += amount
+		source.entries = append(source.entries, db.ListStatementEntriesRow{
+			ID:                    int64(i + 1),
+			AccountID:             account.ID,
+			Amount:                amount,
+			BalanceAfter:          balance,
+			TransferID:            sql.NullInt64{Int64: int64(100 + i), Valid: true},
+			CounterpartyAccountID: 8,
+			CreatedAt:             from.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	st, err := Build(context.Background(), source, account, from, to)
+	require.NoError(t, err)
+	return st, source
+}
+
+func TestPreviousMonth(t *testing.T) {
+	from, to := PreviousMonth(time.Date(2026, time.January, 31, 23, 0, 0, 0, time.UTC))
+	require.Equal(t, time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC), from)
+	require.Equal(t, time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), to)
+}
+
+func TestBuild(t *testing.T) {
+	st, source := randomStatement(t, pageSize+3)
+	require.Equal(t, 2, source.calls)
+	require.Len(t, st.Entries, pageSize+3)
+	require.Equal(t, int64(1000), st.OpeningBalance)
+	require.Equal(t, st.Entries[len(st.Entries)-1].BalanceAfter, st.ClosingBalance)
+
+	// no entries keeps the opening balance
+	empty, _ := randomStatement(t, 0)
+	require.Empty(t, empty.Entries)
+	require.Equal(t, empty.OpeningBalance, empty.ClosingBalance)
+}
+
+func TestRenderCSV(t *testing.T) {
+	st, _ := randomStatement(t, 2)
+
+	doc, err := Render(st, FormatCSV)
+	require.NoError(t, err)
+	require.Equal(t, "text/csv", doc.ContentType)
+	require.Equal(t, "statement_7_2026-02-01_2026-03-01.csv", doc.Filename)
+
+	records, err := csv.NewReader(bytes.NewReader(doc.Content)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+	require.Equal(t, "opening balance", records[1][1])
+	require.Equal(t, "10.00", records[1][6])
+	require.Equal(t, []string{"2026-02-01T00:00:00Z", "transfer from account 8", "1", "100", "8", "0.25", "10.25", "USD"}, records[2])
+	require.Equal(t, "transfer to account 8", records[3][1])
+	require.Equal(t, "-0.10", records[3][5])
+	require.Equal(t, "closing balance", records[4][1])
+	require.Equal(t, "10.15", records[4][6])
+}
+
+func TestRenderPDF(t *testing.T) {
+	// enough entries to span pages
+	st, _ := randomStatement(t, 100)
+
+	doc, err := Render(st, FormatPDF)
+	require.NoError(t, err)
+	require.Equal(t, "application/pdf", doc.ContentType)
+	require.True(t, bytes.HasPrefix(doc.Content, []byte("%PDF-")))
+
+	_, err = Render(st, "xls")
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
@@ -22,9 +22,9 @@ func NewJWTMaker(secretKey string) (Maker, error) {
 	return &JWTMaker{secretKey}, nil
 }
 
-func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
+func (maker *JWTMaker) CreateToken(username string, role string, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
 	// create a new payload
-	payload, err := NewPayload(username, duration)
+	payload, err := NewPayload(username, role, tokenType, duration)
 	if err != nil {
 		return "", payload, err
 	}
@@ -35,7 +35,7 @@ func (maker *JWTMaker) CreateToken(username string, duration time.Duration) (str
 	return token, payload, err
 }
 
-func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
+func (maker *JWTMaker) VerifyToken(token string, tokenType TokenType) (*Payload, error) {
 	// parse the token
 	keyFunc := func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -54,7 +54,7 @@ func (maker *JWTMaker) VerifyToken(token string) (*Payload, error) {
 
 	// validate the token
 	payload, ok := jwtToken.Claims.(*Payload)
-	if !ok {
+	if !ok || payload.Type != tokenType {
 		return nil, ErrInvalidToken
 	}
 
@@ -15,21 +15,24 @@ func TestJWTMaker(t *testing.T) {
 	require.NotEmpty(t, jwtMaker)
 
 	username := utils.RandomOwner()
+	role := utils.DepositorRole
 	duration := time.Minute
 	createdAt := time.Now()
 	expiredAt := createdAt.Add(duration)
 
-	jwtToken, payload, err := jwtMaker.CreateToken(username, duration)
+	jwtToken, payload, err := jwtMaker.CreateToken(username, role, AccessToken, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, jwtToken)
 	require.NotEmpty(t, payload)
 
-	payload, err = jwtMaker.VerifyToken(jwtToken)
+	payload, err = jwtMaker.VerifyToken(jwtToken, AccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
 	require.NotZero(t, payload.Id)
 	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
+	require.Equal(t, AccessToken, payload.Type)
 	require.WithinDuration(t, createdAt, payload.CreatedAt, time.Second)
 	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
 }
@@ -39,19 +42,19 @@ func TestExpiredJWTToken(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, jwtMaker)
 
-	jwtToken, payload, err := jwtMaker.CreateToken(utils.RandomOwner(), -time.Minute)
+	jwtToken, payload, err := jwtMaker.CreateToken(utils.RandomOwner(), utils.DepositorRole, AccessToken, -time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, jwtToken)
 	require.NotEmpty(t, payload)
 
-	payload, err = jwtMaker.VerifyToken(jwtToken)
+	payload, err = jwtMaker.VerifyToken(jwtToken, AccessToken)
 	require.Error(t, err)
 	require.EqualError(t, err, ErrExpiredToken.Error())
 	require.Nil(t, payload)
 }
 
 func TestInvalidJWTToken(t *testing.T) {
-	payload, err := NewPayload(utils.RandomOwner(), time.Minute)
+	payload, err := NewPayload(utils.RandomOwner(), utils.DepositorRole, AccessToken, time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -64,8 +67,24 @@ func TestInvalidJWTToken(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, jwtMaker)
 
-	payload, err = jwtMaker.VerifyToken(token)
+	payload, err = jwtMaker.VerifyToken(token, AccessToken)
 	require.Error(t, err)
 	require.EqualError(t, err, ErrInvalidToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestWrongTypeJWTToken(t *testing.T) {
+	jwtMaker, err := NewJWTMaker(utils.RandomString(32))
+	require.NoError(t, err)
+
+	refreshToken, _, err := jwtMaker.CreateToken(utils.RandomOwner(), utils.DepositorRole, RefreshToken, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := jwtMaker.VerifyToken(refreshToken, AccessToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	payload, err = jwtMaker.VerifyToken(refreshToken, RefreshToken)
+	require.NoError(t, err)
+	require.Equal(t, RefreshToken, payload.Type)
+}
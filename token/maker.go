@@ -4,8 +4,8 @@ import "time"
 
 // for making tokens using diff algorithms
 type Maker interface {
-	// creates a new token
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
-	// validates a token
-	VerifyToken(token string) (*Payload, error)
+	// creates a new token of the given type
+	CreateToken(username string, role string, tokenType TokenType, duration time.Duration) (string, *Payload, error)
+	// validates a token and checks it is of the given type
+	VerifyToken(token string, tokenType TokenType) (*Payload, error)
 }
@@ -23,8 +23,8 @@ func NewPasetoMaker(symmetricKey []byte) (Maker, error) {
 	}, nil
 }
 
-func (pasetoMaker *PasetoMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, duration)
+func (pasetoMaker *PasetoMaker) CreateToken(username string, role string, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, role, tokenType, duration)
 	if err != nil {
 		return "", payload, err
 	}
@@ -33,7 +33,7 @@ func (pasetoMaker *PasetoMaker) CreateToken(username string, duration time.Durat
 	return token, payload, err
 }
 
-func (pasetoMaker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
+func (pasetoMaker *PasetoMaker) VerifyToken(token string, tokenType TokenType) (*Payload, error) {
 	payload := &Payload{}
 
 	err := pasetoMaker.paseto.Decrypt(token, pasetoMaker.symmetricKey, payload, nil)
@@ -45,5 +45,9 @@ func (pasetoMaker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
 		return nil, err
 	}
 
+	if payload.Type != tokenType {
+		return nil, ErrInvalidToken
+	}
+
 	return payload, nil
 }
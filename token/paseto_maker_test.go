@@ -14,21 +14,24 @@ func TestPasetoMaker(t *testing.T) {
 	require.NotEmpty(t, pasetoMaker)
 
 	username := utils.RandomOwner()
+	role := utils.DepositorRole
 	duration := time.Minute
 	createdAt := time.Now()
 	expiredAt := createdAt.Add(duration)
 
-	jwtToken, payload, err := pasetoMaker.CreateToken(username, duration)
+	jwtToken, payload, err := pasetoMaker.CreateToken(username, role, AccessToken, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, jwtToken)
 	require.NotEmpty(t, payload)
 
-	payload, err = pasetoMaker.VerifyToken(jwtToken)
+	payload, err = pasetoMaker.VerifyToken(jwtToken, AccessToken)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
 	require.NotZero(t, payload.Id)
 	require.Equal(t, username, payload.Username)
+	require.Equal(t, role, payload.Role)
+	require.Equal(t, AccessToken, payload.Type)
 	require.WithinDuration(t, createdAt, payload.CreatedAt, time.Second)
 	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
 }
@@ -38,13 +41,29 @@ func TestExpiredPasetoToken(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, pasetoMaker)
 
-	jwtToken, payload, err := pasetoMaker.CreateToken(utils.RandomOwner(), -time.Minute)
+	jwtToken, payload, err := pasetoMaker.CreateToken(utils.RandomOwner(), utils.DepositorRole, AccessToken, -time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, jwtToken)
 	require.NotEmpty(t, payload)
 
-	payload, err = pasetoMaker.VerifyToken(jwtToken)
+	payload, err = pasetoMaker.VerifyToken(jwtToken, AccessToken)
 	require.Error(t, err)
 	require.EqualError(t, err, ErrExpiredToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestWrongTypePasetoToken(t *testing.T) {
+	pasetoMaker, err := NewPasetoMaker([]byte(utils.RandomString(32)))
+	require.NoError(t, err)
+
+	accessToken, _, err := pasetoMaker.CreateToken(utils.RandomOwner(), utils.DepositorRole, AccessToken, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := pasetoMaker.VerifyToken(accessToken, RefreshToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+
+	payload, err = pasetoMaker.VerifyToken(accessToken, AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, AccessToken, payload.Type)
+}
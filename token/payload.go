@@ -12,14 +12,24 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 )
 
+// kind of token, a refresh token must not pass as an access token
+type TokenType string
+
+const (
+	AccessToken  TokenType = "access"
+	RefreshToken TokenType = "refresh"
+)
+
 type Payload struct {
 	Id        uuid.UUID `json:"id"`
 	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	Type      TokenType `json:"token_type"`
 	CreatedAt time.Time `json:"created_at"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
 
-func NewPayload(username string, duration time.Duration) (*Payload, error) {
+func NewPayload(username string, role string, tokenType TokenType, duration time.Duration) (*Payload, error) {
 	tokenId, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
@@ -27,6 +37,8 @@ func NewPayload(username string, duration time.Duration) (*Payload, error) {
 	return &Payload{
 		Id:        tokenId,
 		Username:  username,
+		Role:      role,
+		Type:      tokenType,
 		CreatedAt: time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}, nil
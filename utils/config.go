@@ -7,16 +7,33 @@ import (
 )
 
 type Config struct {
-	Environment       string        `mapstructure:"ENVIRONMENT"`
-	Driver            string        `mapstructure:"DRIVER"`
-	Source            string        `mapstructure:"SOURCE"`
-	MigrateURL        string        `mapstructure:"MIGRATE_URL"`
-	HTTPAddress       string        `mapstructure:"HTTP_ADDRESS"`
-	GRPCAddress       string        `mapstructure:"GRPC_ADDRESS"`
-	TokenSymmetricKey string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	TokenDuration     time.Duration `mapstructure:"TOKEN_DURATION"`
-	RefreshDuration   time.Duration `mapstructure:"REFRESH_DURATION"`
-	RedisAddress      string        `mapstructure:"REDIS_ADDRESS"`
+	Environment                string        `mapstructure:"ENVIRONMENT"`
+	Driver                     string        `mapstructure:"DRIVER"`
+	Source                     string        `mapstructure:"SOURCE"`
+	MigrateURL                 string        `mapstructure:"MIGRATE_URL"`
+	HTTPAddress                string        `mapstructure:"HTTP_ADDRESS"`
+	GRPCAddress                string        `mapstructure:"GRPC_ADDRESS"`
+	TokenSymmetricKey          string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	TokenDuration              time.Duration `mapstructure:"TOKEN_DURATION"`
+	RefreshDuration            time.Duration `mapstructure:"REFRESH_DURATION"`
+	RedisAddress               string        `mapstructure:"REDIS_ADDRESS"`
+	IdempotencyKeyTTL          time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
+	CurrencyCacheTTL           time.Duration `mapstructure:"CURRENCY_CACHE_TTL"`
+	FXQuoteTTL                 time.Duration `mapstructure:"FX_QUOTE_TTL"`
+	FXRatesFile                string        `mapstructure:"FX_RATES_FILE"`
+	FXRatesCronspec            string        `mapstructure:"FX_RATES_CRONSPEC"`
+	HoldTTL                    time.Duration `mapstructure:"HOLD_TTL"`
+	HoldExpiryCronspec         string        `mapstructure:"HOLD_EXPIRY_CRONSPEC"`
+	ScheduledTransfersCronspec string        `mapstructure:"SCHEDULED_TRANSFERS_CRONSPEC"`
+	ReconciliationCronspec     string        `mapstructure:"RECONCILIATION_CRONSPEC"`
+	StatementsCronspec         string        `mapstructure:"STATEMENTS_CRONSPEC"`
+	SessionsPurgeCronspec      string        `mapstructure:"SESSIONS_PURGE_CRONSPEC"`
+	OutboxRelayInterval        time.Duration `mapstructure:"OUTBOX_RELAY_INTERVAL"`
+	EmailSenderName            string        `mapstructure:"EMAIL_SENDER_NAME"`
+	EmailSenderAddress         string        `mapstructure:"EMAIL_SENDER_ADDRESS"`
+	EmailSenderPassword        string        `mapstructure:"EMAIL_SENDER_PASSWORD"`
+	VerifyEmailURL             string        `mapstructure:"VERIFY_EMAIL_URL"`
+	RequireVerifiedEmail       bool          `mapstructure:"REQUIRE_VERIFIED_EMAIL"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
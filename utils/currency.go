@@ -1,16 +1,44 @@
 package utils
 
+import (
+	"errors"
+	"sync"
+)
+
+// currencies seeded by migration, the rest live in currencies table
 const (
 	USD = "USD"
 	EUR = "EUR"
 	CAD = "CAD"
 )
 
-func CurrencySupported(currency string) bool {
-	switch currency {
-	case USD, EUR, CAD:
-		return true
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// ISO 4217 minor unit exponent, e.g. 2 means 100 minor units (cents) per unit
+var (
+	exponentsMu       sync.RWMutex
+	currencyExponents = map[string]int{
+		USD: 2,
+		EUR: 2,
+		CAD: 2,
 	}
+)
+
+// make currency known to money parsing and formatting
+func RegisterCurrency(currency string, exponent int) {
+	exponentsMu.Lock()
+	defer exponentsMu.Unlock()
 
-	return false
+	currencyExponents[currency] = exponent
+}
+
+func CurrencyExponent(currency string) (int, error) {
+	exponentsMu.RLock()
+	defer exponentsMu.RUnlock()
+
+	exponent, ok := currencyExponents[currency]
+	if !ok {
+		return 0, ErrUnsupportedCurrency
+	}
+	return exponent, nil
 }
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+)
+
+// digits kept for exchange rates, matches numeric(24,12) columns
+const rateScale = 12
+
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+func parseRate(rate string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok || r.Sign() <= 0 {
+		return nil, ErrInvalidRate
+	}
+	return r, nil
+}
+
+// check rate is positive decimal string like "1.0850"
+func ValidateRate(rate string) error {
+	_, err := parseRate(rate)
+	return err
+}
+
+// take bank spread off the mid rate, e.g. 50 bps turns 1.1 into 1.0945
+func ApplySpread(rate string, spreadBps int32) (string, error) {
+	r, err := parseRate(rate)
+	if err != nil {
+		return "", err
+	}
+	if spreadBps < 0 || spreadBps >= 10000 {
+		return "", ErrInvalidRate
+	}
+
+	r.Mul(r, big.NewRat(int64(10000-spreadBps), 10000))
+	return r.FloatString(rateScale), nil
+}
+
+// convert money into currency at rate, rounding down in favour of the bank
+func ConvertMoney(amount Money, currency string, rate string) (Money, error) {
+	r, err := parseRate(rate)
+	if err != nil {
+		return Money{}, err
+	}
+
+	fromExponent, err := CurrencyExponent(amount.Currency)
+	if err != nil {
+		return Money{}, err
+	}
+	toExponent, err := CurrencyExponent(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	// minor units differ when exponents differ, e.g. USD cents to JPY yen
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(amount.Amount), r)
+	value.Mul(value, new(big.Rat).SetFrac(pow10(toExponent), pow10(fromExponent)))
+
+	converted := new(big.Int).Quo(value.Num(), value.Denom())
+	if !converted.IsInt64() {
+		return Money{}, ErrAmountOverflow
+	}
+
+	return NewMoney(converted.Int64(), currency), nil
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
+
+// format rate the way numeric(24,12) columns store it
+func NormalizeRate(rate string) (string, error) {
+	r, err := parseRate(rate)
+	if err != nil {
+		return "", err
+	}
+	return r.FloatString(rateScale), nil
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplySpread(t *testing.T) {
+	rate, err := ApplySpread("1.1", 50)
+	require.NoError(t, err)
+	require.Equal(t, "1.094500000000", rate)
+
+	rate, err = ApplySpread("1.1", 0)
+	require.NoError(t, err)
+	require.Equal(t, "1.100000000000", rate)
+
+	_, err = ApplySpread("0", 50)
+	require.ErrorIs(t, err, ErrInvalidRate)
+
+	_, err = ApplySpread("1.1", 10000)
+	require.ErrorIs(t, err, ErrInvalidRate)
+}
+
+func TestConvertMoney(t *testing.T) {
+	converted, err := ConvertMoney(NewMoney(10000, USD), EUR, "0.92")
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(9200, EUR), converted)
+
+	// fractions of minor unit are rounded down
+	converted, err = ConvertMoney(NewMoney(1, USD), EUR, "0.92")
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(0, EUR), converted)
+
+	// exponents differ between currencies
+	RegisterCurrency("KRW", 0)
+	converted, err = ConvertMoney(NewMoney(1050, USD), "KRW", "1380.5")
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(14495, "KRW"), converted)
+
+	_, err = ConvertMoney(NewMoney(100, USD), EUR, "-1")
+	require.ErrorIs(t, err, ErrInvalidRate)
+
+	_, err = ConvertMoney(NewMoney(100, USD), "XYZ", "1")
+	require.ErrorIs(t, err, ErrUnsupportedCurrency)
+
+	_, err = ConvertMoney(NewMoney(1<<62, USD), EUR, "4")
+	require.ErrorIs(t, err, ErrAmountOverflow)
+}
+
+func TestNormalizeRate(t *testing.T) {
+	rate, err := NormalizeRate("1.37")
+	require.NoError(t, err)
+	require.Equal(t, "1.370000000000", rate)
+
+	_, err = NormalizeRate("abc")
+	require.ErrorIs(t, err, ErrInvalidRate)
+}
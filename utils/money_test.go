@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	testCases := []struct {
+		value    string
+		currency string
+		amount   int64
+		err      error
+	}{
+		{"12.34", USD, 1234, nil},
+		{"12.3", USD, 1230, nil},
+		{"12", USD, 1200, nil},
+		{"0.01", USD, 1, nil},
+		{"-5.50", EUR, -550, nil},
+		{"12.345", USD, 0, ErrInvalidAmount},
+		{"12,34", USD, 0, ErrInvalidAmount},
+		{".5", USD, 0, ErrInvalidAmount},
+		{"", USD, 0, ErrInvalidAmount},
+		{"99999999999999999999", USD, 0, ErrAmountOverflow},
+		{"1.00", "XYZ", 0, ErrUnsupportedCurrency},
+	}
+
+	for _, tc := range testCases {
+		money, err := ParseMoney(tc.value, tc.currency)
+		if tc.err != nil {
+			require.ErrorIs(t, err, tc.err, tc.value)
+			continue
+		}
+		require.NoError(t, err, tc.value)
+		require.Equal(t, NewMoney(tc.amount, tc.currency), money)
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	require.Equal(t, "12.34", NewMoney(1234, USD).Decimal())
+	require.Equal(t, "0.05", NewMoney(5, USD).Decimal())
+	require.Equal(t, "-0.05", NewMoney(-5, USD).Decimal())
+	require.Equal(t, "0.00", NewMoney(0, CAD).Decimal())
+	require.Equal(t, "-92233720368547758.08", NewMoney(math.MinInt64, USD).Decimal())
+	require.Equal(t, "12.34 EUR", NewMoney(1234, EUR).String())
+
+	// formatting round trips through parsing
+	money := RandomMoney()
+	parsed, err := ParseMoney(money.Decimal(), money.Currency)
+	require.NoError(t, err)
+	require.Equal(t, money, parsed)
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	sum, err := NewMoney(150, USD).Add(NewMoney(250, USD))
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(400, USD), sum)
+
+	diff, err := NewMoney(150, USD).Sub(NewMoney(250, USD))
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(-100, USD), diff)
+
+	_, err = NewMoney(1, USD).Add(NewMoney(1, EUR))
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = NewMoney(math.MaxInt64, USD).Add(NewMoney(1, USD))
+	require.ErrorIs(t, err, ErrAmountOverflow)
+
+	_, err = NewMoney(math.MinInt64, USD).Sub(NewMoney(1, USD))
+	require.ErrorIs(t, err, ErrAmountOverflow)
+
+	_, err = NewMoney(math.MinInt64, USD).Neg()
+	require.ErrorIs(t, err, ErrAmountOverflow)
+}
+
+func TestMoneyJSON(t *testing.T) {
+	money := NewMoney(1234, USD)
+
+	data, err := json.Marshal(money)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount": "12.34", "currency": "USD"}`, string(data))
+
+	var decoded Money
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, money, decoded)
+
+	require.Error(t, json.Unmarshal([]byte(`{"amount": "1.234", "currency": "USD"}`), &decoded))
+}
+
+func TestRegisterCurrency(t *testing.T) {
+	_, err := ParseMoney("1200", "JPY")
+	require.ErrorIs(t, err, ErrUnsupportedCurrency)
+
+	RegisterCurrency("JPY", 0)
+
+	money, err := ParseMoney("1200", "JPY")
+	require.NoError(t, err)
+	require.Equal(t, NewMoney(1200, "JPY"), money)
+	require.Equal(t, "1200", money.Decimal())
+
+	_, err = ParseMoney("12.5", "JPY")
+	require.ErrorIs(t, err, ErrInvalidAmount)
+}
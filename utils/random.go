@@ -32,8 +32,8 @@ func RandomOwner() string {
 }
 
 // generate random money
-func RandomMoney() int64 {
-	return RandomInt(0, 1000)
+func RandomMoney() Money {
+	return NewMoney(RandomInt(0, 1000), RandomCurrency())
 }
 
 // generate random currency
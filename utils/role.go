@@ -0,0 +1,7 @@
+package utils
+
+const (
+	DepositorRole = "depositor"
+	BankerRole    = "banker"
+	SystemRole    = "system"
+)
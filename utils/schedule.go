@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// standard five field cron spec or descriptor, CRON_TZ= prefix picks the timezone
+func ParseSchedule(spec string) (cron.Schedule, error) {
+	return cron.ParseStandard(spec)
+}
+
+// first run strictly after the given time, zero when the schedule ends before it
+func NextRun(spec string, after time.Time, endAt time.Time) (time.Time, error) {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	next := schedule.Next(after)
+	if next.IsZero() || (!endAt.IsZero() && next.After(endAt)) {
+		return time.Time{}, nil
+	}
+	return next, nil
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextRun(t *testing.T) {
+	after := time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name  string
+		spec  string
+		endAt time.Time
+		next  time.Time
+	}{
+		{
+			name: "MonthlyOnFirst",
+			spec: "0 9 1 * *",
+			next: time.Date(2024, time.February, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Descriptor",
+			spec: "@daily",
+			next: time.Date(2024, time.January, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Timezone",
+			spec: "CRON_TZ=Asia/Tashkent 0 9 * * *",
+			next: time.Date(2024, time.January, 16, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "BeforeEnd",
+			spec:  "0 9 1 * *",
+			endAt: time.Date(2024, time.February, 1, 9, 0, 0, 0, time.UTC),
+			next:  time.Date(2024, time.February, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "AfterEnd",
+			spec:  "0 9 1 * *",
+			endAt: time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			next, err := NextRun(tc.spec, after, tc.endAt)
+			require.NoError(t, err)
+			require.True(t, tc.next.Equal(next), "expected %v, got %v", tc.next, next)
+		})
+	}
+}
+
+func TestNextRunInvalid(t *testing.T) {
+	for _, spec := range []string{"", "every day", "0 9 1 *", "61 * * * *"} {
+		_, err := NextRun(spec, time.Now(), time.Time{})
+		require.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// random bytes behind a secret code
+const secretCodeBytes = 32
+
+// generate url safe secret code, e.g. for email verification links
+func NewSecretCode() (string, error) {
+	b := make([]byte, secretCodeBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate secret code: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hash a high entropy token for storage, unlike passwords it needs no salt or stretching
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
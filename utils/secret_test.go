@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSecretCode(t *testing.T) {
+	code1, err := NewSecretCode()
+	require.NoError(t, err)
+	require.Len(t, code1, base64.RawURLEncoding.EncodedLen(secretCodeBytes))
+
+	decoded, err := base64.RawURLEncoding.DecodeString(code1)
+	require.NoError(t, err)
+	require.Len(t, decoded, secretCodeBytes)
+
+	code2, err := NewSecretCode()
+	require.NoError(t, err)
+	require.NotEqual(t, code1, code2)
+}
+
+func TestHashToken(t *testing.T) {
+	token := RandomString(32)
+
+	hash := HashToken(token)
+	require.Len(t, hash, 64)
+	require.NotContains(t, hash, token)
+	require.Equal(t, hash, HashToken(token))
+	require.NotEqual(t, hash, HashToken(token+"x"))
+}
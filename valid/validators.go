@@ -4,11 +4,15 @@ import (
 	"fmt"
 	"net/mail"
 	"regexp"
+
+	"github.com/dxtym/bankrupt/utils"
 )
 
 var (
 	validateUsername = regexp.MustCompile(`^[a-zA-Z0-9_]+$`).MatchString
 	validateFullname = regexp.MustCompile(`^[a-zA-Z\s]+$`).MatchString
+	validateCode     = regexp.MustCompile(`^[A-Z]{3}$`).MatchString
+	validateTier     = regexp.MustCompile(`^[a-z0-9_]+$`).MatchString
 )
 
 func ValidateString(value string, minLen, maxLen int) error {
@@ -52,3 +56,103 @@ func ValidateEmail(email string) error {
 	}
 	return nil
 }
+
+func ValidateCurrency(currency string, supported func(string) bool) error {
+	if !supported(currency) {
+		return fmt.Errorf("unsupported currency")
+	}
+	return nil
+}
+
+func ValidateTransferStatus(status string, known func(string) bool) error {
+	if !known(status) {
+		return fmt.Errorf("unknown transfer status")
+	}
+	return nil
+}
+
+func ValidateCurrencyCode(code string) error {
+	if !validateCode(code) {
+		return fmt.Errorf("must be 3 uppercase letters ISO 4217 code")
+	}
+	return nil
+}
+
+func ValidateCurrencyExponent(exponent int32) error {
+	if exponent < 0 || exponent > 4 {
+		return fmt.Errorf("must be between 0 and 4")
+	}
+	return nil
+}
+
+func ValidateID(id int64) error {
+	if id < 1 {
+		return fmt.Errorf("must be a positive integer")
+	}
+	return nil
+}
+
+func ValidatePageID(pageID int32) error {
+	if pageID < 1 {
+		return fmt.Errorf("must be a positive integer")
+	}
+	return nil
+}
+
+func ValidatePageSize(pageSize int32) error {
+	if pageSize < 5 || pageSize > 10 {
+		return fmt.Errorf("should be between 5 and 10")
+	}
+	return nil
+}
+
+// statements are read in bulk, so pages are larger than for lists
+func ValidateStatementPageSize(pageSize int32) error {
+	if pageSize < 10 || pageSize > 500 {
+		return fmt.Errorf("should be between 10 and 500")
+	}
+	return nil
+}
+
+func ValidateAmount(amount, currency string) error {
+	money, err := utils.ParseMoney(amount, currency)
+	if err != nil {
+		return fmt.Errorf("must be decimal amount in %s: %w", currency, err)
+	}
+	if !money.IsPositive() {
+		return fmt.Errorf("must be greater than zero")
+	}
+	return nil
+}
+
+func ValidateSchedule(spec string) error {
+	if err := ValidateString(spec, 1, 128); err != nil {
+		return err
+	}
+	if _, err := utils.ParseSchedule(spec); err != nil {
+		return fmt.Errorf("must be cron spec or descriptor: %w", err)
+	}
+	return nil
+}
+
+func ValidateTier(tier string) error {
+	if err := ValidateString(tier, 1, 32); err != nil {
+		return err
+	}
+	if !validateTier(tier) {
+		return fmt.Errorf("can only contain lowercase letters, numbers, and underscores")
+	}
+	return nil
+}
+
+func ValidateMemo(memo string) error {
+	return ValidateString(memo, 0, 140)
+}
+
+func ValidateIdempotencyKey(key string) error {
+	return ValidateString(key, 1, 255)
+}
+
+func ValidateSecretCode(code string) error {
+	return ValidateString(code, 32, 128)
+}
@@ -3,6 +3,7 @@ package worker
 import (
 	"context"
 
+	db "github.com/dxtym/bankrupt/db/sqlc"
 	"github.com/hibiken/asynq"
 )
 
@@ -12,6 +13,22 @@ type TaskDistributor interface {
 		payload PayloadSendEmail,
 		opts ...asynq.Option,
 	) error
+	DistributorTaskNotifyScheduledTransferFailed(
+		ctx context.Context,
+		payload PayloadNotifyScheduledTransferFailed,
+		opts ...asynq.Option,
+	) error
+	DistributorTaskSendReconciliationAlert(
+		ctx context.Context,
+		payload PayloadSendReconciliationAlert,
+		opts ...asynq.Option,
+	) error
+	DistributorTaskSendStatement(
+		ctx context.Context,
+		payload PayloadSendStatement,
+		opts ...asynq.Option,
+	) error
+	DistributorOutboxMessage(ctx context.Context, message db.OutboxMessage) error
 }
 
 type RedisTaskDistributor struct {
@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/outbox"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+// task distributor, the outbox id doubles as task id so a message published twice is enqueued once,
+// retention keeps the id taken after the task is done for as long as the relay may publish it again
+func (rtd RedisTaskDistributor) DistributorOutboxMessage(ctx context.Context, message db.OutboxMessage) error {
+	opts := []asynq.Option{
+		asynq.TaskID(fmt.Sprintf("outbox:%d", message.ID)),
+		asynq.Retention(outbox.TaskRetention),
+		asynq.Queue(message.Queue),
+		asynq.MaxRetry(int(message.MaxRetry)),
+	}
+	if message.TimeoutSeconds > 0 {
+		opts = append(opts, asynq.Timeout(time.Duration(message.TimeoutSeconds)*time.Second))
+	}
+
+	task := asynq.NewTask(message.TaskType, message.Payload, opts...)
+	info, err := rtd.client.EnqueueContext(ctx, task)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		log.Info().Str("type", task.Type()).Int64("outbox_id", message.ID).Msg("task already enqueued")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).Int64("outbox_id", message.ID).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("task enqueued")
+	return nil
+}
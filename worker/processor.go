@@ -4,6 +4,8 @@ import (
 	"context"
 
 	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/fx"
+	"github.com/dxtym/bankrupt/mail"
 	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog/log"
 )
@@ -17,15 +19,36 @@ const (
 type TaskProcessor interface {
 	Run() error
 	ProcessorTaskSendEmail(ctx context.Context, task *asynq.Task) error
+	ProcessorTaskCleanupIdempotencyKeys(ctx context.Context, task *asynq.Task) error
+	ProcessorTaskRefreshFXRates(ctx context.Context, task *asynq.Task) error
+	ProcessorTaskExpireHolds(ctx context.Context, task *asynq.Task) error
+	ProcessorTaskRunScheduledTransfers(ctx context.Context, task *asynq.Task) error
+	ProcessorTaskNotifyScheduledTransferFailed(ctx context.Context, task *asynq.Task) error
+	ProcessorTaskReconcileLedger(ctx context.Context, task *asynq.Task) error
+	ProcessorTaskSendReconciliationAlert(ctx context.Context, task *asynq.Task) error
+	ProcessorTaskSendMonthlyStatements(ctx context.Context, task *asynq.Task) error
+	ProcessorTaskSendStatement(ctx context.Context, task *asynq.Task) error
+	ProcessorTaskPurgeExpiredSessions(ctx context.Context, task *asynq.Task) error
 }
 
 // task processor
 type RedisTaskProcessor struct {
-	server *asynq.Server
-	store  db.Store
+	server       *asynq.Server
+	store        db.Store
+	distributor  TaskDistributor // for follow-up tasks
+	mailer       mail.EmailSender
+	rateProvider fx.RateProvider
+	verifyURL    string // link in verification emails
 }
 
-func NewRedisTaskProcessor(opts asynq.RedisClientOpt, store db.Store) TaskProcessor {
+func NewRedisTaskProcessor(
+	opts asynq.RedisClientOpt,
+	store db.Store,
+	distributor TaskDistributor,
+	mailer mail.EmailSender,
+	rateProvider fx.RateProvider,
+	verifyURL string,
+) TaskProcessor {
 	server := asynq.NewServer(
 		opts,
 		asynq.Config{
@@ -42,8 +65,12 @@ func NewRedisTaskProcessor(opts asynq.RedisClientOpt, store db.Store) TaskProces
 	)
 
 	return &RedisTaskProcessor{
-		server: server,
-		store:  store,
+		server:       server,
+		store:        store,
+		distributor:  distributor,
+		mailer:       mailer,
+		rateProvider: rateProvider,
+		verifyURL:    verifyURL,
 	}
 }
 
@@ -52,5 +79,15 @@ func (rtp RedisTaskProcessor) Run() error {
 
 	// register task handlers
 	mux.HandleFunc(TaskSendEmail, rtp.ProcessorTaskSendEmail)
+	mux.HandleFunc(TaskCleanupIdempotencyKeys, rtp.ProcessorTaskCleanupIdempotencyKeys)
+	mux.HandleFunc(TaskRefreshFXRates, rtp.ProcessorTaskRefreshFXRates)
+	mux.HandleFunc(TaskExpireHolds, rtp.ProcessorTaskExpireHolds)
+	mux.HandleFunc(TaskRunScheduledTransfers, rtp.ProcessorTaskRunScheduledTransfers)
+	mux.HandleFunc(TaskNotifyScheduledTransferFailed, rtp.ProcessorTaskNotifyScheduledTransferFailed)
+	mux.HandleFunc(TaskReconcileLedger, rtp.ProcessorTaskReconcileLedger)
+	mux.HandleFunc(TaskSendReconciliationAlert, rtp.ProcessorTaskSendReconciliationAlert)
+	mux.HandleFunc(TaskSendMonthlyStatements, rtp.ProcessorTaskSendMonthlyStatements)
+	mux.HandleFunc(TaskSendStatement, rtp.ProcessorTaskSendStatement)
+	mux.HandleFunc(TaskPurgeExpiredSessions, rtp.ProcessorTaskPurgeExpiredSessions)
 	return rtp.server.Start(mux)
 }
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+// for periodic tasks
+type TaskScheduler interface {
+	Run() error
+	SchedulerTaskCleanupIdempotencyKeys(
+		cronspec string,
+		payload PayloadCleanupIdempotencyKeys,
+		opts ...asynq.Option,
+	) error
+	SchedulerTaskRefreshFXRates(
+		cronspec string,
+		payload PayloadRefreshFXRates,
+		opts ...asynq.Option,
+	) error
+	SchedulerTaskExpireHolds(
+		cronspec string,
+		payload PayloadExpireHolds,
+		opts ...asynq.Option,
+	) error
+	SchedulerTaskRunScheduledTransfers(
+		cronspec string,
+		payload PayloadRunScheduledTransfers,
+		opts ...asynq.Option,
+	) error
+	SchedulerTaskReconcileLedger(
+		cronspec string,
+		payload PayloadReconcileLedger,
+		opts ...asynq.Option,
+	) error
+	SchedulerTaskSendMonthlyStatements(
+		cronspec string,
+		payload PayloadSendMonthlyStatements,
+		opts ...asynq.Option,
+	) error
+	SchedulerTaskPurgeExpiredSessions(
+		cronspec string,
+		payload PayloadPurgeExpiredSessions,
+		opts ...asynq.Option,
+	) error
+}
+
+type RedisTaskScheduler struct {
+	scheduler *asynq.Scheduler
+}
+
+func NewRedisTaskScheduler(redisOpts asynq.RedisClientOpt) TaskScheduler {
+	scheduler := asynq.NewScheduler(
+		redisOpts,
+		&asynq.SchedulerOpts{
+			Location: time.UTC,
+			EnqueueErrorHandler: func(task *asynq.Task, opts []asynq.Option, err error) {
+				log.Error().Err(err).Str("type", task.Type()).
+					Bytes("payload", task.Payload()).Msg("task scheduling failed")
+			},
+			Logger: NewLogger(),
+		},
+	)
+
+	return &RedisTaskScheduler{
+		scheduler: scheduler,
+	}
+}
+
+func (rts *RedisTaskScheduler) Run() error {
+	return rts.scheduler.Run()
+}
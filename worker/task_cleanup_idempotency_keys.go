@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskCleanupIdempotencyKeys = "task:cleanup_idempotency_keys"
+
+type PayloadCleanupIdempotencyKeys struct {
+	TTL time.Duration `json:"ttl"`
+}
+
+// task scheduler
+func (rts *RedisTaskScheduler) SchedulerTaskCleanupIdempotencyKeys(
+	cronspec string,
+	payload PayloadCleanupIdempotencyKeys,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskCleanupIdempotencyKeys, jsonPayload, opts...)
+	entryID, err := rts.scheduler.Register(cronspec, task)
+	if err != nil {
+		return fmt.Errorf("failed to register task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Str("cronspec", cronspec).
+		Str("entry_id", entryID).Msg("task scheduled")
+	return nil
+}
+
+// task processor
+func (rtp RedisTaskProcessor) ProcessorTaskCleanupIdempotencyKeys(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadCleanupIdempotencyKeys
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	deleted, err := rtp.store.DeleteIdempotencyKeysBefore(ctx, time.Now().Add(-payload.TTL))
+	if err != nil {
+		return fmt.Errorf("failed to delete idempotency keys: %w", err)
+	}
+
+	log.Info().Int64("deleted", deleted).Msg("task processed")
+	return nil
+}
@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskExpireHolds = "task:expire_holds"
+
+type PayloadExpireHolds struct{}
+
+// task scheduler
+func (rts *RedisTaskScheduler) SchedulerTaskExpireHolds(
+	cronspec string,
+	payload PayloadExpireHolds,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskExpireHolds, jsonPayload, opts...)
+	entryID, err := rts.scheduler.Register(cronspec, task)
+	if err != nil {
+		return fmt.Errorf("failed to register task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Str("cronspec", cronspec).
+		Str("entry_id", entryID).Msg("task scheduled")
+	return nil
+}
+
+// task processor
+func (rtp RedisTaskProcessor) ProcessorTaskExpireHolds(ctx context.Context, task *asynq.Task) error {
+	// expired holds already stop reserving funds, this only settles their status
+	expired, err := rtp.store.ExpireHolds(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to expire holds: %w", err)
+	}
+
+	log.Info().Int64("expired", expired).Msg("task processed")
+	return nil
+}
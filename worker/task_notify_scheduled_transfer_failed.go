@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskNotifyScheduledTransferFailed = "task:notify_scheduled_transfer_failed"
+
+type PayloadNotifyScheduledTransferFailed struct {
+	RunID int64 `json:"run_id"`
+}
+
+// task distributor
+func (rtd RedisTaskDistributor) DistributorTaskNotifyScheduledTransferFailed(
+	ctx context.Context,
+	payload PayloadNotifyScheduledTransferFailed,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskNotifyScheduledTransferFailed, jsonPayload, opts...)
+	info, err := rtd.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("task enqueued")
+	return nil
+}
+
+// task processor
+func (rtp RedisTaskProcessor) ProcessorTaskNotifyScheduledTransferFailed(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadNotifyScheduledTransferFailed
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	run, err := rtp.store.GetScheduledTransferRun(ctx, payload.RunID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("scheduled transfer run not found: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get scheduled transfer run: %w", err)
+	}
+
+	schedule, err := rtp.store.GetScheduledTransfer(ctx, run.ScheduledTransferID)
+	if err != nil {
+		return fmt.Errorf("failed to get scheduled transfer: %w", err)
+	}
+
+	user, err := rtp.store.GetUser(ctx, schedule.Owner)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	amount := utils.NewMoney(schedule.Amount, schedule.Currency)
+	subject := "Scheduled transfer failed"
+	content := fmt.Sprintf(`Hello %s,<br/>
+	your scheduled transfer #%d of %s from account #%d to account #%d due at %s could not be made.<br/>
+	Reason: %s<br/>
+	Later runs of the schedule are not affected.`,
+		html.EscapeString(user.FullName), schedule.ID, amount, schedule.FromAccountID, schedule.ToAccountID,
+		run.ScheduledFor.UTC().Format(time.RFC1123), html.EscapeString(run.FailureReason.String))
+
+	err = rtp.mailer.SendEmail(subject, content, []string{user.Email}, nil, nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to send failure email: %w", err)
+	}
+
+	log.Info().Str("username", user.Username).Int64("scheduled_transfer_id", schedule.ID).
+		Int64("run_id", run.ID).Msg("task processed")
+	return nil
+}
@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskPurgeExpiredSessions = "task:purge_expired_sessions"
+
+type PayloadPurgeExpiredSessions struct{}
+
+// task scheduler
+func (rts *RedisTaskScheduler) SchedulerTaskPurgeExpiredSessions(
+	cronspec string,
+	payload PayloadPurgeExpiredSessions,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskPurgeExpiredSessions, jsonPayload, opts...)
+	entryID, err := rts.scheduler.Register(cronspec, task)
+	if err != nil {
+		return fmt.Errorf("failed to register task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Str("cronspec", cronspec).
+		Str("entry_id", entryID).Msg("task scheduled")
+	return nil
+}
+
+// task processor, blocked sessions are kept until they expire
+func (rtp RedisTaskProcessor) ProcessorTaskPurgeExpiredSessions(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadPurgeExpiredSessions
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	deleted, err := rtp.store.DeleteExpiredSessions(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+
+	log.Info().Int64("deleted", deleted).Msg("task processed")
+	return nil
+}
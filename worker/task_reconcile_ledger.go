@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dxtym/bankrupt/reconcile"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskReconcileLedger = "task:reconcile_ledger"
+
+type PayloadReconcileLedger struct{}
+
+// task scheduler
+func (rts *RedisTaskScheduler) SchedulerTaskReconcileLedger(
+	cronspec string,
+	payload PayloadReconcileLedger,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskReconcileLedger, jsonPayload, opts...)
+	entryID, err := rts.scheduler.Register(cronspec, task)
+	if err != nil {
+		return fmt.Errorf("failed to register task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Str("cronspec", cronspec).
+		Str("entry_id", entryID).Msg("task scheduled")
+	return nil
+}
+
+// task processor
+func (rtp RedisTaskProcessor) ProcessorTaskReconcileLedger(ctx context.Context, task *asynq.Task) error {
+	reconciler := reconcile.NewReconciler(rtp.store, reconcile.DefaultBatchSize)
+	run, _, err := reconciler.Run(ctx, reconcile.SourceTask)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile ledger: %w", err)
+	}
+
+	if run.CriticalFindings > 0 {
+		payload := PayloadSendReconciliationAlert{RunID: run.ID}
+		opts := []asynq.Option{
+			asynq.MaxRetry(10),
+			asynq.Queue(QueueCritical),
+		}
+		if err := rtp.distributor.DistributorTaskSendReconciliationAlert(ctx, payload, opts...); err != nil {
+			return fmt.Errorf("failed to distribute task: %w", err)
+		}
+	}
+
+	log.Info().Int64("run_id", run.ID).Str("status", run.Status).Msg("task processed")
+	return nil
+}
@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dxtym/bankrupt/fx"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskRefreshFXRates = "task:refresh_fx_rates"
+
+type PayloadRefreshFXRates struct{}
+
+// task scheduler
+func (rts *RedisTaskScheduler) SchedulerTaskRefreshFXRates(
+	cronspec string,
+	payload PayloadRefreshFXRates,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskRefreshFXRates, jsonPayload, opts...)
+	entryID, err := rts.scheduler.Register(cronspec, task)
+	if err != nil {
+		return fmt.Errorf("failed to register task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Str("cronspec", cronspec).
+		Str("entry_id", entryID).Msg("task scheduled")
+	return nil
+}
+
+// task processor
+func (rtp RedisTaskProcessor) ProcessorTaskRefreshFXRates(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadRefreshFXRates
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	rates, err := rtp.rateProvider.FetchRates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch rates: %w", err)
+	}
+
+	imported, err := fx.ImportRates(ctx, rtp.store, rtp.rateProvider.Name(), rates)
+	if err != nil {
+		return fmt.Errorf("failed to import rates: %w", err)
+	}
+
+	log.Info().Str("provider", rtp.rateProvider.Name()).
+		Int("imported", imported).Msg("task processed")
+	return nil
+}
@@ -0,0 +1,128 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/limits"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/hibiken/asynq"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskRunScheduledTransfers = "task:run_scheduled_transfers"
+
+// due schedules picked per run, the rest wait for the next tick
+const scheduledTransfersBatch = 100
+
+type PayloadRunScheduledTransfers struct{}
+
+// task scheduler
+func (rts *RedisTaskScheduler) SchedulerTaskRunScheduledTransfers(
+	cronspec string,
+	payload PayloadRunScheduledTransfers,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskRunScheduledTransfers, jsonPayload, opts...)
+	entryID, err := rts.scheduler.Register(cronspec, task)
+	if err != nil {
+		return fmt.Errorf("failed to register task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Str("cronspec", cronspec).
+		Str("entry_id", entryID).Msg("task scheduled")
+	return nil
+}
+
+// task processor
+func (rtp RedisTaskProcessor) ProcessorTaskRunScheduledTransfers(ctx context.Context, task *asynq.Task) error {
+	schedules, err := rtp.store.ListDueScheduledTransfers(ctx, db.ListDueScheduledTransfersParams{
+		Now:        time.Now(),
+		LimitCount: scheduledTransfersBatch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list due scheduled transfers: %w", err)
+	}
+
+	failed := 0
+	for _, schedule := range schedules {
+		run, err := rtp.runScheduledTransfer(ctx, schedule)
+		if errors.Is(err, db.ErrScheduledRunRecorded) {
+			// overlapping tick, the transfer itself was replayed by its idempotency key
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to run scheduled transfer [%d]: %w", schedule.ID, err)
+		}
+		if run.Status == db.ScheduledRunFailed {
+			failed++
+		}
+	}
+
+	log.Info().Int("runs", len(schedules)).Int("failed", failed).Msg("task processed")
+	return nil
+}
+
+// run one occurrence, business failures are recorded instead of retried
+func (rtp RedisTaskProcessor) runScheduledTransfer(ctx context.Context, schedule db.ScheduledTransfer) (db.ScheduledTransferRun, error) {
+	arg := db.RecordScheduledRunTxParams{
+		ScheduledTransferID: schedule.ID,
+		ScheduledFor:        schedule.NextRunAt.Time,
+		OnFailed: func(run db.ScheduledTransferRun) db.OutboxTask {
+			return db.OutboxTask{
+				Type:     TaskNotifyScheduledTransferFailed,
+				Payload:  PayloadNotifyScheduledTransferFailed{RunID: run.ID},
+				Queue:    QueueDefault,
+				MaxRetry: 10,
+			}
+		},
+	}
+
+	result, err := rtp.store.TransferTx(ctx, db.TransferTxParams{
+		FromAccountId: schedule.FromAccountID,
+		ToAccountId:   schedule.ToAccountID,
+		Amount:        utils.NewMoney(schedule.Amount, schedule.Currency),
+		Idempotency:   db.ScheduledRunIdempotency(schedule, arg.ScheduledFor),
+	})
+	switch {
+	case err == nil:
+		arg.TransferID = result.Transfer.ID
+	case isScheduledRunFailure(err):
+		arg.FailureReason = err.Error()
+		arg.Pause = isScheduleBroken(err)
+	default:
+		return db.ScheduledTransferRun{}, err
+	}
+
+	return rtp.store.RecordScheduledRunTx(ctx, arg)
+}
+
+// errors that another attempt of the same occurrence won't fix
+func isScheduledRunFailure(err error) bool {
+	if errors.Is(err, db.ErrInsufficientFunds) || errors.Is(err, db.ErrIdempotencyKeyReused) || errors.Is(err, limits.ErrExceeded) {
+		return true
+	}
+	return isScheduleBroken(err)
+}
+
+// failures every later occurrence would hit too, like a closed account
+func isScheduleBroken(err error) bool {
+	if errors.Is(err, sql.ErrNoRows) || errors.Is(err, utils.ErrCurrencyMismatch) {
+		return true
+	}
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "foreign_key_violation" {
+		return true
+	}
+	return false
+}
@@ -5,7 +5,12 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"html"
+	"net/url"
+	"time"
 
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/utils"
 	"github.com/hibiken/asynq"
 	"github.com/rs/zerolog/log"
 )
@@ -16,6 +21,17 @@ type PayloadSendEmail struct {
 	Username string `json:"username"`
 }
 
+// outbox task that sends a fresh verification link to the user's current address
+func VerifyEmailTask(username string) db.OutboxTask {
+	return db.OutboxTask{
+		Type:     TaskSendEmail,
+		Payload:  PayloadSendEmail{Username: username},
+		Queue:    QueueCritical,
+		MaxRetry: 10,
+		Timeout:  10 * time.Second,
+	}
+}
+
 // task distributor
 func (rtd RedisTaskDistributor) DistributorTaskSendEmail(
 	ctx context.Context,
@@ -52,8 +68,39 @@ func (rtp RedisTaskProcessor) ProcessorTaskSendEmail(ctx context.Context, task *
 		}
 		return fmt.Errorf("failed to get user: %w", err)
 	}
+	if user.IsEmailVerified {
+		log.Info().Str("username", user.Username).Msg("email already verified")
+		return nil
+	}
+
+	secretCode, err := utils.NewSecretCode()
+	if err != nil {
+		return err
+	}
+
+	verifyEmail, err := rtp.store.CreateVerifyEmail(ctx, db.CreateVerifyEmailParams{
+		Username:   user.Username,
+		Email:      user.Email,
+		SecretCode: secretCode,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create verify email: %w", err)
+	}
+
+	verifyURL := fmt.Sprintf("%s?email_id=%d&secret_code=%s",
+		rtp.verifyURL, verifyEmail.ID, url.QueryEscape(verifyEmail.SecretCode))
+	subject := "Welcome to Bankrupt"
+	content := fmt.Sprintf(`Hello %s,<br/>
+	thank you for registering with us!<br/>
+	Please <a href="%s">click here</a> to verify your email address.<br/>
+	The link expires at %s.`,
+		html.EscapeString(user.FullName), verifyURL, verifyEmail.ExpiredAt.UTC().Format(time.RFC1123))
+
+	err = rtp.mailer.SendEmail(subject, content, []string{verifyEmail.Email}, nil, nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to send verify email: %w", err)
+	}
 
-	// todo: send email to user
-	log.Info().Str("username", user.Username).Str("email", user.Email).Msg("task processed")
+	log.Info().Str("username", user.Username).Int64("verify_email_id", verifyEmail.ID).Msg("task processed")
 	return nil
 }
@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/statement"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendMonthlyStatements = "task:send_monthly_statements"
+
+// accounts read per query
+const statementAccountsBatch = 500
+
+type PayloadSendMonthlyStatements struct{}
+
+// task scheduler
+func (rts *RedisTaskScheduler) SchedulerTaskSendMonthlyStatements(
+	cronspec string,
+	payload PayloadSendMonthlyStatements,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskSendMonthlyStatements, jsonPayload, opts...)
+	entryID, err := rts.scheduler.Register(cronspec, task)
+	if err != nil {
+		return fmt.Errorf("failed to register task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Str("cronspec", cronspec).
+		Str("entry_id", entryID).Msg("task scheduled")
+	return nil
+}
+
+// task processor
+func (rtp RedisTaskProcessor) ProcessorTaskSendMonthlyStatements(ctx context.Context, task *asynq.Task) error {
+	from, to := statement.PreviousMonth(time.Now().UTC())
+
+	var afterID int64
+	var enqueued int
+	for {
+		accounts, err := rtp.store.ListStatementAccounts(ctx, db.ListStatementAccountsParams{
+			AfterID:       afterID,
+			CreatedBefore: to,
+			LimitCount:    statementAccountsBatch,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list statement accounts: %w", err)
+		}
+
+		for _, account := range accounts {
+			payload := PayloadSendStatement{
+				AccountID:   account.ID,
+				PeriodStart: from,
+				PeriodEnd:   to,
+			}
+			// one task per account and month, so a retried fan-out doesn't email twice
+			opts := []asynq.Option{
+				asynq.MaxRetry(10),
+				asynq.Queue(QueueDefault),
+				asynq.TaskID(fmt.Sprintf("statement:%d:%s", account.ID, from.Format("2006-01"))),
+			}
+			err := rtp.distributor.DistributorTaskSendStatement(ctx, payload, opts...)
+			if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+				return fmt.Errorf("failed to distribute task: %w", err)
+			}
+			enqueued++
+		}
+
+		if len(accounts) < statementAccountsBatch {
+			break
+		}
+		afterID = accounts[len(accounts)-1].ID
+	}
+
+	log.Info().Time("period_start", from).Int("accounts", enqueued).Msg("task processed")
+	return nil
+}
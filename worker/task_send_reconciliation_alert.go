@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendReconciliationAlert = "task:send_reconciliation_alert"
+
+type PayloadSendReconciliationAlert struct {
+	RunID int64 `json:"run_id"`
+}
+
+// task distributor
+func (rtd RedisTaskDistributor) DistributorTaskSendReconciliationAlert(
+	ctx context.Context,
+	payload PayloadSendReconciliationAlert,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskSendReconciliationAlert, jsonPayload, opts...)
+	info, err := rtd.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("task enqueued")
+	return nil
+}
+
+// task processor
+func (rtp RedisTaskProcessor) ProcessorTaskSendReconciliationAlert(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendReconciliationAlert
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	run, err := rtp.store.GetReconciliationRun(ctx, payload.RunID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("reconciliation run not found: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get reconciliation run: %w", err)
+	}
+
+	// todo: page whoever is on call
+	log.Error().Int64("run_id", run.ID).Str("source", run.Source).Str("status", run.Status).
+		Int32("critical_findings", run.CriticalFindings).RawJSON("findings", run.Findings).
+		Msg("ledger reconciliation found critical issues")
+	return nil
+}
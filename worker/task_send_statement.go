@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	db "github.com/dxtym/bankrupt/db/sqlc"
+	"github.com/dxtym/bankrupt/mail"
+	"github.com/dxtym/bankrupt/statement"
+	"github.com/dxtym/bankrupt/utils"
+	"github.com/hibiken/asynq"
+	"github.com/rs/zerolog/log"
+)
+
+const TaskSendStatement = "task:send_statement"
+
+type PayloadSendStatement struct {
+	AccountID   int64     `json:"account_id"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+}
+
+// task distributor
+func (rtd RedisTaskDistributor) DistributorTaskSendStatement(
+	ctx context.Context,
+	payload PayloadSendStatement,
+	opts ...asynq.Option,
+) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskSendStatement, jsonPayload, opts...)
+	info, err := rtd.client.EnqueueContext(ctx, task)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Info().Str("type", task.Type()).Bytes("payload", task.Payload()).
+		Str("queue", info.Queue).Int("max_retry", info.MaxRetry).Msg("task enqueued")
+	return nil
+}
+
+// task processor
+func (rtp RedisTaskProcessor) ProcessorTaskSendStatement(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadSendStatement
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", asynq.SkipRetry)
+	}
+
+	account, err := rtp.store.GetAccount(ctx, payload.AccountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("account not found: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+
+	st, err := statement.Build(ctx, rtp.store, account, payload.PeriodStart, payload.PeriodEnd)
+	if err != nil {
+		return fmt.Errorf("failed to build statement: %w", err)
+	}
+
+	record, err := rtp.store.CreateStatement(ctx, db.CreateStatementParams{
+		AccountID:      account.ID,
+		PeriodStart:    st.From,
+		PeriodEnd:      st.To,
+		OpeningBalance: st.OpeningBalance,
+		ClosingBalance: st.ClosingBalance,
+		EntryCount:     int32(len(st.Entries)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create statement: %w", err)
+	}
+	if record.EmailedAt.Valid {
+		log.Info().Int64("statement_id", record.ID).Msg("statement already emailed")
+		return nil
+	}
+
+	user, err := rtp.store.GetUser(ctx, account.Owner)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	var attachments []mail.Attachment
+	for _, format := range []string{statement.FormatPDF, statement.FormatCSV} {
+		doc, err := statement.Render(st, format)
+		if err != nil {
+			return fmt.Errorf("failed to render statement: %w", err)
+		}
+		attachments = append(attachments, mail.Attachment{
+			Filename:    doc.Filename,
+			ContentType: doc.ContentType,
+			Content:     doc.Content,
+		})
+	}
+
+	subject := fmt.Sprintf("Your %s statement for account %d", st.From.Format("January 2006"), account.ID)
+	content := fmt.Sprintf(`Hello %s,<br/>
+	your statement for account %d is attached.<br/>
+	Opening balance %s, closing balance %s.`,
+		user.FullName, account.ID,
+		utils.NewMoney(st.OpeningBalance, account.Currency),
+		utils.NewMoney(st.ClosingBalance, account.Currency),
+	)
+
+	err = rtp.mailer.SendEmail(subject, content, []string{user.Email}, nil, nil, nil, attachments)
+	if err != nil {
+		return fmt.Errorf("failed to send statement email: %w", err)
+	}
+
+	if _, err := rtp.store.MarkStatementEmailed(ctx, record.ID); err != nil {
+		return fmt.Errorf("failed to mark statement emailed: %w", err)
+	}
+
+	log.Info().Str("username", user.Username).Int64("statement_id", record.ID).Msg("task processed")
+	return nil
+}